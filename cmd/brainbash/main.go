@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	_ "time/tzdata" // the runtime image has no zoneinfo; user timezones must still load
 
 	"brainbash_backend/config"
 	"brainbash_backend/internal/app"
@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 // GlobalConf is the interface that application configs must implement
 type GlobalConf interface {
 	GetStaticConfig() interface{}
@@ -31,11 +33,256 @@ type StaticConfig struct {
 	Auth struct {
 		JWTSecret      string `mapstructure:"jwt_secret"`
 		GoogleClientID string `mapstructure:"google_client_id"`
+		AdminUserIDs   string `mapstructure:"admin_user_ids"` // comma-separated user_ids allowed on /api/admin/*
 	} `mapstructure:"auth"`
 	Mongo struct {
 		URI      string `mapstructure:"uri"`
 		Database string `mapstructure:"database"`
 	} `mapstructure:"mongo"`
+	Validation  ValidationConfig `mapstructure:"validation"`
+	Norms       NormsConfig      `mapstructure:"norms"`
+	Profile     ProfileConfig    `mapstructure:"profile"`
+	Idempotency struct {
+		TTL         time.Duration `mapstructure:"ttl"`          // how long a result is replayable
+		LockTimeout time.Duration `mapstructure:"lock_timeout"` // after this an unfinished submission may be retried
+	} `mapstructure:"idempotency"`
+	Batch           BatchConfig           `mapstructure:"batch"`
+	Streak          StreakConfig          `mapstructure:"streak"`
+	Achievements    AchievementsConfig    `mapstructure:"achievements"`
+	XP              XPConfig              `mapstructure:"xp"`
+	Recommendations RecommendationsConfig `mapstructure:"recommendations"`
+	Goals           GoalsConfig           `mapstructure:"goals"`
+	Digest          DigestConfig          `mapstructure:"digest"`
+	Notifications   NotificationsConfig   `mapstructure:"notifications"`
+	Social          SocialConfig          `mapstructure:"social"`
+	Duels           DuelsConfig           `mapstructure:"duels"`
+	Matches         MatchesConfig         `mapstructure:"matches"`
+	Ratings         RatingsConfig         `mapstructure:"ratings"`
+	Tournaments     TournamentsConfig     `mapstructure:"tournaments"`
+	Groups          GroupsConfig          `mapstructure:"groups"`
+	Tenants         TenantsConfig         `mapstructure:"tenants"`
+}
+
+// TenantsConfig lists the partner organisations served alongside the default tenant. Each tenant's
+// users, scores and leaderboards are isolated from every other tenant's.
+type TenantsConfig struct {
+	Header            string         `mapstructure:"header"` // request header naming the tenant; takes precedence over the host
+	List              []TenantConfig `mapstructure:"list"`
+	SettingsCacheTTL  time.Duration  `mapstructure:"settings_cache_ttl"` // how long settings stored in Mongo are cached
+	SettingsSync      time.Duration  `mapstructure:"settings_sync"`      // how often settings changed by other instances are reloaded
+	RetentionInterval time.Duration  `mapstructure:"retention_interval"` // how often sessions past a tenant's retention are deleted
+}
+
+// TenantConfig describes one partner tenant.
+type TenantConfig struct {
+	ID              string            `mapstructure:"id"`
+	Name            string            `mapstructure:"name"`
+	Hosts           []string          `mapstructure:"hosts"`             // hostnames resolving to the tenant
+	GoogleClientIDs string            `mapstructure:"google_client_ids"` // comma-separated; empty accepts auth.google_client_id
+	CORSOrigins     []string          `mapstructure:"cors_origins"`
+	GameTypes       []string          `mapstructure:"game_types"`     // enabled game types; empty enables all
+	GameLabels      map[string]string `mapstructure:"game_labels"`    // display names overriding the default labels
+	AdminUserIDs    string            `mapstructure:"admin_user_ids"` // comma-separated user_ids allowed to change the tenant's settings
+	Leaderboards    string            `mapstructure:"leaderboards"`   // public (default), group or off
+	Retention       time.Duration     `mapstructure:"retention"`      // sessions older than this are deleted; 0 keeps them
+}
+
+// GroupsConfig limits groups (classrooms, teams) and their membership.
+type GroupsConfig struct {
+	MaxMembers       int `mapstructure:"max_members"`
+	MaxGroupsPerUser int `mapstructure:"max_groups_per_user"` // groups a user can own or belong to
+}
+
+// TournamentsConfig controls admin-run tournaments.
+type TournamentsConfig struct {
+	MaxRounds        int           `mapstructure:"max_rounds"`
+	MinRoundDuration time.Duration `mapstructure:"min_round_duration"`
+	AdvanceInterval  time.Duration `mapstructure:"advance_interval"` // how often tournaments are started and rounds settled
+}
+
+// RatingsConfig controls Glicko-2 skill ratings per game type.
+type RatingsConfig struct {
+	Tau                  float64       `mapstructure:"tau"`                   // volatility constraint
+	Period               time.Duration `mapstructure:"period"`                // inactivity per step of deviation growth
+	ProvisionalDeviation float64       `mapstructure:"provisional_deviation"` // less certain ratings are kept off the leaderboard
+	ChallengePlacements  bool          `mapstructure:"challenge_placements"`  // rate daily challenges as multi-player matches
+	ChallengeNeighbors   int           `mapstructure:"challenge_neighbors"`   // placements above and below each player rated against
+	ChallengeMaxPlayers  int64         `mapstructure:"challenge_max_players"` // top attempts rated per challenge
+	ChallengeInterval    time.Duration `mapstructure:"challenge_interval"`    // how often finished challenges are rated
+}
+
+// MatchesConfig controls real-time matches: matchmaking and the pace of questions.
+type MatchesConfig struct {
+	Questions           int           `mapstructure:"questions"`            // per match
+	QuestionTime        time.Duration `mapstructure:"question_time"`        // answer window per question
+	StartDelay          time.Duration `mapstructure:"start_delay"`          // countdown before the first question
+	MaxPlayers          int           `mapstructure:"max_players"`          // largest match a player can queue for
+	MatchmakingInterval time.Duration `mapstructure:"matchmaking_interval"` // how often queues are re-checked
+	MaxQueueWait        time.Duration `mapstructure:"max_queue_wait"`       // players waiting longer are sent away
+	SkillWindow         float64       `mapstructure:"skill_window"`         // rating gap accepted straight away
+	SkillWidening       float64       `mapstructure:"skill_widening"`       // added to the gap per second waited
+}
+
+// DuelsConfig controls head-to-head duels between friends.
+type DuelsConfig struct {
+	TTL            time.Duration `mapstructure:"ttl"`             // time both players have to play, from creation
+	ExpiryInterval time.Duration `mapstructure:"expiry_interval"` // how often expired duels are settled
+}
+
+// SocialConfig limits the social graph and builds invite links from friend codes.
+type SocialConfig struct {
+	MaxFriends   int    `mapstructure:"max_friends"`
+	MaxFollowing int    `mapstructure:"max_following"`
+	InviteURL    string `mapstructure:"invite_url"` // fmt pattern with one %s for the friend code; empty disables links
+}
+
+// NotificationsConfig controls the notification outbox, its dispatcher and the delivery channels.
+// A channel is available when its settings are present (log always is).
+type NotificationsConfig struct {
+	DispatchInterval time.Duration                   `mapstructure:"dispatch_interval"` // how often the outbox is drained
+	BatchSize        int                             `mapstructure:"batch_size"`        // notifications sent per run
+	MaxAttempts      int                             `mapstructure:"max_attempts"`      // deliveries tried before giving up
+	RetryBackoff     time.Duration                   `mapstructure:"retry_backoff"`     // first retry delay; doubles per attempt
+	SendTimeout      time.Duration                   `mapstructure:"send_timeout"`      // per delivery; also how long a claim is held
+	DefaultChannels  []string                        `mapstructure:"default_channels"`  // for users without saved preferences
+	StreakRisk       StreakRiskConfig                `mapstructure:"streak_risk"`
+	Templates        map[string]NotificationTemplate `mapstructure:"templates"` // per kind; overrides the built-in text
+	File             struct {
+		Path string `mapstructure:"path"`
+	} `mapstructure:"file"`
+	Webhook struct {
+		URL string `mapstructure:"url"`
+	} `mapstructure:"webhook"`
+	Push struct {
+		GatewayURL string `mapstructure:"gateway_url"` // receives webhook-style posts and resolves the user's devices
+	} `mapstructure:"push"`
+	Email struct {
+		Host     string `mapstructure:"host"`
+		Port     int    `mapstructure:"port"`
+		Username string `mapstructure:"username"`
+		Password string `mapstructure:"password"`
+		From     string `mapstructure:"from"`
+	} `mapstructure:"email"`
+}
+
+// StreakRiskConfig controls the reminder sent to users who have not played yet today.
+type StreakRiskConfig struct {
+	CheckInterval time.Duration `mapstructure:"check_interval"`
+	Hour          int           `mapstructure:"hour"`       // local hour from which the reminder is sent
+	MinStreak     int           `mapstructure:"min_streak"` // shorter streaks are not worth a reminder
+}
+
+// NotificationTemplate overrides the subject and/or body of a notification kind (text/template syntax).
+type NotificationTemplate struct {
+	Subject string `mapstructure:"subject"`
+	Body    string `mapstructure:"body"`
+}
+
+// GoalsConfig limits per-user goals on /api/user/goals.
+type GoalsConfig struct {
+	MaxPerUser int `mapstructure:"max_per_user"`
+}
+
+// DigestConfig schedules the weekly digest job. Digests cover Monday to Monday (UTC) and are
+// generated on the first run after the week ends.
+type DigestConfig struct {
+	Interval time.Duration `mapstructure:"interval"` // how often the job checks for a finished week
+}
+
+// RecommendationsConfig selects the recommender behind /api/user/recommendations. When an experiment
+// has variants, users are split between recommenders by weight instead.
+type RecommendationsConfig struct {
+	PlanSize    int    `mapstructure:"plan_size"`   // games per daily plan
+	Recommender string `mapstructure:"recommender"` // used outside experiments
+	Experiment  struct {
+		Name     string         `mapstructure:"name"`     // salts the user split; rename to reshuffle users
+		Variants map[string]int `mapstructure:"variants"` // recommender name -> relative weight
+	} `mapstructure:"experiment"`
+}
+
+// XPConfig controls the XP earned per session and the level curve.
+// Level 1 starts at 0 XP; reaching level L+1 takes level_base * level_growth^(L-1) more XP.
+type XPConfig struct {
+	PerSession        int64   `mapstructure:"per_session"`          // flat XP for finishing a session
+	PerQuestion       float64 `mapstructure:"per_question"`         // effort: XP per question answered
+	ScoreFactor       float64 `mapstructure:"score_factor"`         // XP per session score point (0–100)
+	PersonalBestBonus int64   `mapstructure:"personal_best_bonus"`  // beating the game type's high_score
+	StreakBonusPerDay int64   `mapstructure:"streak_bonus_per_day"` // per day of the current streak
+	MaxStreakBonus    int64   `mapstructure:"max_streak_bonus"`
+	LevelBase         int64   `mapstructure:"level_base"`
+	LevelGrowth       float64 `mapstructure:"level_growth"`
+}
+
+// AchievementsConfig declares the achievements players can unlock.
+type AchievementsConfig struct {
+	Rules []AchievementRule `mapstructure:"rules"`
+}
+
+// AchievementRule declares one achievement and the condition that unlocks it.
+// Kind is one of accuracy, score, sessions_total, sessions_in_window, leaderboard_rank, domains_played.
+type AchievementRule struct {
+	ID          string        `mapstructure:"id"`
+	Name        string        `mapstructure:"name"`
+	Description string        `mapstructure:"description"`
+	Kind        string        `mapstructure:"kind"`
+	GameType    string        `mapstructure:"game_type"` // restricts the rule to one game type; empty matches all
+	Threshold   float64       `mapstructure:"threshold"` // minimum value (leaderboard_rank: worst qualifying rank)
+	Window      time.Duration `mapstructure:"window"`    // sessions_in_window only
+}
+
+// StreakConfig controls how streak-freeze tokens are earned.
+type StreakConfig struct {
+	FreezeEvery     int `mapstructure:"freeze_every"`      // a token is earned every this many streak days
+	MaxFreezeTokens int `mapstructure:"max_freeze_tokens"` // tokens beyond this are not earned
+}
+
+// BatchConfig bounds offline batch submissions on /api/game/results/batch.
+type BatchConfig struct {
+	MaxSessions   int           `mapstructure:"max_sessions"`    // sessions accepted per request
+	MaxAge        time.Duration `mapstructure:"max_age"`         // oldest accepted played_at
+	MaxFutureSkew time.Duration `mapstructure:"max_future_skew"` // tolerated client clock drift ahead of the server
+}
+
+// ProfileConfig controls the cognitive profile and the composite overall_score built on it.
+type ProfileConfig struct {
+	HalfLife        time.Duration      `mapstructure:"half_life"`        // age at which a session counts half as much
+	Weights         map[string]float64 `mapstructure:"weights"`          // per game type; missing game types weigh 1
+	RefreshInterval time.Duration      `mapstructure:"refresh_interval"` // how often stored overall scores are re-decayed
+}
+
+// NormsConfig controls the periodic rebuild of per-game-type percentile tables.
+type NormsConfig struct {
+	RebuildInterval time.Duration `mapstructure:"rebuild_interval"`
+	MinSamples      int           `mapstructure:"min_samples"` // smaller populations fall back to the all-ages table
+	AgeBands        []int         `mapstructure:"age_bands"`   // ascending lower bounds of age bands, e.g. [18, 25, 35]
+}
+
+// ValidationConfig holds plausibility limits for submitted question_responses.
+// Default applies to every game type; Games overrides individual fields per game type.
+type ValidationConfig struct {
+	Default GameLimits            `mapstructure:"default"`
+	Games   map[string]GameLimits `mapstructure:"games"`
+	Outlier OutlierConfig         `mapstructure:"outlier"`
+}
+
+// OutlierConfig controls comparison of a new session against the user's own history.
+type OutlierConfig struct {
+	MinHistory      int     `mapstructure:"min_history"`        // sessions needed before outliers are checked
+	HistoryWindow   int     `mapstructure:"history_window"`     // most recent sessions used as the baseline
+	ZThreshold      float64 `mapstructure:"z_threshold"`        // |z| above this flags the session
+	MinScoreStdDev  float64 `mapstructure:"min_score_std_dev"`  // floor for the score std dev (avoids flagging stable players)
+	MinAvgTimeRatio float64 `mapstructure:"min_avg_time_ratio"` // floor for the avgTime std dev, as a fraction of the mean
+}
+
+// GameLimits are the per-game plausibility limits. Zero values mean "inherit from default".
+// Times are in seconds.
+type GameLimits struct {
+	MinQuestions        int     `mapstructure:"min_questions"`         // fewer responses is rejected
+	MaxQuestions        int     `mapstructure:"max_questions"`         // more responses is rejected
+	MaxTimeTaken        float64 `mapstructure:"max_time_taken"`        // a single response above this is rejected
+	SuspiciousTimeTaken float64 `mapstructure:"suspicious_time_taken"` // responses faster than this are humanly implausible
+	SuspiciousFastRatio float64 `mapstructure:"suspicious_fast_ratio"` // share of implausibly fast responses that flags a session
+	MinTimingCV         float64 `mapstructure:"min_timing_cv"`         // coefficient of variation below this flags bot-like timing
 }
 
 type DynamicConfig struct{}
@@ -5,6 +5,7 @@ go 1.25.0
 require (
 	github.com/gin-gonic/gin v1.11.0
 	github.com/golang-jwt/jwt/v5 v5.3.1
+	github.com/gorilla/websocket v1.5.3
 	github.com/spf13/viper v1.21.0
 	go.mongodb.org/mongo-driver/v2 v2.5.0
 	gopkg.in/yaml.v3 v3.0.1
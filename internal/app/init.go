@@ -12,6 +12,7 @@ import (
 	"brainbash_backend/config"
 	appMongo "brainbash_backend/internal/mongo"
 	httpRouter "brainbash_backend/internal/router/http"
+	"brainbash_backend/internal/scheduler"
 )
 
 type App struct {
@@ -34,6 +35,8 @@ func NewApp(cfg *config.AppConfig) (*App, error) {
 func (a *App) Start() error {
 	errCh := make(chan error, 1)
 
+	scheduler.Start()
+
 	go func() {
 		if err := a.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			errCh <- err
@@ -57,6 +60,8 @@ func (a *App) Start() error {
 		return err
 	}
 
+	scheduler.Stop(ctx)
+
 	if err := appMongo.Disconnect(ctx); err != nil {
 		log.Printf("Failed to disconnect MongoDB: %v", err)
 	}
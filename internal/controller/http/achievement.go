@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"brainbash_backend/internal/model/response"
+	"brainbash_backend/internal/service"
+	"brainbash_backend/internal/utils"
+)
+
+// AchievementController serves the authenticated user's achievements.
+type AchievementController struct {
+	achievementService *service.AchievementService
+}
+
+// NewAchievementController creates a new AchievementController.
+func NewAchievementController(achievementService *service.AchievementService) *AchievementController {
+	return &AchievementController{
+		achievementService: achievementService,
+	}
+}
+
+// List handles GET /api/user/achievements. Returns every achievement, locked ones included.
+func (ac *AchievementController) List(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	statuses, err := ac.achievementService.List(c.Request.Context(), userID)
+	if err != nil {
+		log.Printf("Achievements List: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load achievements"})
+		return
+	}
+
+	resp := response.AchievementsResponse{
+		Achievements: make([]response.AchievementResponse, 0, len(statuses)),
+		Total:        len(statuses),
+	}
+	for _, st := range statuses {
+		resp.Achievements = append(resp.Achievements, response.AchievementResponse{
+			ID:          st.ID,
+			Name:        st.Name,
+			Description: st.Description,
+			Unlocked:    st.UnlockedAt != nil,
+			UnlockedAt:  st.UnlockedAt,
+		})
+		if st.UnlockedAt != nil {
+			resp.Unlocked++
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
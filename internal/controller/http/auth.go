@@ -8,21 +8,26 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 
+	"brainbash_backend/internal/model/entity"
 	"brainbash_backend/internal/model/request"
 	"brainbash_backend/internal/model/response"
 	"brainbash_backend/internal/service"
+	"brainbash_backend/internal/tenant"
+	"brainbash_backend/internal/utils"
 )
 
 type AuthController struct {
 	googleAuthService *service.GoogleAuthService
 	userService       *service.UserService
+	xpService         *service.XPService
 	jwtSecret         string
 }
 
-func NewAuthController(googleAuthService *service.GoogleAuthService, userService *service.UserService, jwtSecret string) *AuthController {
+func NewAuthController(googleAuthService *service.GoogleAuthService, userService *service.UserService, xpService *service.XPService, jwtSecret string) *AuthController {
 	return &AuthController{
 		googleAuthService: googleAuthService,
 		userService:       userService,
+		xpService:         xpService,
 		jwtSecret:         jwtSecret,
 	}
 }
@@ -41,7 +46,7 @@ func (ac *AuthController) GoogleLogin(c *gin.Context) {
 
 	switch {
 	case req.IDToken != "":
-		googleUser, err = ac.googleAuthService.VerifyIDToken(req.IDToken)
+		googleUser, err = ac.googleAuthService.VerifyIDToken(c.Request.Context(), req.IDToken)
 	case req.AccessToken != "":
 		googleUser, err = ac.googleAuthService.VerifyAccessToken(req.AccessToken)
 	default:
@@ -71,11 +76,12 @@ func (ac *AuthController) GoogleLogin(c *gin.Context) {
 		}
 	}
 
-	// Generate app JWT with user_id as the subject
+	// Generate app JWT with user_id as the subject, only valid for the tenant it was issued for
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"sub": persistedUser.UserID.Hex(),
-		"iat": time.Now().Unix(),
-		"exp": time.Now().Add(7 * 24 * time.Hour).Unix(),
+		"sub":             persistedUser.UserID.Hex(),
+		"iat":             time.Now().Unix(),
+		"exp":             time.Now().Add(7 * 24 * time.Hour).Unix(),
+		utils.ClaimTenant: requestTenantID(c),
 	})
 
 	tokenString, err := token.SignedString([]byte(ac.jwtSecret))
@@ -85,16 +91,11 @@ func (ac *AuthController) GoogleLogin(c *gin.Context) {
 	}
 
 	// Use stored user details for response (existing or newly created)
+	info := toUserInfo(persistedUser, ac.xpService)
+	info.FirstName, info.LastName = googleUser.GivenName, googleUser.FamilyName
 	c.JSON(http.StatusOK, response.LoginResponse{
 		AccessToken: tokenString,
-		User: response.UserInfo{
-			UserID:    persistedUser.UserID.Hex(),
-			Email:     persistedUser.Email,
-			Name:      persistedUser.Name,
-			Picture:   persistedUser.Picture,
-			FirstName: googleUser.GivenName,
-			LastName:  googleUser.FamilyName,
-		},
+		User:        info,
 	})
 }
 
@@ -130,12 +131,39 @@ func (ac *AuthController) Me(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, response.UserInfo{
-		UserID:  user.UserID.Hex(),
-		Email:   user.Email,
-		Name:    user.Name,
-		Picture: user.Picture,
-	})
+	c.JSON(http.StatusOK, toUserInfo(user, ac.xpService))
+}
+
+// toUserInfo returns the user's details and profile as returned by /auth/google, /auth/me and
+// PATCH /api/user/profile.
+func toUserInfo(user *entity.User, xpService *service.XPService) response.UserInfo {
+	p := xpService.Progress(user.XP)
+	return response.UserInfo{
+		UserID:      user.UserID.Hex(),
+		Email:       user.Email,
+		Name:        user.Name,
+		Picture:     user.Picture,
+		DisplayName: user.PublicName(),
+		AvatarURL:   user.PublicPicture(),
+		BirthYear:   user.BirthYear,
+		Timezone:    user.Timezone,
+		Locale:      user.Locale,
+		Bio:         user.Bio,
+		Level: &response.LevelInfo{
+			Level:        p.Level,
+			XP:           p.XP,
+			IntoLevel:    p.IntoLevel,
+			ForNextLevel: p.ForNextLevel,
+		},
+	}
+}
+
+// requestTenantID returns the id of the request's tenant.
+func requestTenantID(c *gin.Context) string {
+	if t := tenant.FromContext(c.Request.Context()); t != nil {
+		return t.ID
+	}
+	return tenant.DefaultID
 }
 
 func getString(claims jwt.MapClaims, key string) string {
@@ -0,0 +1,206 @@
+package controller
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"brainbash_backend/internal/game"
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/model/request"
+	"brainbash_backend/internal/model/response"
+	"brainbash_backend/internal/service"
+	"brainbash_backend/internal/utils"
+)
+
+const defaultChallengeListLimit = 30
+
+// ChallengeController handles the daily challenge, its leaderboards and past challenges.
+type ChallengeController struct {
+	challengeService *service.ChallengeService
+}
+
+// NewChallengeController creates a new ChallengeController.
+func NewChallengeController(challengeService *service.ChallengeService) *ChallengeController {
+	return &ChallengeController{
+		challengeService: challengeService,
+	}
+}
+
+// Today handles GET /api/challenge/today. Returns today's game type and seed, and the user's attempt if any.
+func (cc *ChallengeController) Today(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	challenge, err := cc.challengeService.Today(c.Request.Context())
+	if err != nil {
+		log.Printf("Challenge Today: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load challenge"})
+		return
+	}
+	attempt, rank, err := cc.challengeService.GetAttempt(c.Request.Context(), challenge.ID, userID)
+	if err != nil {
+		log.Printf("Challenge GetAttempt: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load challenge"})
+		return
+	}
+
+	resp := toChallengeResponse(challenge)
+	if attempt != nil {
+		resp.Attempt = toChallengeAttemptResponse(attempt, nil, rank)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Submit handles POST /api/challenge/today/result. Stores the user's single ranked attempt.
+// Retrying with the same session_id and responses replays the stored attempt.
+func (cc *ChallengeController) Submit(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	var req request.ChallengeResultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "question_responses is required"})
+		return
+	}
+
+	attempt, result, replayed, err := cc.challengeService.Submit(c.Request.Context(), userID, req)
+	if err != nil {
+		log.Printf("Challenge Submit: %v", err)
+		switch {
+		case errors.Is(err, service.ErrChallengeAttempted), errors.Is(err, service.ErrSubmissionInProgress):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrChallengeNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	var rank int64
+	if !attempt.Flagged {
+		if _, rank, err = cc.challengeService.GetAttempt(c.Request.Context(), attempt.ChallengeID, userID); err != nil {
+			log.Printf("Challenge GetAttempt: %v", err)
+		}
+	}
+	if replayed {
+		c.Header(idempotentReplayedHeader, "true")
+	}
+	c.JSON(http.StatusOK, toChallengeAttemptResponse(attempt, result, rank))
+}
+
+// List handles GET /api/challenge?before=YYYY-MM-DD&limit=30. Lists past challenges, newest first (public).
+func (cc *ChallengeController) List(c *gin.Context) {
+	before := c.Query("before")
+	if before != "" {
+		if _, err := time.Parse(entity.ChallengeDateLayout, before); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "before must be YYYY-MM-DD"})
+			return
+		}
+	}
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", strconv.Itoa(defaultChallengeListLimit)), 10, 64)
+	if err != nil || limit <= 0 || limit > maxLeaderboardLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+		return
+	}
+
+	challenges, err := cc.challengeService.ListPast(c.Request.Context(), before, limit)
+	if err != nil {
+		log.Printf("Challenge ListPast: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load challenges"})
+		return
+	}
+	resp := response.ChallengeListResponse{Challenges: make([]response.ChallengeResponse, 0, len(challenges))}
+	for _, ch := range challenges {
+		resp.Challenges = append(resp.Challenges, toChallengeResponse(ch))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Get handles GET /api/challenge/:challenge_id. Returns a past or today's challenge (public);
+// challenge_id is a date (YYYY-MM-DD) or "today".
+func (cc *ChallengeController) Get(c *gin.Context) {
+	challenge, err := cc.challengeService.Get(c.Request.Context(), c.Param("challenge_id"))
+	if err != nil {
+		cc.writeLookupError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toChallengeResponse(challenge))
+}
+
+// Leaderboard handles GET /api/challenge/:challenge_id/leaderboard?limit=10 (public).
+// Ranks attempts by score; earlier submissions win ties. Flagged attempts are excluded until approved.
+func (cc *ChallengeController) Leaderboard(c *gin.Context) {
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", strconv.Itoa(entity.DashboardTopN)), 10, 64)
+	if err != nil || limit <= 0 || limit > maxLeaderboardLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+		return
+	}
+
+	challenge, entries, err := cc.challengeService.Leaderboard(c.Request.Context(), c.Param("challenge_id"), limit)
+	if err != nil {
+		cc.writeLookupError(c, err)
+		return
+	}
+	out := make([]response.ChallengeLeaderboardEntry, 0, len(entries))
+	for i, e := range entries {
+		out = append(out, response.ChallengeLeaderboardEntry{
+			Rank: i + 1,
+			User: response.CompositeUserSummary{
+				ID:    e.User.UserID.Hex(),
+				Name:  e.User.PublicName(),
+				Photo: e.User.PublicPicture(),
+			},
+			Score:       e.Attempt.SessionScore.Score,
+			Accuracy:    e.Attempt.SessionScore.Accuracy,
+			AvgTime:     e.Attempt.SessionScore.AvgTime,
+			SubmittedAt: e.Attempt.SubmittedAt,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"challenge_id": challenge.ID, "leaderboard": out})
+}
+
+func (cc *ChallengeController) writeLookupError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrChallengeNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	log.Printf("Challenge lookup: %v", err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load challenge"})
+}
+
+func toChallengeResponse(ch *entity.Challenge) response.ChallengeResponse {
+	return response.ChallengeResponse{
+		ChallengeID: ch.ID,
+		GameType:    ch.GameType,
+		Label:       game.GameType(ch.GameType).Label(),
+		Seed:        ch.Seed,
+		StartsAt:    ch.StartsAt,
+		EndsAt:      ch.EndsAt,
+	}
+}
+
+// toChallengeAttemptResponse maps an attempt; result adds the percentile when the attempt was just submitted.
+func toChallengeAttemptResponse(attempt *entity.ChallengeAttempt, result *entity.GameResult, rank int64) *response.ChallengeAttemptResponse {
+	if result == nil {
+		result = &entity.GameResult{SessionID: attempt.SessionID, SessionScore: attempt.SessionScore}
+	}
+	return &response.ChallengeAttemptResponse{
+		SessionID:   attempt.SessionID,
+		Result:      toGameResultResponse(result),
+		Rank:        rank,
+		Flagged:     attempt.Flagged,
+		SubmittedAt: attempt.SubmittedAt,
+	}
+}
@@ -2,13 +2,17 @@ package controller
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
 	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/model/response"
 	"brainbash_backend/internal/service"
 )
 
+const maxLeaderboardLimit = 100
+
 // DashboardController handles public dashboard (leaderboard) API.
 type DashboardController struct {
 	dashboardService *service.DashboardService
@@ -60,3 +64,33 @@ func (dc *DashboardController) GetDashboard(c *gin.Context) {
 
 	c.JSON(http.StatusOK, out)
 }
+
+// GetCompositeLeaderboard handles GET /api/dashboard/composite?limit=10. Ranks users by overall_score,
+// the equally weighted composite of their cognitive profile (public).
+func (dc *DashboardController) GetCompositeLeaderboard(c *gin.Context) {
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", strconv.Itoa(entity.DashboardTopN)), 10, 64)
+	if err != nil || limit <= 0 || limit > maxLeaderboardLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+		return
+	}
+
+	entries, err := dc.dashboardService.GetCompositeLeaderboard(c.Request.Context(), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load leaderboard"})
+		return
+	}
+
+	out := make([]response.CompositeLeaderboardEntry, 0, len(entries))
+	for i, e := range entries {
+		out = append(out, response.CompositeLeaderboardEntry{
+			Rank: i + 1,
+			User: response.CompositeUserSummary{
+				ID:    e.User.UserID.Hex(),
+				Name:  e.User.PublicName(),
+				Photo: e.User.PublicPicture(),
+			},
+			OverallScore: e.OverallScore,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"leaderboard": out})
+}
@@ -11,6 +11,7 @@ import (
 	"brainbash_backend/config"
 	"brainbash_backend/internal/model/response"
 	"brainbash_backend/internal/service"
+	"brainbash_backend/internal/utils"
 )
 
 // DebugController exposes debug-only endpoints (e.g. user lookup by id, JWT from email).
@@ -48,10 +49,16 @@ func (dc *DebugController) GetUser(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, response.UserInfo{
-		UserID:  user.UserID.Hex(),
-		Email:   user.Email,
-		Name:    user.Name,
-		Picture: user.Picture,
+		UserID:      user.UserID.Hex(),
+		Email:       user.Email,
+		Name:        user.Name,
+		Picture:     user.Picture,
+		DisplayName: user.PublicName(),
+		AvatarURL:   user.PublicPicture(),
+		BirthYear:   user.BirthYear,
+		Timezone:    user.Timezone,
+		Locale:      user.Locale,
+		Bio:         user.Bio,
 	})
 }
 
@@ -81,9 +88,10 @@ func (dc *DebugController) GenerateJWT(c *gin.Context) {
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"sub": user.UserID.Hex(),
-		"iat": time.Now().Unix(),
-		"exp": time.Now().Add(24 * time.Hour).Unix(),
+		"sub":             user.UserID.Hex(),
+		"iat":             time.Now().Unix(),
+		"exp":             time.Now().Add(24 * time.Hour).Unix(),
+		utils.ClaimTenant: requestTenantID(c),
 	})
 	tokenString, err := token.SignedString([]byte(dc.cfg.StaticConfig.Auth.JWTSecret))
 	if err != nil {
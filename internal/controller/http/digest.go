@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"brainbash_backend/internal/game"
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/model/response"
+	"brainbash_backend/internal/service"
+	"brainbash_backend/internal/utils"
+)
+
+const defaultDigestListLimit = 4
+
+// DigestController serves the authenticated user's weekly digests.
+type DigestController struct {
+	digestService *service.DigestService
+}
+
+// NewDigestController creates a new DigestController.
+func NewDigestController(digestService *service.DigestService) *DigestController {
+	return &DigestController{
+		digestService: digestService,
+	}
+}
+
+// List handles GET /api/user/digests?limit=4. Returns the most recent digests, newest first.
+func (dc *DigestController) List(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", strconv.Itoa(defaultDigestListLimit)), 10, 64)
+	if err != nil || limit <= 0 || limit > maxLeaderboardLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+		return
+	}
+
+	digests, err := dc.digestService.List(c.Request.Context(), userID, limit)
+	if err != nil {
+		log.Printf("Digest List: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load digests"})
+		return
+	}
+	resp := response.DigestListResponse{Digests: make([]response.DigestResponse, 0, len(digests))}
+	for _, d := range digests {
+		resp.Digests = append(resp.Digests, toDigestResponse(d))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Get handles GET /api/user/digests/:week_start, where week_start is the Monday (YYYY-MM-DD) the week began.
+func (dc *DigestController) Get(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	digest, err := dc.digestService.Get(c.Request.Context(), userID, c.Param("week_start"))
+	if err != nil {
+		if errors.Is(err, service.ErrDigestNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Digest Get: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load digest"})
+		return
+	}
+	c.JSON(http.StatusOK, toDigestResponse(digest))
+}
+
+func toDigestResponse(d *entity.Digest) response.DigestResponse {
+	resp := response.DigestResponse{
+		WeekStart:        d.WeekStart,
+		WeekEnd:          d.WeekEnd,
+		SessionsPlayed:   d.SessionsPlayed,
+		PreviousSessions: d.PreviousSessions,
+		Improvements:     make([]response.DigestImprovementResponse, 0, len(d.Improvements)),
+		OverallRank:      d.OverallRank,
+		RankChange:       d.RankChange,
+		GoalsHit:         make([]response.DigestGoalResponse, 0, len(d.GoalsHit)),
+		GeneratedAt:      d.GeneratedAt,
+	}
+	for _, imp := range d.Improvements {
+		resp.Improvements = append(resp.Improvements, response.DigestImprovementResponse{
+			GameType:    imp.GameType,
+			Label:       game.GameType(imp.GameType).Label(),
+			Sessions:    imp.Sessions,
+			AvgScore:    imp.AvgScore,
+			PreviousAvg: imp.PreviousAvg,
+			Change:      imp.Change,
+			WeekBest:    imp.WeekBest,
+		})
+	}
+	for _, g := range d.GoalsHit {
+		resp.GoalsHit = append(resp.GoalsHit, response.DigestGoalResponse{GoalID: g.GoalID, Kind: g.Kind, Target: g.Target, Times: g.Times})
+	}
+	return resp
+}
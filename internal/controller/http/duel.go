@@ -0,0 +1,297 @@
+package controller
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"brainbash_backend/internal/game"
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/model/request"
+	"brainbash_backend/internal/model/response"
+	"brainbash_backend/internal/service"
+	"brainbash_backend/internal/utils"
+)
+
+const defaultDuelListLimit = 20
+
+// Duel outcomes from the caller's side.
+const (
+	duelOutcomeWon  = "won"
+	duelOutcomeLost = "lost"
+	duelOutcomeDraw = "draw"
+)
+
+// DuelController handles head-to-head duels between friends.
+type DuelController struct {
+	duelService *service.DuelService
+}
+
+// NewDuelController creates a new DuelController.
+func NewDuelController(duelService *service.DuelService) *DuelController {
+	return &DuelController{
+		duelService: duelService,
+	}
+}
+
+// Create handles POST /api/duels. Challenges a friend on a game type; returns the duel with its seed.
+func (dc *DuelController) Create(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	var req request.CreateDuelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "opponent_id and gametype are required"})
+		return
+	}
+	if err := service.ValidateGameType(c.Request.Context(), req.GameType); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	duel, err := dc.duelService.Create(c.Request.Context(), userID, req.OpponentID, req.GameType)
+	if err != nil {
+		dc.writeError(c, "Create", err)
+		return
+	}
+	dc.respond(c, http.StatusCreated, userID, duel)
+}
+
+// List handles GET /api/duels?status=&limit=20. Lists the user's duels from both sides, newest first.
+func (dc *DuelController) List(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	status := c.Query("status")
+	switch status {
+	case "", entity.DuelPending, entity.DuelActive, entity.DuelDeclined, entity.DuelCompleted, entity.DuelExpired:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be pending, active, declined, completed or expired"})
+		return
+	}
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", strconv.Itoa(defaultDuelListLimit)), 10, 64)
+	if err != nil || limit <= 0 || limit > maxLeaderboardLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+		return
+	}
+
+	duels, err := dc.duelService.List(c.Request.Context(), userID, status, limit)
+	if err != nil {
+		dc.writeError(c, "List", err)
+		return
+	}
+	players, err := dc.duelService.Players(c.Request.Context(), duels...)
+	if err != nil {
+		dc.writeError(c, "List", err)
+		return
+	}
+	resp := response.DuelListResponse{Duels: make([]response.DuelResponse, 0, len(duels))}
+	for _, d := range duels {
+		resp.Duels = append(resp.Duels, toDuelResponse(d, userID, players))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Get handles GET /api/duels/:duel_id.
+func (dc *DuelController) Get(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	duel, err := dc.duelService.Get(c.Request.Context(), userID, c.Param("duel_id"))
+	if err != nil {
+		dc.writeError(c, "Get", err)
+		return
+	}
+	dc.respond(c, http.StatusOK, userID, duel)
+}
+
+// Accept handles POST /api/duels/:duel_id/accept. Only the challenged player can accept.
+func (dc *DuelController) Accept(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	duel, err := dc.duelService.Accept(c.Request.Context(), userID, c.Param("duel_id"))
+	if err != nil {
+		dc.writeError(c, "Accept", err)
+		return
+	}
+	dc.respond(c, http.StatusOK, userID, duel)
+}
+
+// Decline handles POST /api/duels/:duel_id/decline. Only the challenged player can decline.
+func (dc *DuelController) Decline(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	duel, err := dc.duelService.Decline(c.Request.Context(), userID, c.Param("duel_id"))
+	if err != nil {
+		dc.writeError(c, "Decline", err)
+		return
+	}
+	dc.respond(c, http.StatusOK, userID, duel)
+}
+
+// Submit handles POST /api/duels/:duel_id/result. Stores the user's single session for the duel and
+// decides the duel once both players have played. Retrying with the same session_id and responses replays it.
+func (dc *DuelController) Submit(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	var req request.DuelResultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "question_responses is required"})
+		return
+	}
+
+	duel, result, replayed, err := dc.duelService.Submit(c.Request.Context(), userID, c.Param("duel_id"), req)
+	if err != nil {
+		log.Printf("Duel Submit: %v", err)
+		switch {
+		case errors.Is(err, service.ErrDuelNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrDuelPlayed), errors.Is(err, service.ErrDuelClosed),
+			errors.Is(err, service.ErrDuelNotAccepted), errors.Is(err, service.ErrSubmissionInProgress):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	players, err := dc.duelService.Players(c.Request.Context(), duel)
+	if err != nil {
+		log.Printf("Duel Players: %v", err)
+	}
+	if replayed {
+		c.Header(idempotentReplayedHeader, "true")
+	}
+	c.JSON(http.StatusOK, response.DuelResultResponse{
+		Duel:   toDuelResponse(duel, userID, players),
+		Result: toGameResultResponse(result),
+	})
+}
+
+// Record handles GET /api/user/duels/record. Returns the user's duel wins, losses and draws.
+func (dc *DuelController) Record(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	record, err := dc.duelService.Record(c.Request.Context(), userID)
+	if err != nil {
+		dc.writeError(c, "Record", err)
+		return
+	}
+	c.JSON(http.StatusOK, response.DuelRecordResponse{
+		Wins:   record.Wins,
+		Losses: record.Losses,
+		Draws:  record.Draws,
+		Played: record.Wins + record.Losses + record.Draws,
+	})
+}
+
+// respond writes the duel with its players.
+func (dc *DuelController) respond(c *gin.Context, code int, userID string, duel *entity.Duel) {
+	players, err := dc.duelService.Players(c.Request.Context(), duel)
+	if err != nil {
+		dc.writeError(c, "Players", err)
+		return
+	}
+	c.JSON(code, toDuelResponse(duel, userID, players))
+}
+
+func (dc *DuelController) writeError(c *gin.Context, op string, err error) {
+	switch {
+	case errors.Is(err, service.ErrDuelNotFound), errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrSelfRelation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrDuelForbidden), errors.Is(err, service.ErrNotFriends), errors.Is(err, service.ErrBlocked):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrDuelClosed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Printf("Duel %s: %v", op, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
+
+// toDuelResponse maps a duel from userID's side. The other player's result is only shown once the
+// user has played or the duel is over, so it cannot be used as a target.
+func toDuelResponse(d *entity.Duel, userID string, players map[string]*entity.User) response.DuelResponse {
+	side := d.Side(userID)
+	otherSide := entity.DuelSideOpponent
+	if side == entity.DuelSideOpponent {
+		otherSide = entity.DuelSideChallenger
+	}
+	mine, theirs := d.Entry(side), d.Entry(otherSide)
+	reveal := mine != nil || d.CompletedAt != nil
+
+	resp := response.DuelResponse{
+		DuelID:      d.ID,
+		GameType:    d.GameType,
+		Label:       game.GameType(d.GameType).Label(),
+		Seed:        d.Seed,
+		Status:      d.Status,
+		Role:        side,
+		Me:          toDuelPlayerResponse(userID, players, mine, true),
+		Opponent:    toDuelPlayerResponse(d.OtherID(userID), players, theirs, reveal),
+		Forfeit:     d.Forfeit,
+		CreatedAt:   d.CreatedAt,
+		AcceptedAt:  d.AcceptedAt,
+		ExpiresAt:   d.ExpiresAt,
+		CompletedAt: d.CompletedAt,
+	}
+	if d.Status == entity.DuelCompleted {
+		switch d.WinnerID {
+		case "":
+			resp.Outcome = duelOutcomeDraw
+		case userID:
+			resp.Outcome = duelOutcomeWon
+		default:
+			resp.Outcome = duelOutcomeLost
+		}
+	}
+	return resp
+}
+
+func toDuelPlayerResponse(userID string, players map[string]*entity.User, entry *entity.DuelEntry, reveal bool) response.DuelPlayerResponse {
+	out := response.DuelPlayerResponse{User: response.CompositeUserSummary{ID: userID}, Played: entry != nil}
+	if u := players[userID]; u != nil {
+		out.User = toUserSummary(u)
+	}
+	if entry != nil && reveal {
+		out.Result = &response.DuelEntryResponse{
+			SessionID:   entry.SessionID,
+			Score:       entry.SessionScore.Score,
+			Accuracy:    entry.SessionScore.Accuracy,
+			AvgTime:     entry.SessionScore.AvgTime,
+			Flagged:     entry.Flagged,
+			SubmittedAt: entry.SubmittedAt,
+		}
+	}
+	return out
+}
@@ -0,0 +1,36 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"brainbash_backend/internal/model/response"
+	"brainbash_backend/internal/service"
+	"brainbash_backend/internal/tenant"
+)
+
+// GameController serves the game catalog of the request's tenant.
+type GameController struct {
+	tenants *service.TenantService
+}
+
+// NewGameController creates a new GameController.
+func NewGameController(tenants *service.TenantService) *GameController {
+	return &GameController{
+		tenants: tenants,
+	}
+}
+
+// Catalog handles GET /api/games. Lists the game types the tenant offers with their display names (public).
+func (gc *GameController) Catalog(c *gin.Context) {
+	t := tenant.FromContext(c.Request.Context())
+	if t == nil {
+		t = gc.tenants.Get(c.Request.Context(), tenant.DefaultID)
+	}
+	resp := response.GameCatalogResponse{TenantID: t.ID, Name: t.Name, GameTypes: []response.GameCatalogEntry{}}
+	for _, gt := range t.Catalog() {
+		resp.GameTypes = append(resp.GameTypes, response.GameCatalogEntry{GameType: string(gt), Label: t.Label(gt)})
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"brainbash_backend/internal/model/request"
+	"brainbash_backend/internal/model/response"
+	"brainbash_backend/internal/service"
+	"brainbash_backend/internal/utils"
+	"brainbash_backend/internal/validation"
+)
+
+// GoalController handles CRUD of the authenticated user's goals.
+type GoalController struct {
+	goalService *service.GoalService
+}
+
+// NewGoalController creates a new GoalController.
+func NewGoalController(goalService *service.GoalService) *GoalController {
+	return &GoalController{
+		goalService: goalService,
+	}
+}
+
+// List handles GET /api/user/goals. Returns every goal with today's progress.
+func (gc *GoalController) List(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	statuses, err := gc.goalService.List(c.Request.Context(), userID)
+	if err != nil {
+		log.Printf("Goals List: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load goals"})
+		return
+	}
+	resp := response.GoalsResponse{Goals: make([]response.GoalResponse, 0, len(statuses))}
+	for i := range statuses {
+		resp.Goals = append(resp.Goals, toGoalResponse(&statuses[i]))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Create handles POST /api/user/goals.
+func (gc *GoalController) Create(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	var req request.CreateGoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "kind and target are required"})
+		return
+	}
+	status, err := gc.goalService.Create(c.Request.Context(), userID, service.GoalInput{
+		Kind:     req.Kind,
+		GameType: req.GameType,
+		Target:   req.Target,
+		Active:   req.Active,
+	})
+	if err != nil {
+		gc.writeError(c, "Create", err)
+		return
+	}
+	c.JSON(http.StatusCreated, toGoalResponse(status))
+}
+
+// Get handles GET /api/user/goals/:goal_id.
+func (gc *GoalController) Get(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	status, err := gc.goalService.Get(c.Request.Context(), userID, c.Param("goal_id"))
+	if err != nil {
+		gc.writeError(c, "Get", err)
+		return
+	}
+	c.JSON(http.StatusOK, toGoalResponse(status))
+}
+
+// Update handles PATCH /api/user/goals/:goal_id. Only target and active can change.
+func (gc *GoalController) Update(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	var req request.UpdateGoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	status, err := gc.goalService.Update(c.Request.Context(), userID, c.Param("goal_id"), service.GoalInput{
+		Target: req.Target,
+		Active: req.Active,
+	})
+	if err != nil {
+		gc.writeError(c, "Update", err)
+		return
+	}
+	c.JSON(http.StatusOK, toGoalResponse(status))
+}
+
+// Delete handles DELETE /api/user/goals/:goal_id.
+func (gc *GoalController) Delete(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	if err := gc.goalService.Delete(c.Request.Context(), userID, c.Param("goal_id")); err != nil {
+		gc.writeError(c, "Delete", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (gc *GoalController) writeError(c *gin.Context, op string, err error) {
+	var vErr *validation.Error
+	switch {
+	case errors.Is(err, service.ErrGoalNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrTooManyGoals):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.As(err, &vErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Printf("Goals %s: %v", op, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save goal"})
+	}
+}
+
+func toGoalResponse(status *service.GoalStatus) response.GoalResponse {
+	g := status.Goal
+	return response.GoalResponse{
+		GoalID:     g.ID,
+		Kind:       g.Kind,
+		GameType:   g.GameType,
+		Target:     g.Target,
+		Active:     g.Active,
+		Progress:   status.Progress,
+		Met:        status.Met,
+		TimesMet:   len(g.HitDays),
+		AchievedAt: g.AchievedAt,
+		CreatedAt:  g.CreatedAt,
+	}
+}
@@ -0,0 +1,394 @@
+package controller
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"brainbash_backend/internal/game"
+	"brainbash_backend/internal/middleware"
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/model/request"
+	"brainbash_backend/internal/model/response"
+	"brainbash_backend/internal/service"
+	"brainbash_backend/internal/utils"
+	"brainbash_backend/internal/validation"
+)
+
+const defaultGroupReportDays = 30
+
+// GroupController handles groups: creation, join codes, membership, roles, the group leaderboard and the
+// owner's progress dashboard. Routes on /api/groups/:group_id are guarded by RequireRole.
+type GroupController struct {
+	groupService *service.GroupService
+}
+
+// NewGroupController creates a new GroupController.
+func NewGroupController(groupService *service.GroupService) *GroupController {
+	return &GroupController{
+		groupService: groupService,
+	}
+}
+
+// RequireRole returns the middleware that only lets members of the :group_id group with at least role through.
+func (gc *GroupController) RequireRole(role string) gin.HandlerFunc {
+	return middleware.GroupMembership(gc.groupService, role)
+}
+
+// Create handles POST /api/groups. The caller becomes the owner.
+func (gc *GroupController) Create(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+	var req request.CreateGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+
+	group, err := gc.groupService.Create(c.Request.Context(), userID, req.Name)
+	if err != nil {
+		gc.writeError(c, "Create", err)
+		return
+	}
+	c.JSON(http.StatusCreated, toGroupResponse(group, entity.GroupRoleOwner, 1))
+}
+
+// List handles GET /api/groups. Returns the caller's groups, most recently joined first.
+func (gc *GroupController) List(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	memberships, err := gc.groupService.ForUser(c.Request.Context(), userID)
+	if err != nil {
+		gc.writeError(c, "List", err)
+		return
+	}
+	resp := response.GroupListResponse{Groups: make([]response.GroupResponse, 0, len(memberships))}
+	for _, m := range memberships {
+		resp.Groups = append(resp.Groups, toGroupResponse(m.Group, m.Role, m.Members))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Join handles POST /api/groups/join. Joins the group with the join code as a member.
+func (gc *GroupController) Join(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+	var req request.JoinGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "join_code is required"})
+		return
+	}
+
+	group, err := gc.groupService.Join(c.Request.Context(), userID, req.JoinCode)
+	if err != nil {
+		gc.writeError(c, "Join", err)
+		return
+	}
+	gc.respondGroup(c, "Join", group.ID, entity.GroupRoleMember)
+}
+
+// Get handles GET /api/groups/:group_id (members).
+func (gc *GroupController) Get(c *gin.Context) {
+	gc.respondGroup(c, "Get", c.Param("group_id"), middleware.GetGroupRole(c))
+}
+
+// Delete handles DELETE /api/groups/:group_id (owner). Removes the group and all memberships.
+func (gc *GroupController) Delete(c *gin.Context) {
+	if err := gc.groupService.Delete(c.Request.Context(), c.Param("group_id")); err != nil {
+		gc.writeError(c, "Delete", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RegenerateCode handles POST /api/groups/:group_id/code (admins). Replaces the join code.
+func (gc *GroupController) RegenerateCode(c *gin.Context) {
+	if _, err := gc.groupService.RegenerateCode(c.Request.Context(), c.Param("group_id")); err != nil {
+		gc.writeError(c, "RegenerateCode", err)
+		return
+	}
+	gc.respondGroup(c, "RegenerateCode", c.Param("group_id"), middleware.GetGroupRole(c))
+}
+
+// Members handles GET /api/groups/:group_id/members (members).
+func (gc *GroupController) Members(c *gin.Context) {
+	members, err := gc.groupService.Members(c.Request.Context(), c.Param("group_id"))
+	if err != nil {
+		gc.writeError(c, "Members", err)
+		return
+	}
+	resp := response.GroupMembersResponse{Members: make([]response.GroupMemberResponse, 0, len(members))}
+	for _, m := range members {
+		resp.Members = append(resp.Members, response.GroupMemberResponse{
+			User:     toUserSummary(m.User),
+			Role:     m.Member.Role,
+			JoinedAt: m.Member.JoinedAt,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// SetRole handles PUT /api/groups/:group_id/members/:user_id/role (owner). Setting role "owner" transfers ownership.
+func (gc *GroupController) SetRole(c *gin.Context) {
+	var req request.GroupRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "role is required"})
+		return
+	}
+	err := gc.groupService.SetRole(c.Request.Context(), c.Param("group_id"), utils.GetUserIDFromContext(c), c.Param("user_id"), req.Role)
+	if err != nil {
+		gc.writeError(c, "SetRole", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RemoveMember handles DELETE /api/groups/:group_id/members/:user_id (admins). Admins can only remove members.
+func (gc *GroupController) RemoveMember(c *gin.Context) {
+	err := gc.groupService.RemoveMember(c.Request.Context(), c.Param("group_id"), middleware.GetGroupRole(c), c.Param("user_id"))
+	if err != nil {
+		gc.writeError(c, "RemoveMember", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Leave handles DELETE /api/groups/:group_id/membership (members).
+func (gc *GroupController) Leave(c *gin.Context) {
+	if err := gc.groupService.Leave(c.Request.Context(), c.Param("group_id"), utils.GetUserIDFromContext(c)); err != nil {
+		gc.writeError(c, "Leave", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Leaderboard handles GET /api/groups/:group_id/leaderboard?gametype=&window=all (members).
+func (gc *GroupController) Leaderboard(c *gin.Context) {
+	groupID := c.Param("group_id")
+	gameType, window := c.Query("gametype"), c.DefaultQuery("window", service.WindowAll)
+	entries, err := gc.groupService.Leaderboard(c.Request.Context(), groupID, utils.GetUserIDFromContext(c), gameType, window)
+	if err != nil {
+		gc.writeError(c, "Leaderboard", err)
+		return
+	}
+	c.JSON(http.StatusOK, response.GroupLeaderboardResponse{
+		GroupID:     groupID,
+		GameType:    gameType,
+		Window:      window,
+		Leaderboard: toLeaderboardResponse(entries),
+	})
+}
+
+// Progress handles GET /api/groups/:group_id/progress (owner). Returns every member's scores and last activity.
+func (gc *GroupController) Progress(c *gin.Context) {
+	groupID := c.Param("group_id")
+	progress, err := gc.groupService.Progress(c.Request.Context(), groupID)
+	if err != nil {
+		gc.writeError(c, "Progress", err)
+		return
+	}
+	resp := response.GroupProgressResponse{GroupID: groupID, Members: make([]response.GroupMemberProgressRow, 0, len(progress))}
+	for _, p := range progress {
+		row := response.GroupMemberProgressRow{
+			User:         toUserSummary(p.User),
+			Role:         p.Member.Role,
+			OverallScore: p.OverallScore,
+			GameTypes:    make([]response.GroupGameTypeProgress, 0, len(p.GameTypes)),
+			LastActiveAt: p.LastActiveAt,
+		}
+		for _, gt := range p.GameTypes {
+			row.GameTypes = append(row.GameTypes, response.GroupGameTypeProgress{
+				GameType:  gt.GameType,
+				Label:     game.GameType(gt.GameType).Label(),
+				AvgScore:  gt.AvgScore,
+				HighScore: gt.HighScore,
+				Sessions:  gt.Sessions,
+			})
+		}
+		resp.Members = append(resp.Members, row)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Report handles GET /api/groups/:group_id/report?from=dd-mm-yyyy&to=dd-mm-yyyy&format=json|csv (owner).
+// to defaults to today and from to 30 days before to (UTC), both inclusive. The CSV is also returned when
+// the client accepts text/csv.
+func (gc *GroupController) Report(c *gin.Context) {
+	now := time.Now().UTC()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if s := c.Query("to"); s != "" {
+		t, err := time.Parse(dateLayout, s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be dd-mm-yyyy"})
+			return
+		}
+		to = t
+	}
+	from := to.AddDate(0, 0, -defaultGroupReportDays)
+	if s := c.Query("from"); s != "" {
+		t, err := time.Parse(dateLayout, s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be dd-mm-yyyy"})
+			return
+		}
+		from = t
+	}
+	format := c.Query("format")
+	if format == "" && strings.Contains(c.GetHeader("Accept"), "text/csv") {
+		format = "csv"
+	}
+	if format != "" && format != "json" && format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or csv"})
+		return
+	}
+
+	groupID := c.Param("group_id")
+	// Include the whole of the end day
+	rows, err := gc.groupService.Report(c.Request.Context(), groupID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		gc.writeError(c, "Report", err)
+		return
+	}
+	if format == "csv" {
+		writeGroupReportCSV(c, groupID, from, to, rows)
+		return
+	}
+
+	resp := response.GroupReportResponse{
+		GroupID: groupID,
+		From:    from.Format(dateLayout),
+		To:      to.Format(dateLayout),
+		Members: make([]response.GroupMemberReportRow, 0, len(rows)),
+	}
+	for _, r := range rows {
+		row := response.GroupMemberReportRow{
+			User:         toUserSummary(r.User),
+			Role:         r.Member.Role,
+			Sessions:     r.Sessions,
+			LastActiveAt: r.LastActiveAt,
+			Domains:      make([]response.GroupDomainReport, 0, len(r.Domains)),
+		}
+		for _, d := range r.Domains {
+			row.Domains = append(row.Domains, response.GroupDomainReport{
+				GameType:    d.GameType,
+				Label:       game.GameType(d.GameType).Label(),
+				Sessions:    d.Sessions,
+				AvgScore:    d.AvgScore,
+				Improvement: d.Improvement,
+			})
+		}
+		resp.Members = append(resp.Members, row)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// writeGroupReportCSV writes the report as a CSV attachment: one row per member, with sessions, average
+// and improvement columns for every game type. Missing values are left empty.
+func writeGroupReportCSV(c *gin.Context, groupID string, from, to time.Time, rows []service.MemberReport) {
+	header := []string{"user_id", "name", "role", "sessions", "last_active"}
+	for _, gt := range game.AllGameTypes {
+		header = append(header, string(gt)+"_sessions", string(gt)+"_avg_score", string(gt)+"_improvement")
+	}
+	records := [][]string{header}
+	for _, r := range rows {
+		lastActive := ""
+		if r.LastActiveAt != nil {
+			lastActive = r.LastActiveAt.UTC().Format(time.DateOnly)
+		}
+		record := []string{r.User.UserID.Hex(), csvSafe(r.User.PublicName()), r.Member.Role, strconv.Itoa(r.Sessions), lastActive}
+		for _, gt := range game.AllGameTypes {
+			sessions, avg, improvement := "0", "", ""
+			for _, d := range r.Domains {
+				if d.GameType == string(gt) {
+					sessions, avg, improvement = strconv.Itoa(d.Sessions), formatReportValue(d.AvgScore), formatReportValue(d.Improvement)
+				}
+			}
+			record = append(record, sessions, avg, improvement)
+		}
+		records = append(records, record)
+	}
+
+	filename := fmt.Sprintf("group-%s-report-%s-%s.csv", groupID, from.Format(time.DateOnly), to.Format(time.DateOnly))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(http.StatusOK)
+	if err := csv.NewWriter(c.Writer).WriteAll(records); err != nil {
+		log.Printf("Group Report: write csv: %v", err)
+	}
+}
+
+// csvSafe stops spreadsheets from evaluating user-controlled text as a formula.
+func csvSafe(s string) string {
+	if s != "" && strings.ContainsRune("=+-@", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func formatReportValue(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', 2, 64)
+}
+
+// respondGroup writes the group as seen by a member with role.
+func (gc *GroupController) respondGroup(c *gin.Context, op, groupID, role string) {
+	group, members, err := gc.groupService.Get(c.Request.Context(), groupID)
+	if err != nil {
+		gc.writeError(c, op, err)
+		return
+	}
+	c.JSON(http.StatusOK, toGroupResponse(group, role, members))
+}
+
+func (gc *GroupController) writeError(c *gin.Context, op string, err error) {
+	var vErr *validation.Error
+	switch {
+	case errors.Is(err, service.ErrGroupNotFound), errors.Is(err, service.ErrGroupMemberNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrGroupForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrGroupJoined), errors.Is(err, service.ErrGroupFull),
+		errors.Is(err, service.ErrGroupLimit), errors.Is(err, service.ErrGroupOwner):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidWindow), errors.As(err, &vErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Printf("Group %s: %v", op, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
+
+// toGroupResponse shows the join code only to admins and the owner.
+func toGroupResponse(g *entity.Group, role string, members int64) response.GroupResponse {
+	resp := response.GroupResponse{
+		GroupID:   g.ID,
+		Name:      g.Name,
+		OwnerID:   g.OwnerID,
+		Role:      role,
+		Members:   members,
+		CreatedAt: g.CreatedAt,
+	}
+	if entity.GroupRoleRank(role) >= entity.GroupRoleRank(entity.GroupRoleAdmin) {
+		resp.JoinCode = g.JoinCode
+	}
+	return resp
+}
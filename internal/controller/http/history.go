@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"brainbash_backend/internal/model/response"
+	"brainbash_backend/internal/service"
+	"brainbash_backend/internal/utils"
+	"brainbash_backend/internal/validation"
+)
+
+const defaultHistoryDays = 90
+
+// HistoryController serves the authenticated user's progress history.
+type HistoryController struct {
+	historyService *service.HistoryService
+}
+
+// NewHistoryController creates a new HistoryController.
+func NewHistoryController(historyService *service.HistoryService) *HistoryController {
+	return &HistoryController{
+		historyService: historyService,
+	}
+}
+
+// GetHistory handles GET /api/user/history?gametype=&from=dd-mm-yyyy&to=dd-mm-yyyy&bucket=day|week|month.
+// to defaults to today and from to 90 days before to (UTC); bucket defaults to day.
+func (hc *HistoryController) GetHistory(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	gameType := c.Query("gametype")
+	if gameType == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "query param gametype is required"})
+		return
+	}
+	bucket := c.DefaultQuery("bucket", service.BucketDay)
+
+	now := time.Now().UTC()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if s := c.Query("to"); s != "" {
+		t, err := time.Parse(dateLayout, s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be dd-mm-yyyy"})
+			return
+		}
+		to = t
+	}
+	from := to.AddDate(0, 0, -defaultHistoryDays)
+	if s := c.Query("from"); s != "" {
+		t, err := time.Parse(dateLayout, s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be dd-mm-yyyy"})
+			return
+		}
+		from = t
+	}
+	if to.Before(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must be >= from"})
+		return
+	}
+	// Include the whole of the end day
+	toEnd := to.Add(24*time.Hour - time.Nanosecond)
+
+	history, err := hc.historyService.GetHistory(c.Request.Context(), userID, gameType, bucket, from, toEnd)
+	if err != nil {
+		var vErr *validation.Error
+		if errors.As(err, &vErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("GetHistory: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load history"})
+		return
+	}
+
+	out := response.HistoryResponse{
+		GameType: history.GameType,
+		Bucket:   history.Bucket,
+		From:     from.Format(dateLayout),
+		To:       to.Format(dateLayout),
+		Buckets:  make([]response.HistoryBucket, 0, len(history.Buckets)),
+		Trend: response.HistoryTrend{
+			SlopePerDay:    history.SlopePerDay,
+			SlopePerBucket: history.SlopePerBucket,
+		},
+	}
+	for _, b := range history.Buckets {
+		out.Buckets = append(out.Buckets, response.HistoryBucket{
+			Start:    b.Start.Format(dateLayout),
+			Count:    b.Count,
+			Mean:     b.Mean,
+			Best:     b.Best,
+			Accuracy: b.Accuracy,
+			AvgTime:  b.AvgTime,
+		})
+	}
+	c.JSON(http.StatusOK, out)
+}
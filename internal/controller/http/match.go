@@ -0,0 +1,181 @@
+package controller
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"brainbash_backend/internal/game"
+	"brainbash_backend/internal/match"
+	"brainbash_backend/internal/middleware"
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/model/response"
+	"brainbash_backend/internal/service"
+	"brainbash_backend/internal/tenant"
+	"brainbash_backend/internal/utils"
+	"brainbash_backend/internal/validation"
+)
+
+const (
+	defaultMatchPlayers   = 2
+	defaultMatchListLimit = 20
+	matchReadLimit        = 4096 // bytes per client message
+)
+
+// MatchController handles real-time matches: the WebSocket players connect to and match history.
+type MatchController struct {
+	matchService *service.MatchService
+	upgrader     websocket.Upgrader
+}
+
+// NewMatchController creates a new MatchController.
+func NewMatchController(matchService *service.MatchService) *MatchController {
+	return &MatchController{
+		matchService: matchService,
+		upgrader: websocket.Upgrader{
+			// Same origins as CORS; clients that send no Origin (mobile apps) are allowed
+			CheckOrigin: func(r *http.Request) bool {
+				origin := r.Header.Get("Origin")
+				return origin == "" || middleware.TenantAllowsOrigin(tenant.FromContext(r.Context()), origin)
+			},
+		},
+	}
+}
+
+// Play handles GET /api/matches/play?gametype=&players=2 (WebSocket). Queues the user for a match and
+// streams it: queued, match_found, then question and standings per question, and match_end. Clients send
+// {"type":"answer","index":i,"outcome":"correct"} per question, or {"type":"leave"}.
+func (mc *MatchController) Play(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	gameType := c.Query("gametype")
+	players, err := strconv.Atoi(c.DefaultQuery("players", strconv.Itoa(defaultMatchPlayers)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "players must be a number"})
+		return
+	}
+	if err := mc.matchService.CheckQueue(c.Request.Context(), gameType, players); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	conn, err := mc.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader already answered the request
+		log.Printf("Match Play: upgrade: %v", err)
+		return
+	}
+	defer conn.Close()
+	conn.SetReadLimit(matchReadLimit)
+
+	if err := mc.matchService.Play(c.Request.Context(), userID, gameType, players, conn); err != nil {
+		if !errors.Is(err, match.ErrAlreadyPlaying) {
+			log.Printf("Match Play: %v", err)
+		}
+		_ = conn.WriteJSON(match.ErrorMessage{Type: match.MsgError, Error: err.Error()})
+	}
+}
+
+// List handles GET /api/matches?limit=20. Lists the user's finished matches, most recent first.
+func (mc *MatchController) List(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", strconv.Itoa(defaultMatchListLimit)), 10, 64)
+	if err != nil || limit <= 0 || limit > maxLeaderboardLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+		return
+	}
+
+	matches, err := mc.matchService.List(c.Request.Context(), userID, limit)
+	if err != nil {
+		mc.writeError(c, "List", err)
+		return
+	}
+	users, err := mc.matchService.Players(c.Request.Context(), matches...)
+	if err != nil {
+		mc.writeError(c, "List", err)
+		return
+	}
+	resp := response.MatchListResponse{Matches: make([]response.MatchResponse, 0, len(matches))}
+	for _, m := range matches {
+		resp.Matches = append(resp.Matches, toMatchResponse(m, userID, users))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Get handles GET /api/matches/:match_id. Only players of the match can see it.
+func (mc *MatchController) Get(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	m, err := mc.matchService.Get(c.Request.Context(), userID, c.Param("match_id"))
+	if err != nil {
+		mc.writeError(c, "Get", err)
+		return
+	}
+	users, err := mc.matchService.Players(c.Request.Context(), m)
+	if err != nil {
+		mc.writeError(c, "Get", err)
+		return
+	}
+	c.JSON(http.StatusOK, toMatchResponse(m, userID, users))
+}
+
+func (mc *MatchController) writeError(c *gin.Context, op string, err error) {
+	var vErr *validation.Error
+	switch {
+	case errors.Is(err, service.ErrMatchNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.As(err, &vErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Printf("Match %s: %v", op, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load matches"})
+	}
+}
+
+func toMatchResponse(m *entity.Match, userID string, users map[string]*entity.User) response.MatchResponse {
+	resp := response.MatchResponse{
+		MatchID:   m.ID,
+		GameType:  m.GameType,
+		Label:     game.GameType(m.GameType).Label(),
+		Questions: m.Questions,
+		Players:   make([]response.MatchPlayerResponse, 0, len(m.Players)),
+		StartedAt: m.StartedAt,
+		EndedAt:   m.EndedAt,
+	}
+	for _, p := range m.Players {
+		if p.UserID == userID {
+			resp.Place = p.Place
+		}
+		user := response.CompositeUserSummary{ID: p.UserID}
+		if u := users[p.UserID]; u != nil {
+			user = toUserSummary(u)
+		}
+		resp.Players = append(resp.Players, response.MatchPlayerResponse{
+			Place:     p.Place,
+			User:      user,
+			SessionID: p.SessionID,
+			Score:     p.SessionScore.Score,
+			Accuracy:  p.SessionScore.Accuracy,
+			AvgTime:   p.SessionScore.AvgTime,
+			Left:      p.Left,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"brainbash_backend/internal/model/request"
+	"brainbash_backend/internal/model/response"
+	"brainbash_backend/internal/notify"
+	"brainbash_backend/internal/service"
+	"brainbash_backend/internal/utils"
+	"brainbash_backend/internal/validation"
+)
+
+const defaultNotificationListLimit = 20
+
+// NotificationController serves the authenticated user's notifications and notification preferences.
+type NotificationController struct {
+	notificationService *service.NotificationService
+}
+
+// NewNotificationController creates a new NotificationController.
+func NewNotificationController(notificationService *service.NotificationService) *NotificationController {
+	return &NotificationController{
+		notificationService: notificationService,
+	}
+}
+
+// List handles GET /api/user/notifications?limit=20. Returns recent notifications on every channel, newest first.
+func (nc *NotificationController) List(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", strconv.Itoa(defaultNotificationListLimit)), 10, 64)
+	if err != nil || limit <= 0 || limit > maxLeaderboardLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+		return
+	}
+
+	notifications, err := nc.notificationService.List(c.Request.Context(), userID, limit)
+	if err != nil {
+		log.Printf("Notifications List: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load notifications"})
+		return
+	}
+	resp := response.NotificationListResponse{Notifications: make([]response.NotificationResponse, 0, len(notifications))}
+	for _, n := range notifications {
+		resp.Notifications = append(resp.Notifications, response.NotificationResponse{
+			ID:        n.ID,
+			Kind:      n.Kind,
+			Channel:   n.Channel,
+			Subject:   n.Subject,
+			Body:      n.Body,
+			Data:      n.Data,
+			Status:    n.Status,
+			CreatedAt: n.CreatedAt,
+			SentAt:    n.SentAt,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetPreferences handles GET /api/user/notifications/preferences.
+func (nc *NotificationController) GetPreferences(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	prefs, err := nc.notificationService.GetPreferences(c.Request.Context(), userID)
+	if err != nil {
+		log.Printf("Notifications GetPreferences: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load preferences"})
+		return
+	}
+	c.JSON(http.StatusOK, toNotificationPreferencesResponse(prefs))
+}
+
+// UpdatePreferences handles PUT /api/user/notifications/preferences.
+func (nc *NotificationController) UpdatePreferences(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	var req request.NotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "channels is required"})
+		return
+	}
+	prefs, err := nc.notificationService.UpdatePreferences(c.Request.Context(), userID, service.NotificationPreferences{
+		Channels:      req.Channels,
+		DisabledKinds: req.DisabledKinds,
+		QuietStart:    req.QuietStart,
+		QuietEnd:      req.QuietEnd,
+	})
+	if err != nil {
+		var vErr *validation.Error
+		if errors.As(err, &vErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Notifications UpdatePreferences: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save preferences"})
+		return
+	}
+	c.JSON(http.StatusOK, toNotificationPreferencesResponse(prefs))
+}
+
+func toNotificationPreferencesResponse(prefs *service.NotificationPreferences) response.NotificationPreferencesResponse {
+	resp := response.NotificationPreferencesResponse{
+		Channels:          prefs.Channels,
+		DisabledKinds:     prefs.DisabledKinds,
+		QuietStart:        prefs.QuietStart,
+		QuietEnd:          prefs.QuietEnd,
+		AvailableChannels: prefs.AvailableChannels,
+		Kinds:             notify.Kinds,
+	}
+	if resp.Channels == nil {
+		resp.Channels = []string{}
+	}
+	if resp.DisabledKinds == nil {
+		resp.DisabledKinds = []string{}
+	}
+	return resp
+}
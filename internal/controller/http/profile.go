@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"brainbash_backend/internal/model/response"
+	"brainbash_backend/internal/service"
+	"brainbash_backend/internal/utils"
+)
+
+// ProfileController serves the authenticated user's cognitive profile.
+type ProfileController struct {
+	profileService *service.ProfileService
+}
+
+// NewProfileController creates a new ProfileController.
+func NewProfileController(profileService *service.ProfileService) *ProfileController {
+	return &ProfileController{
+		profileService: profileService,
+	}
+}
+
+// Cognitive handles GET /api/user/profile/cognitive. Returns per-domain decayed scores,
+// the weighted composite, and a radar-ready series.
+func (pc *ProfileController) Cognitive(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	profile, err := pc.profileService.GetProfile(c.Request.Context(), userID)
+	if err != nil {
+		log.Printf("Cognitive GetProfile: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load profile"})
+		return
+	}
+
+	out := response.CognitiveProfileResponse{
+		Composite:    profile.Composite,
+		HalfLifeDays: profile.HalfLife.Hours() / 24,
+		Domains:      make([]response.DomainScore, 0, len(profile.Domains)),
+		Radar:        response.RadarChartSeries{Max: 100},
+	}
+	for _, d := range profile.Domains {
+		out.Domains = append(out.Domains, response.DomainScore{
+			GameType:     d.GameType,
+			Label:        d.Label,
+			Score:        d.Score,
+			Weight:       d.Weight,
+			Sessions:     d.Sessions,
+			LastPlayedAt: d.LastPlayedAt,
+		})
+		out.Radar.Labels = append(out.Radar.Labels, d.Label)
+		out.Radar.Keys = append(out.Radar.Keys, d.GameType)
+		out.Radar.Values = append(out.Radar.Values, d.Score)
+	}
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/model/response"
+	"brainbash_backend/internal/service"
+	"brainbash_backend/internal/utils"
+	"brainbash_backend/internal/validation"
+)
+
+const defaultRatingHistoryLimit = 20
+
+// RatingController handles the rating leaderboard and a user's rating history.
+type RatingController struct {
+	ratingService *service.RatingService
+}
+
+// NewRatingController creates a new RatingController.
+func NewRatingController(ratingService *service.RatingService) *RatingController {
+	return &RatingController{ratingService: ratingService}
+}
+
+// Leaderboard handles GET /api/dashboard/ratings?gametype=&limit=10. Ranks established (non-provisional)
+// ratings on the game type (public).
+func (rc *RatingController) Leaderboard(c *gin.Context) {
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", strconv.Itoa(entity.DashboardTopN)), 10, 64)
+	if err != nil || limit <= 0 || limit > maxLeaderboardLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+		return
+	}
+
+	entries, err := rc.ratingService.Leaderboard(c.Request.Context(), c.Query("gametype"), limit)
+	if err != nil {
+		rc.writeError(c, "Leaderboard", err)
+		return
+	}
+	out := make([]response.RatingLeaderboardEntry, 0, len(entries))
+	for i, e := range entries {
+		out = append(out, response.RatingLeaderboardEntry{
+			Rank:   i + 1,
+			User:   toUserSummary(e.User),
+			Rating: toRatingResponse(e.Rating, rc.ratingService),
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"leaderboard": out})
+}
+
+// History handles GET /api/user/ratings/history?gametype=&limit=20. Returns the user's current rating on
+// the game type and its changes, newest first.
+func (rc *RatingController) History(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", strconv.Itoa(defaultRatingHistoryLimit)), 10, 64)
+	if err != nil || limit <= 0 || limit > maxLeaderboardLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+		return
+	}
+
+	gameType := c.Query("gametype")
+	changes, err := rc.ratingService.History(c.Request.Context(), userID, gameType, limit)
+	if err != nil {
+		rc.writeError(c, "History", err)
+		return
+	}
+	current, err := rc.ratingService.Get(c.Request.Context(), userID, gameType)
+	if err != nil {
+		rc.writeError(c, "History", err)
+		return
+	}
+
+	resp := response.RatingHistoryResponse{
+		GameType: gameType,
+		Current:  toRatingResponse(current, rc.ratingService),
+		History:  make([]response.RatingChangeResponse, 0, len(changes)),
+	}
+	for _, ch := range changes {
+		resp.History = append(resp.History, response.RatingChangeResponse{
+			Source:    ch.Source,
+			RefID:     ch.RefID,
+			Games:     ch.Games,
+			Rating:    ch.Rating,
+			Deviation: ch.Deviation,
+			Change:    ch.Change,
+			CreatedAt: ch.CreatedAt,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (rc *RatingController) writeError(c *gin.Context, op string, err error) {
+	var vErr *validation.Error
+	if errors.As(err, &vErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	log.Printf("Rating %s: %v", op, err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load ratings"})
+}
+
+func toRatingResponse(r *entity.Rating, ratingService *service.RatingService) response.RatingResponse {
+	return response.RatingResponse{
+		Rating:      r.Rating,
+		Deviation:   r.Deviation,
+		Games:       r.Games,
+		Provisional: ratingService.Provisional(r),
+	}
+}
@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"brainbash_backend/internal/model/response"
+	"brainbash_backend/internal/service"
+	"brainbash_backend/internal/utils"
+)
+
+// RecommendationController serves the authenticated user's daily training plan.
+type RecommendationController struct {
+	recommendationService *service.RecommendationService
+}
+
+// NewRecommendationController creates a new RecommendationController.
+func NewRecommendationController(recommendationService *service.RecommendationService) *RecommendationController {
+	return &RecommendationController{
+		recommendationService: recommendationService,
+	}
+}
+
+// GetRecommendations handles GET /api/user/recommendations. Returns a short plan of games to play next,
+// each with a suggested difficulty and the reason it was picked.
+func (rc *RecommendationController) GetRecommendations(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	plan, err := rc.recommendationService.GetPlan(c.Request.Context(), userID)
+	if err != nil {
+		log.Printf("GetRecommendations GetPlan: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to build recommendations"})
+		return
+	}
+
+	resp := response.RecommendationsResponse{
+		Date:        plan.Date,
+		Recommender: plan.Recommender,
+		Experiment:  plan.Experiment,
+		Plan:        make([]response.RecommendationItem, 0, len(plan.Suggestions)),
+	}
+	for _, s := range plan.Suggestions {
+		resp.Plan = append(resp.Plan, response.RecommendationItem{
+			GameType:   s.GameType,
+			Label:      s.Label,
+			Difficulty: s.Difficulty,
+			Reason:     s.Reason,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -1,45 +1,221 @@
 package controller
 
 import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
 	"brainbash_backend/config"
 	appMongo "brainbash_backend/internal/mongo"
+	"brainbash_backend/internal/notify"
+	"brainbash_backend/internal/recommend"
 	"brainbash_backend/internal/repository"
+	"brainbash_backend/internal/scheduler"
 	"brainbash_backend/internal/scoring"
 	"brainbash_backend/internal/service"
-	"strings"
+	"brainbash_backend/internal/tenant"
+	"brainbash_backend/internal/validation"
 )
 
 // Controllers handles dependency injection in a centralized place.
 type Controllers struct {
-	HealthController    *HealthController
-	AuthController      *AuthController
-	DebugController     *DebugController
-	ScoreController     *ScoreController
-	DashboardController *DashboardController
-	CleanupController   *CleanupController
+	HealthController         *HealthController
+	AuthController           *AuthController
+	DebugController          *DebugController
+	ScoreController          *ScoreController
+	DashboardController      *DashboardController
+	CleanupController        *CleanupController
+	ReviewController         *ReviewController
+	ProfileController        *ProfileController
+	HistoryController        *HistoryController
+	SessionController        *SessionController
+	ChallengeController      *ChallengeController
+	StreakController         *StreakController
+	AchievementController    *AchievementController
+	RecommendationController *RecommendationController
+	GoalController           *GoalController
+	DigestController         *DigestController
+	NotificationController   *NotificationController
+	SocialController         *SocialController
+	DuelController           *DuelController
+	MatchController          *MatchController
+	RatingController         *RatingController
+	TournamentController     *TournamentController
+	GroupController          *GroupController
+	GameController           *GameController
+	TenantController         *TenantController
+	UserController           *UserController
+
+	// Tenants resolves the tenant of each request for the router's tenant and CORS middleware.
+	Tenants *service.TenantService
 }
 
 func NewControllers(cfg *config.AppConfig) *Controllers {
-	googleAuthService := service.NewGoogleAuthService(splitTrim(cfg.StaticConfig.Auth.GoogleClientID, ","))
+	googleClientIDs := splitTrim(cfg.StaticConfig.Auth.GoogleClientID, ",")
+	googleAuthService := service.NewGoogleAuthService(googleClientIDs)
+	tenantSettingsRepo := repository.NewTenantSettingsRepository(appMongo.GetDatabase())
+	tenants := service.NewTenantService(tenant.NewRegistry(cfg.StaticConfig.Tenants, googleClientIDs, splitTrim(cfg.StaticConfig.Auth.AdminUserIDs, ",")), tenantSettingsRepo, cfg.StaticConfig.Tenants)
 
 	userRepo := repository.NewUserRepository(appMongo.GetDatabase())
 	userService := service.NewUserService(userRepo)
 
 	scorer := scoring.NewScorer()
+	validator := validation.NewSessionValidator(cfg.StaticConfig.Validation)
+	outlierDetector := validation.NewOutlierDetector(cfg.StaticConfig.Validation.Outlier)
 	scoreRepo := repository.NewScoreRepository(appMongo.GetDatabase())
+	flagRepo := repository.NewFlagRepository(appMongo.GetDatabase())
+	sessionRepo := repository.NewSessionRepository(appMongo.GetDatabase())
+	idempotencyRepo := repository.NewIdempotencyRepository(appMongo.GetDatabase(), durationOr(cfg.StaticConfig.Idempotency.TTL, 24*time.Hour))
 	dashboardRepo := repository.NewDashboardRepository(appMongo.GetDatabase())
-	dashboardService := service.NewDashboardService(dashboardRepo, userService)
-	scoreService := service.NewScoreService(scoreRepo, scorer, dashboardService)
-	cleanupService := service.NewCleanupService(scoreRepo, dashboardRepo)
+	dashboardService := service.NewDashboardService(dashboardRepo, scoreRepo, userService)
+	profileService := service.NewProfileService(scoreRepo, tenants, cfg.StaticConfig.Profile)
+	normRepo := repository.NewNormRepository(appMongo.GetDatabase())
+	normsService := service.NewNormsService(normRepo, scoreRepo, userService, cfg.StaticConfig.Norms)
+	streakRepo := repository.NewStreakRepository(appMongo.GetDatabase())
+	streakService := service.NewStreakService(streakRepo, userService, cfg.StaticConfig.Streak)
+	xpService := service.NewXPService(userRepo, cfg.StaticConfig.XP)
+	scoreService := service.NewScoreService(scoreRepo, sessionRepo, flagRepo, idempotencyRepo, scorer, validator, outlierDetector, dashboardService, normsService, profileService, streakService, xpService, durationOr(cfg.StaticConfig.Idempotency.LockTimeout, time.Minute), cfg.StaticConfig.Batch)
+	cleanupService := service.NewCleanupService(scoreRepo, sessionRepo, dashboardRepo, profileService, tenants)
+	historyService := service.NewHistoryService(sessionRepo)
+	sessionService := service.NewSessionService(sessionRepo, scoreRepo)
+	challengeRepo := repository.NewChallengeRepository(appMongo.GetDatabase())
+	challengeAttemptRepo := repository.NewChallengeAttemptRepository(appMongo.GetDatabase())
+	challengeService := service.NewChallengeService(challengeRepo, challengeAttemptRepo, scoreService, userService, tenants)
+	ratingRepo := repository.NewRatingRepository(appMongo.GetDatabase())
+	ratingService := service.NewRatingService(ratingRepo, challengeRepo, challengeAttemptRepo, userService, tenants, cfg.StaticConfig.Ratings)
+	reviewService := service.NewReviewService(flagRepo, scoreRepo, sessionRepo, challengeAttemptRepo, dashboardService, profileService, tenants)
+
+	achievementRepo := repository.NewAchievementRepository(appMongo.GetDatabase())
+	achievementService := service.NewAchievementService(achievementRepo, sessionRepo, scoreRepo, cfg.StaticConfig.Achievements)
+	achievementService.Subscribe()
+
+	recommendationService := service.NewRecommendationService(scoreRepo, userService, recommend.NewEngine(), cfg.StaticConfig.Recommendations)
+
+	goalRepo := repository.NewGoalRepository(appMongo.GetDatabase())
+	goalService := service.NewGoalService(goalRepo, sessionRepo, scoreRepo, userService, cfg.StaticConfig.Goals)
+	goalService.Subscribe()
+	digestRepo := repository.NewDigestRepository(appMongo.GetDatabase())
+	digestService := service.NewDigestService(digestRepo, sessionRepo, scoreRepo, goalRepo, tenants)
+
+	renderer, err := notify.NewRenderer(notificationTemplates(cfg.StaticConfig.Notifications.Templates))
+	if err != nil {
+		log.Fatalf("Failed to parse notification templates: %v", err)
+	}
+	notificationRepo := repository.NewNotificationRepository(appMongo.GetDatabase())
+	notificationPrefsRepo := repository.NewNotificationPreferencesRepository(appMongo.GetDatabase())
+	duelRepo := repository.NewDuelRepository(appMongo.GetDatabase())
+	notificationService := service.NewNotificationService(notificationRepo, notificationPrefsRepo, streakRepo, goalRepo, digestRepo, duelRepo, userService, achievementService, tenants, notificationChannels(cfg.StaticConfig.Notifications), renderer, cfg.StaticConfig.Notifications)
+	notificationService.Subscribe()
+
+	socialRepo := repository.NewSocialRepository(appMongo.GetDatabase())
+	socialService := service.NewSocialService(socialRepo, userRepo, scoreRepo, cfg.StaticConfig.Social)
+	duelService := service.NewDuelService(duelRepo, socialService, scoreService, ratingService, cfg.StaticConfig.Duels)
+
+	matchRepo := repository.NewMatchRepository(appMongo.GetDatabase())
+	matchService := service.NewMatchService(matchRepo, scoreService, userService, ratingService, scorer, cfg.StaticConfig.Matches)
+
+	tournamentRepo := repository.NewTournamentRepository(appMongo.GetDatabase())
+	tournamentService := service.NewTournamentService(tournamentRepo, scoreService, ratingService, userService, tenants, cfg.StaticConfig.Tournaments)
+
+	groupRepo := repository.NewGroupRepository(appMongo.GetDatabase())
+	groupService := service.NewGroupService(groupRepo, scoreRepo, sessionRepo, userService, cfg.StaticConfig.Groups)
+
+	ensureIndexes(userRepo, sessionRepo, idempotencyRepo, challengeAttemptRepo, achievementRepo, goalRepo, digestRepo, notificationRepo, socialRepo, duelRepo, matchRepo, ratingRepo, tournamentRepo, groupRepo, tenantSettingsRepo, flagRepo)
+
+	scheduler.Every("norms_rebuild", cfg.StaticConfig.Norms.RebuildInterval, normsService.Rebuild)
+	scheduler.Every("profile_refresh", cfg.StaticConfig.Profile.RefreshInterval, profileService.RefreshAll)
+	scheduler.Every("weekly_digest", cfg.StaticConfig.Digest.Interval, digestService.GenerateWeekly)
+	scheduler.Every("notification_dispatch", cfg.StaticConfig.Notifications.DispatchInterval, notificationService.Dispatch)
+	scheduler.Every("streak_risk", cfg.StaticConfig.Notifications.StreakRisk.CheckInterval, notificationService.CheckStreakRisk)
+	scheduler.Every("duel_expiry", cfg.StaticConfig.Duels.ExpiryInterval, duelService.ExpireDue)
+	scheduler.Every("matchmaking", cfg.StaticConfig.Matches.MatchmakingInterval, matchService.Matchmake)
+	scheduler.Every("challenge_ratings", cfg.StaticConfig.Ratings.ChallengeInterval, ratingService.RateChallenges)
+	scheduler.Every("tournament_advance", cfg.StaticConfig.Tournaments.AdvanceInterval, tournamentService.Advance)
+	scheduler.Every("tenant_settings_sync", cfg.StaticConfig.Tenants.SettingsSync, tenants.Sync)
+	scheduler.Every("tenant_retention", cfg.StaticConfig.Tenants.RetentionInterval, cleanupService.ApplyRetention)
 
 	return &Controllers{
-		HealthController:    NewHealthController(),
-		AuthController:      NewAuthController(googleAuthService, userService, cfg.StaticConfig.Auth.JWTSecret),
-		DebugController:     NewDebugController(cfg, userService),
-		ScoreController:     NewScoreController(scorer, scoreService),
-		DashboardController: NewDashboardController(dashboardService),
-		CleanupController:   NewCleanupController(cleanupService),
+		HealthController:         NewHealthController(),
+		AuthController:           NewAuthController(googleAuthService, userService, xpService, cfg.StaticConfig.Auth.JWTSecret),
+		DebugController:          NewDebugController(cfg, userService),
+		ScoreController:          NewScoreController(scorer, validator, scoreService, normsService, streakService, ratingService),
+		DashboardController:      NewDashboardController(dashboardService),
+		CleanupController:        NewCleanupController(cleanupService),
+		ReviewController:         NewReviewController(reviewService),
+		ProfileController:        NewProfileController(profileService),
+		HistoryController:        NewHistoryController(historyService),
+		SessionController:        NewSessionController(sessionService),
+		ChallengeController:      NewChallengeController(challengeService),
+		StreakController:         NewStreakController(streakService, userService),
+		AchievementController:    NewAchievementController(achievementService),
+		RecommendationController: NewRecommendationController(recommendationService),
+		GoalController:           NewGoalController(goalService),
+		DigestController:         NewDigestController(digestService),
+		NotificationController:   NewNotificationController(notificationService),
+		SocialController:         NewSocialController(socialService),
+		DuelController:           NewDuelController(duelService),
+		MatchController:          NewMatchController(matchService),
+		RatingController:         NewRatingController(ratingService),
+		TournamentController:     NewTournamentController(tournamentService),
+		GroupController:          NewGroupController(groupService),
+		GameController:           NewGameController(tenants),
+		TenantController:         NewTenantController(tenants),
+		UserController:           NewUserController(userService, xpService),
+		Tenants:                  tenants,
+	}
+}
+
+// indexer is a repository that creates its MongoDB indexes.
+type indexer interface {
+	EnsureIndexes(ctx context.Context) error
+}
+
+// ensureIndexes creates indexes for the given repositories at startup. Failures are logged, not fatal.
+func ensureIndexes(repos ...indexer) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	for _, r := range repos {
+		if err := r.EnsureIndexes(ctx); err != nil {
+			log.Printf("Failed to ensure indexes: %v", err)
+		}
+	}
+}
+
+// notificationChannels builds the delivery channels that have settings. The log channel is always available.
+func notificationChannels(cfg config.NotificationsConfig) *notify.Registry {
+	timeout := durationOr(cfg.SendTimeout, 10*time.Second)
+	channels := []notify.Channel{notify.NewLogChannel()}
+	if cfg.File.Path != "" {
+		channels = append(channels, notify.NewFileChannel(cfg.File.Path))
+	}
+	if cfg.Webhook.URL != "" {
+		channels = append(channels, notify.NewWebhookChannel(notify.ChannelWebhook, cfg.Webhook.URL, timeout))
+	}
+	if cfg.Push.GatewayURL != "" {
+		channels = append(channels, notify.NewWebhookChannel(notify.ChannelPush, cfg.Push.GatewayURL, timeout))
+	}
+	if cfg.Email.Host != "" {
+		channels = append(channels, notify.NewEmailChannel(cfg.Email.Host, cfg.Email.Port, cfg.Email.Username, cfg.Email.Password, cfg.Email.From))
+	}
+	return notify.NewRegistry(channels...)
+}
+
+// notificationTemplates converts configured template overrides to the notify package's type.
+func notificationTemplates(cfg map[string]config.NotificationTemplate) map[string]notify.Template {
+	out := make(map[string]notify.Template, len(cfg))
+	for kind, t := range cfg {
+		out[kind] = notify.Template{Subject: t.Subject, Body: t.Body}
+	}
+	return out
+}
+
+// durationOr returns d, or fallback when d is not configured.
+func durationOr(d, fallback time.Duration) time.Duration {
+	if d <= 0 {
+		return fallback
 	}
+	return d
 }
 
 // splitTrim splits s by sep and trims each element; returns nil for empty s.
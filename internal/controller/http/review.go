@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/service"
+	"brainbash_backend/internal/utils"
+)
+
+const defaultFlagListLimit = 50
+
+// ReviewController exposes the admin review queue for flagged sessions.
+type ReviewController struct {
+	reviewService *service.ReviewService
+}
+
+// NewReviewController creates a new ReviewController.
+func NewReviewController(reviewService *service.ReviewService) *ReviewController {
+	return &ReviewController{
+		reviewService: reviewService,
+	}
+}
+
+// ListFlags handles GET /api/admin/flags?status=pending&limit=50.
+func (rc *ReviewController) ListFlags(c *gin.Context) {
+	status := c.DefaultQuery("status", entity.ReviewPending)
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", strconv.Itoa(defaultFlagListLimit)), 10, 64)
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+		return
+	}
+
+	flags, err := rc.reviewService.ListFlags(c.Request.Context(), status, limit)
+	if err != nil {
+		log.Printf("ListFlags: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load flags"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"flags": flags})
+}
+
+// Approve handles POST /api/admin/flags/:flag_id/approve. Restores leaderboard eligibility.
+func (rc *ReviewController) Approve(c *gin.Context) {
+	flag, err := rc.reviewService.Approve(c.Request.Context(), c.Param("flag_id"), utils.GetUserIDFromContext(c))
+	if err != nil {
+		respondReviewError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, flag)
+}
+
+// Reject handles POST /api/admin/flags/:flag_id/reject. Removes the session and recomputes aggregates.
+func (rc *ReviewController) Reject(c *gin.Context) {
+	flag, err := rc.reviewService.Reject(c.Request.Context(), c.Param("flag_id"), utils.GetUserIDFromContext(c))
+	if err != nil {
+		respondReviewError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, flag)
+}
+
+func respondReviewError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrFlagNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrFlagReviewed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Printf("Review: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "review failed"})
+	}
+}
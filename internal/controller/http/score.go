@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"log"
 	"net/http"
 
@@ -13,19 +14,33 @@ import (
 	"brainbash_backend/internal/scoring"
 	"brainbash_backend/internal/service"
 	"brainbash_backend/internal/utils"
+	"brainbash_backend/internal/validation"
+)
+
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
 )
 
 // ScoreController handles score calculation, game result submission, and user stats.
 type ScoreController struct {
-	scorer       *scoring.Scorer
-	scoreService *service.ScoreService
+	scorer        *scoring.Scorer
+	validator     *validation.SessionValidator
+	scoreService  *service.ScoreService
+	normsService  *service.NormsService
+	streakService *service.StreakService
+	ratingService *service.RatingService
 }
 
 // NewScoreController creates a new ScoreController.
-func NewScoreController(scorer *scoring.Scorer, scoreService *service.ScoreService) *ScoreController {
+func NewScoreController(scorer *scoring.Scorer, validator *validation.SessionValidator, scoreService *service.ScoreService, normsService *service.NormsService, streakService *service.StreakService, ratingService *service.RatingService) *ScoreController {
 	return &ScoreController{
-		scorer:       scorer,
-		scoreService: scoreService,
+		scorer:        scorer,
+		validator:     validator,
+		scoreService:  scoreService,
+		normsService:  normsService,
+		streakService: streakService,
+		ratingService: ratingService,
 	}
 }
 
@@ -37,6 +52,11 @@ func (sc *ScoreController) Calculate(c *gin.Context) {
 		return
 	}
 
+	if _, err := sc.validator.Validate("", req.Strategy, req.QuestionResponses); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	result, err := sc.scorer.Calculate(req.Strategy, req.QuestionResponses)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -62,7 +82,12 @@ func (sc *ScoreController) GameCalculate(c *gin.Context) {
 	}
 
 	gt := game.GameType(req.GameType)
-	if err := gt.Validate(); err != nil {
+	if err := service.ValidateGameType(c.Request.Context(), req.GameType); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := sc.validator.Validate(req.GameType, gt.StrategyFor(), req.QuestionResponses); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -83,6 +108,7 @@ func (sc *ScoreController) GameCalculate(c *gin.Context) {
 }
 
 // GameResult handles POST /api/game/result. Calculates score, stores session in score collection, returns result.
+// Retries carrying the same Idempotency-Key header (or body session_id) return the original result.
 func (sc *ScoreController) GameResult(c *gin.Context) {
 	userID := utils.GetUserIDFromContext(c)
 	if userID == "" {
@@ -96,20 +122,90 @@ func (sc *ScoreController) GameResult(c *gin.Context) {
 		return
 	}
 
-	result, err := sc.scoreService.SubmitGameResult(c.Request.Context(), userID, req)
+	result, replayed, err := sc.scoreService.SubmitGameResult(c.Request.Context(), userID, c.GetHeader(idempotencyKeyHeader), req)
 	if err != nil {
 		log.Printf("GameResult SubmitGameResult: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		switch {
+		case errors.Is(err, service.ErrSubmissionInProgress):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrIdempotencyKeyReused):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
-	c.JSON(http.StatusOK, response.ScoringResponse{
-		Score:     result.Score,
-		Questions: result.Questions,
-		Correct:   result.Correct,
-		Accuracy:  result.Accuracy,
-		AvgTime:   result.AvgTime,
-	})
+	if replayed {
+		c.Header(idempotentReplayedHeader, "true")
+	}
+	c.JSON(http.StatusOK, toGameResultResponse(result))
+}
+
+// GameResultBatch handles POST /api/game/results/batch. Stores sessions played offline with their played_at
+// timestamps and returns a result per session; one invalid session does not reject the rest.
+func (sc *ScoreController) GameResultBatch(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	var req request.GameResultBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sessions is required"})
+		return
+	}
+
+	results, err := sc.scoreService.SubmitBatch(c.Request.Context(), userID, req.Sessions)
+	if err != nil {
+		log.Printf("GameResultBatch SubmitBatch: %v", err)
+		if errors.Is(err, service.ErrEmptyBatch) || errors.Is(err, service.ErrBatchTooLarge) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to store sessions"})
+		return
+	}
+
+	resp := response.GameResultBatchResponse{Results: make([]response.BatchItemResponse, 0, len(results))}
+	for _, r := range results {
+		item := response.BatchItemResponse{SessionID: r.SessionID}
+		switch {
+		case r.Err != nil:
+			item.Status = "error"
+			item.Error = r.Err.Error()
+			resp.Failed++
+		case r.Replayed:
+			item.Status = "replayed"
+			resp.Replayed++
+		default:
+			item.Status = "created"
+			resp.Created++
+		}
+		if r.Result != nil {
+			result := toGameResultResponse(r.Result)
+			item.Result = &result
+		}
+		resp.Results = append(resp.Results, item)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// toGameResultResponse maps a stored game result to the scoring response body.
+func toGameResultResponse(result *entity.GameResult) response.ScoringResponse {
+	return response.ScoringResponse{
+		Score:      result.SessionScore.Score,
+		Questions:  result.SessionScore.Questions,
+		Correct:    result.SessionScore.Correct,
+		Accuracy:   result.SessionScore.Accuracy,
+		AvgTime:    result.SessionScore.AvgTime,
+		Percentile: result.Percentile,
+		SessionID:  result.SessionID,
+		XPGained:   result.XPGained,
+		Level:      result.Level,
+		LevelUp:    result.LeveledUp,
+	}
 }
 
 // UserStats handles GET /api/user/stats. Returns the authenticated user's scores per game type.
@@ -126,21 +222,40 @@ func (sc *ScoreController) UserStats(c *gin.Context) {
 		return
 	}
 
-	// Response: { overall_score, <gametype>: { avg_score, max_score }, ... } — all game types included, 0 when no data
+	ratings, err := sc.ratingService.ForUser(c.Request.Context(), userID)
+	if err != nil {
+		log.Printf("UserStats ratings: %v", err)
+	}
+
+	// Response: { overall_score, streak, <gametype>: { avg_score, max_score, avg_percentile, max_percentile, rating }, ... }
+	// — all game types included, 0 when no data
 	out := make(map[string]interface{})
 	out["overall_score"] = 0.0
 	if score != nil {
 		out["overall_score"] = score.OverallScore
 	}
+	ageBand := sc.normsService.AgeBandFor(c.Request.Context(), userID)
 	for _, kv := range gameTypeKeysAndValues(score) {
+		stats := response.GameTypeStats{AvgScore: 0, MaxScore: 0}
 		if kv.value != nil {
-			out[kv.key] = response.GameTypeStats{
-				AvgScore: kv.value.AvgScore,
-				MaxScore: kv.value.HighScore,
+			stats = response.GameTypeStats{
+				AvgScore:      kv.value.AvgScore,
+				MaxScore:      kv.value.HighScore,
+				AvgPercentile: sc.normsService.Percentile(c.Request.Context(), kv.key, ageBand, kv.value.AvgScore),
+				MaxPercentile: sc.normsService.Percentile(c.Request.Context(), kv.key, ageBand, kv.value.HighScore),
 			}
-		} else {
-			out[kv.key] = response.GameTypeStats{AvgScore: 0, MaxScore: 0}
 		}
+		if r := ratings[kv.key]; r != nil {
+			resp := toRatingResponse(r, sc.ratingService)
+			stats.Rating = &resp
+		}
+		out[kv.key] = stats
+	}
+
+	if streak, err := sc.streakService.GetStreak(c.Request.Context(), userID); err != nil {
+		log.Printf("UserStats GetStreak: %v", err)
+	} else {
+		out["streak"] = toStreakResponse(streak)
 	}
 
 	c.JSON(http.StatusOK, out)
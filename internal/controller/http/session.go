@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/model/response"
+	"brainbash_backend/internal/service"
+	"brainbash_backend/internal/utils"
+)
+
+const (
+	defaultSessionPageSize = 20
+	maxSessionPageSize     = 100
+)
+
+// SessionController serves the authenticated user's past sessions.
+type SessionController struct {
+	sessionService *service.SessionService
+}
+
+// NewSessionController creates a new SessionController.
+func NewSessionController(sessionService *service.SessionService) *SessionController {
+	return &SessionController{
+		sessionService: sessionService,
+	}
+}
+
+// ListSessions handles GET /api/user/sessions?gametype=&cursor=&limit=. Newest first; pass next_cursor
+// from the previous page to continue.
+func (sc *SessionController) ListSessions(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", strconv.Itoa(defaultSessionPageSize)), 10, 64)
+	if err != nil || limit <= 0 || limit > maxSessionPageSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+		return
+	}
+
+	page, err := sc.sessionService.ListSessions(c.Request.Context(), userID, c.Query("gametype"), c.Query("cursor"), limit)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cursor"})
+			return
+		}
+		log.Printf("ListSessions: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	out := response.SessionListResponse{
+		Sessions:   make([]response.SessionSummary, 0, len(page.Sessions)),
+		NextCursor: page.NextCursor,
+	}
+	for _, r := range page.Sessions {
+		out.Sessions = append(out.Sessions, toSessionSummary(r))
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// GetSession handles GET /api/user/sessions/:session_id. Returns the session with its question_responses.
+func (sc *SessionController) GetSession(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	record, err := sc.sessionService.GetSession(c.Request.Context(), userID, c.Param("session_id"))
+	if err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("GetSession: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load session"})
+		return
+	}
+
+	out := response.SessionDetailResponse{
+		SessionSummary:    toSessionSummary(record),
+		QuestionResponses: make([]response.QuestionResponse, 0, len(record.QuestionResponses)),
+	}
+	for _, qr := range record.QuestionResponses {
+		out.QuestionResponses = append(out.QuestionResponses, response.QuestionResponse{
+			TimeTaken: qr.TimeTaken,
+			Outcome:   qr.Outcome,
+		})
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// Backfill handles POST /api/admin/sessions/backfill. Copies sessions embedded in score documents
+// into the sessions collection (idempotent).
+func (sc *SessionController) Backfill(c *gin.Context) {
+	inserted, err := sc.sessionService.Backfill(c.Request.Context())
+	if err != nil {
+		log.Printf("Sessions Backfill: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "backfill failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"inserted": inserted})
+}
+
+func toSessionSummary(r *entity.SessionRecord) response.SessionSummary {
+	return response.SessionSummary{
+		SessionID: r.SessionID,
+		GameType:  r.GameType,
+		SessionScore: response.ScoringResponse{
+			Score:     r.SessionScore.Score,
+			Questions: r.SessionScore.Questions,
+			Correct:   r.SessionScore.Correct,
+			Accuracy:  r.SessionScore.Accuracy,
+			AvgTime:   r.SessionScore.AvgTime,
+		},
+		Timestamp:    r.Timestamp,
+		Flags:        r.Flags,
+		ReviewStatus: r.ReviewStatus,
+	}
+}
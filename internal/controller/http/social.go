@@ -0,0 +1,273 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"brainbash_backend/internal/game"
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/model/request"
+	"brainbash_backend/internal/model/response"
+	"brainbash_backend/internal/service"
+	"brainbash_backend/internal/utils"
+)
+
+// SocialController handles follows, friend requests, blocks, friend codes and the friends leaderboard.
+type SocialController struct {
+	socialService *service.SocialService
+}
+
+// NewSocialController creates a new SocialController.
+func NewSocialController(socialService *service.SocialService) *SocialController {
+	return &SocialController{
+		socialService: socialService,
+	}
+}
+
+// FriendCode handles GET /api/user/friend-code. Returns the user's code and invite link, creating the code on first use.
+func (sc *SocialController) FriendCode(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	code, inviteURL, err := sc.socialService.FriendCode(c.Request.Context(), userID)
+	if err != nil {
+		sc.writeError(c, "FriendCode", err)
+		return
+	}
+	c.JSON(http.StatusOK, response.FriendCodeResponse{FriendCode: code, InviteURL: inviteURL})
+}
+
+// LookupFriendCode handles GET /api/friends/code/:code. Shows who is behind a code before sending a request.
+func (sc *SocialController) LookupFriendCode(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	user, err := sc.socialService.LookupFriendCode(c.Request.Context(), userID, c.Param("code"))
+	if err != nil {
+		sc.writeError(c, "LookupFriendCode", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"user": toUserSummary(user)})
+}
+
+// Connections handles GET /api/friends. Returns friends, followed users and followers.
+func (sc *SocialController) Connections(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	conns, err := sc.socialService.GetConnections(c.Request.Context(), userID)
+	if err != nil {
+		sc.writeError(c, "Connections", err)
+		return
+	}
+	c.JSON(http.StatusOK, response.ConnectionsResponse{
+		Friends:   toUserSummaries(conns.Friends),
+		Following: toUserSummaries(conns.Following),
+		Followers: toUserSummaries(conns.Followers),
+	})
+}
+
+// Unfriend handles DELETE /api/friends/:user_id.
+func (sc *SocialController) Unfriend(c *gin.Context) {
+	sc.relation(c, "Unfriend", sc.socialService.Unfriend)
+}
+
+// ListRequests handles GET /api/friends/requests. Returns pending incoming and outgoing requests.
+func (sc *SocialController) ListRequests(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	reqs, err := sc.socialService.ListFriendRequests(c.Request.Context(), userID)
+	if err != nil {
+		sc.writeError(c, "ListRequests", err)
+		return
+	}
+	resp := response.FriendRequestsResponse{
+		Incoming: make([]response.FriendRequestResponse, 0, len(reqs.Incoming)),
+		Outgoing: make([]response.FriendRequestResponse, 0, len(reqs.Outgoing)),
+	}
+	for _, r := range reqs.Incoming {
+		resp.Incoming = append(resp.Incoming, response.FriendRequestResponse{User: toUserSummary(r.User), CreatedAt: r.CreatedAt})
+	}
+	for _, r := range reqs.Outgoing {
+		resp.Outgoing = append(resp.Outgoing, response.FriendRequestResponse{User: toUserSummary(r.User), CreatedAt: r.CreatedAt})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// SendRequest handles POST /api/friends/requests. If the other user already asked, the friendship is
+// accepted right away (status "accepted").
+func (sc *SocialController) SendRequest(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	var req request.FriendRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil || (req.UserID == "") == (req.FriendCode == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "exactly one of user_id and friend_code is required"})
+		return
+	}
+	status, err := sc.socialService.SendFriendRequest(c.Request.Context(), userID, req.UserID, req.FriendCode)
+	if err != nil {
+		sc.writeError(c, "SendRequest", err)
+		return
+	}
+	code := http.StatusCreated
+	if status == entity.FriendRequestAccepted {
+		code = http.StatusOK
+	}
+	c.JSON(code, gin.H{"status": status})
+}
+
+// AcceptRequest handles POST /api/friends/requests/:user_id/accept.
+func (sc *SocialController) AcceptRequest(c *gin.Context) {
+	sc.relation(c, "AcceptRequest", sc.socialService.AcceptFriendRequest)
+}
+
+// DeclineRequest handles POST /api/friends/requests/:user_id/decline.
+func (sc *SocialController) DeclineRequest(c *gin.Context) {
+	sc.relation(c, "DeclineRequest", sc.socialService.DeclineFriendRequest)
+}
+
+// CancelRequest handles DELETE /api/friends/requests/:user_id. Withdraws a request the user sent.
+func (sc *SocialController) CancelRequest(c *gin.Context) {
+	sc.relation(c, "CancelRequest", sc.socialService.CancelFriendRequest)
+}
+
+// Follow handles PUT /api/follows/:user_id.
+func (sc *SocialController) Follow(c *gin.Context) {
+	sc.relation(c, "Follow", sc.socialService.Follow)
+}
+
+// Unfollow handles DELETE /api/follows/:user_id.
+func (sc *SocialController) Unfollow(c *gin.Context) {
+	sc.relation(c, "Unfollow", sc.socialService.Unfollow)
+}
+
+// ListBlocked handles GET /api/blocks.
+func (sc *SocialController) ListBlocked(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	users, err := sc.socialService.ListBlocked(c.Request.Context(), userID)
+	if err != nil {
+		sc.writeError(c, "ListBlocked", err)
+		return
+	}
+	c.JSON(http.StatusOK, response.BlockedUsersResponse{Blocked: toUserSummaries(users)})
+}
+
+// Block handles PUT /api/blocks/:user_id. Removes any friendship, follows and requests between the users.
+func (sc *SocialController) Block(c *gin.Context) {
+	sc.relation(c, "Block", sc.socialService.Block)
+}
+
+// Unblock handles DELETE /api/blocks/:user_id.
+func (sc *SocialController) Unblock(c *gin.Context) {
+	sc.relation(c, "Unblock", sc.socialService.Unblock)
+}
+
+// FriendsLeaderboard handles GET /api/dashboard/friends?gametype=&window=all|day|week|month.
+// Ranks the user among friends and followed users; without gametype the composite score is used.
+func (sc *SocialController) FriendsLeaderboard(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	gameType, window := c.Query("gametype"), c.DefaultQuery("window", service.WindowAll)
+	if gameType != "" {
+		if err := game.GameType(gameType).Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	entries, err := sc.socialService.FriendsLeaderboard(c.Request.Context(), userID, gameType, window)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidWindow) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		sc.writeError(c, "FriendsLeaderboard", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"gametype": gameType, "window": window, "leaderboard": toLeaderboardResponse(entries)})
+}
+
+func toLeaderboardResponse(entries []service.LeaderboardEntry) []response.LeaderboardEntry {
+	out := make([]response.LeaderboardEntry, 0, len(entries))
+	for i, e := range entries {
+		out = append(out, response.LeaderboardEntry{
+			Rank:     i + 1,
+			User:     toUserSummary(e.User),
+			Score:    e.Score,
+			Sessions: e.Sessions,
+			IsMe:     e.IsMe,
+		})
+	}
+	return out
+}
+
+// relation runs a user-to-user action on the :user_id path parameter and answers 204 on success.
+func (sc *SocialController) relation(c *gin.Context, op string, fn func(ctx context.Context, userID, targetID string) error) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+	if err := fn(c.Request.Context(), userID, c.Param("user_id")); err != nil {
+		sc.writeError(c, op, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (sc *SocialController) writeError(c *gin.Context, op string, err error) {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound), errors.Is(err, service.ErrFriendRequestNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrSelfRelation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrBlocked):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrAlreadyFriends), errors.Is(err, service.ErrSocialLimit):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Printf("Social %s: %v", op, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
+
+func toUserSummary(u *entity.User) response.CompositeUserSummary {
+	return response.CompositeUserSummary{ID: u.UserID.Hex(), Name: u.PublicName(), Photo: u.PublicPicture()}
+}
+
+func toUserSummaries(users []*entity.User) []response.CompositeUserSummary {
+	out := make([]response.CompositeUserSummary, 0, len(users))
+	for _, u := range users {
+		out = append(out, toUserSummary(u))
+	}
+	return out
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"brainbash_backend/internal/model/request"
+	"brainbash_backend/internal/model/response"
+	"brainbash_backend/internal/service"
+	"brainbash_backend/internal/utils"
+)
+
+// StreakController serves the authenticated user's daily play streak and the timezone it is counted in.
+type StreakController struct {
+	streakService *service.StreakService
+	userService   *service.UserService
+}
+
+// NewStreakController creates a new StreakController.
+func NewStreakController(streakService *service.StreakService, userService *service.UserService) *StreakController {
+	return &StreakController{
+		streakService: streakService,
+		userService:   userService,
+	}
+}
+
+// GetStreak handles GET /api/user/streak.
+func (sc *StreakController) GetStreak(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	status, err := sc.streakService.GetStreak(c.Request.Context(), userID)
+	if err != nil {
+		log.Printf("GetStreak: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load streak"})
+		return
+	}
+	c.JSON(http.StatusOK, toStreakResponse(status))
+}
+
+// SetTimezone handles PUT /api/user/timezone. Streak days are counted from midnight in this timezone.
+func (sc *StreakController) SetTimezone(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	var req request.TimezoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "timezone is required"})
+		return
+	}
+	if err := sc.userService.SetTimezone(c.Request.Context(), userID, req.Timezone); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"timezone": req.Timezone})
+}
+
+func toStreakResponse(status *service.StreakStatus) response.StreakResponse {
+	return response.StreakResponse{
+		Current:        status.Current,
+		Longest:        status.Longest,
+		LastPlayedDay:  status.LastPlayedDay,
+		PlayedToday:    status.PlayedToday,
+		FreezeTokens:   status.FreezeTokens,
+		FreezesPending: status.FreezesPending,
+		FreezesUsed:    status.FreezesUsed,
+		NextFreezeIn:   status.NextFreezeIn,
+		Timezone:       status.Timezone,
+	}
+}
@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/model/request"
+	"brainbash_backend/internal/model/response"
+	"brainbash_backend/internal/service"
+	"brainbash_backend/internal/tenant"
+	"brainbash_backend/internal/utils"
+	"brainbash_backend/internal/validation"
+)
+
+const settingsDay = 24 * time.Hour // unit of the settings given in days
+
+// TenantController serves tenant settings: to a tenant's admins for their own tenant, and to global
+// admins for any tenant.
+type TenantController struct {
+	tenantService *service.TenantService
+}
+
+// NewTenantController creates a new TenantController.
+func NewTenantController(tenantService *service.TenantService) *TenantController {
+	return &TenantController{
+		tenantService: tenantService,
+	}
+}
+
+// GetSettings handles GET /api/tenant/settings [tenant admin] and GET /api/admin/tenants/:tenant_id/settings [admin].
+func (tc *TenantController) GetSettings(c *gin.Context) {
+	settings, err := tc.tenantService.Settings(c.Request.Context(), settingsTenantID(c))
+	if err != nil {
+		tc.writeError(c, "GetSettings", err)
+		return
+	}
+	c.JSON(http.StatusOK, toTenantSettingsResponse(settings))
+}
+
+// UpdateSettings handles PUT /api/tenant/settings [tenant admin] and PUT /api/admin/tenants/:tenant_id/settings [admin].
+// Replaces the tenant's settings; they take effect on this instance right away and on others within the sync interval.
+func (tc *TenantController) UpdateSettings(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+	var req request.TenantSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.ScoringHalfLifeDays < 0 || req.RetentionDays < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "scoring_half_life_days and retention_days must not be negative"})
+		return
+	}
+
+	settings, err := tc.tenantService.UpdateSettings(c.Request.Context(), settingsTenantID(c), userID, &entity.TenantSettings{
+		Name:            req.Name,
+		GameTypes:       req.GameTypes,
+		GameLabels:      req.GameLabels,
+		ScoringWeights:  req.ScoringWeights,
+		ScoringHalfLife: time.Duration(req.ScoringHalfLifeDays * float64(settingsDay)),
+		Leaderboards:    req.Leaderboards,
+		Retention:       time.Duration(req.RetentionDays) * settingsDay,
+		CORSOrigins:     req.CORSOrigins,
+	})
+	if err != nil {
+		tc.writeError(c, "UpdateSettings", err)
+		return
+	}
+	c.JSON(http.StatusOK, toTenantSettingsResponse(settings))
+}
+
+func (tc *TenantController) writeError(c *gin.Context, op string, err error) {
+	var vErr *validation.Error
+	switch {
+	case errors.Is(err, service.ErrTenantNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.As(err, &vErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Printf("Tenant %s: %v", op, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
+
+// settingsTenantID is the :tenant_id of admin routes, or else the request's tenant.
+func settingsTenantID(c *gin.Context) string {
+	if id := c.Param("tenant_id"); id != "" {
+		return id
+	}
+	if t := tenant.FromContext(c.Request.Context()); t != nil {
+		return t.ID
+	}
+	return tenant.DefaultID
+}
+
+func toTenantSettingsResponse(s *entity.TenantSettings) response.TenantSettingsResponse {
+	resp := response.TenantSettingsResponse{
+		TenantID:            s.TenantID,
+		Name:                s.Name,
+		GameTypes:           s.GameTypes,
+		GameLabels:          s.GameLabels,
+		ScoringWeights:      s.ScoringWeights,
+		ScoringHalfLifeDays: math.Round(s.ScoringHalfLife.Hours()/24*100) / 100,
+		Leaderboards:        s.Leaderboards,
+		RetentionDays:       int(s.Retention / settingsDay),
+		CORSOrigins:         s.CORSOrigins,
+		UpdatedBy:           s.UpdatedBy,
+	}
+	if !s.UpdatedAt.IsZero() {
+		resp.UpdatedAt = &s.UpdatedAt
+	}
+	return resp
+}
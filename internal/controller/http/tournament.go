@@ -0,0 +1,345 @@
+package controller
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"brainbash_backend/internal/game"
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/model/request"
+	"brainbash_backend/internal/model/response"
+	"brainbash_backend/internal/service"
+	"brainbash_backend/internal/utils"
+	"brainbash_backend/internal/validation"
+)
+
+const defaultTournamentListLimit = 20
+
+// TournamentController handles tournaments: admin creation, entries, pairings, results and standings.
+type TournamentController struct {
+	tournamentService *service.TournamentService
+}
+
+// NewTournamentController creates a new TournamentController.
+func NewTournamentController(tournamentService *service.TournamentService) *TournamentController {
+	return &TournamentController{
+		tournamentService: tournamentService,
+	}
+}
+
+// Create handles POST /api/admin/tournaments. Creates a tournament taking entries until entry_closes_at.
+func (tc *TournamentController) Create(c *gin.Context) {
+	var req request.CreateTournamentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name, gametype, format, entry_closes_at, rounds and round_duration are required"})
+		return
+	}
+
+	t, err := tc.tournamentService.Create(c.Request.Context(), utils.GetUserIDFromContext(c), req)
+	if err != nil {
+		tc.writeError(c, "Create", err)
+		return
+	}
+	c.JSON(http.StatusCreated, toTournamentResponse(t))
+}
+
+// Cancel handles POST /api/admin/tournaments/:tournament_id/cancel.
+func (tc *TournamentController) Cancel(c *gin.Context) {
+	t, err := tc.tournamentService.Cancel(c.Request.Context(), c.Param("tournament_id"))
+	if err != nil {
+		tc.writeError(c, "Cancel", err)
+		return
+	}
+	c.JSON(http.StatusOK, toTournamentResponse(t))
+}
+
+// List handles GET /api/tournaments?status=&limit=20. Latest entry window first (public).
+func (tc *TournamentController) List(c *gin.Context) {
+	status := c.Query("status")
+	switch status {
+	case "", entity.TournamentRegistration, entity.TournamentRunning, entity.TournamentCompleted, entity.TournamentCancelled:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be registration, running, completed or cancelled"})
+		return
+	}
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", strconv.Itoa(defaultTournamentListLimit)), 10, 64)
+	if err != nil || limit <= 0 || limit > maxLeaderboardLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+		return
+	}
+
+	tournaments, err := tc.tournamentService.List(c.Request.Context(), status, limit)
+	if err != nil {
+		tc.writeError(c, "List", err)
+		return
+	}
+	resp := response.TournamentListResponse{Tournaments: make([]response.TournamentResponse, 0, len(tournaments))}
+	for _, t := range tournaments {
+		resp.Tournaments = append(resp.Tournaments, toTournamentResponse(t))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Get handles GET /api/tournaments/:tournament_id (public).
+func (tc *TournamentController) Get(c *gin.Context) {
+	t, err := tc.tournamentService.Get(c.Request.Context(), c.Param("tournament_id"))
+	if err != nil {
+		tc.writeError(c, "Get", err)
+		return
+	}
+	c.JSON(http.StatusOK, toTournamentResponse(t))
+}
+
+// Standings handles GET /api/tournaments/:tournament_id/standings (public).
+func (tc *TournamentController) Standings(c *gin.Context) {
+	t, standings, err := tc.tournamentService.Standings(c.Request.Context(), c.Param("tournament_id"))
+	if err != nil {
+		tc.writeError(c, "Standings", err)
+		return
+	}
+	users, err := tc.tournamentService.Players(c.Request.Context(), nil, standings)
+	if err != nil {
+		tc.writeError(c, "Standings", err)
+		return
+	}
+
+	resp := response.TournamentStandingsResponse{
+		Tournament: toTournamentResponse(t),
+		Standings:  make([]response.TournamentStandingResponse, 0, len(standings)),
+	}
+	for _, st := range standings {
+		p := st.Player
+		resp.Standings = append(resp.Standings, response.TournamentStandingResponse{
+			Place:           st.Place,
+			User:            tournamentUser(p.UserID, users),
+			Seed:            p.Seed,
+			Points:          p.Points,
+			Wins:            p.Wins,
+			Draws:           p.Draws,
+			Losses:          p.Losses,
+			NoShows:         p.NoShows,
+			ScoreTotal:      p.ScoreTotal,
+			EliminatedRound: p.EliminatedRound,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Pairings handles GET /api/tournaments/:tournament_id/pairings?round=. Lists a round's games, every
+// round's when round is omitted (public).
+func (tc *TournamentController) Pairings(c *gin.Context) {
+	round, err := strconv.Atoi(c.DefaultQuery("round", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "round must be a number"})
+		return
+	}
+
+	t, pairings, err := tc.tournamentService.Pairings(c.Request.Context(), c.Param("tournament_id"), round)
+	if err != nil {
+		tc.writeError(c, "Pairings", err)
+		return
+	}
+	users, err := tc.tournamentService.Players(c.Request.Context(), pairings, nil)
+	if err != nil {
+		tc.writeError(c, "Pairings", err)
+		return
+	}
+
+	resp := response.TournamentPairingsResponse{
+		Tournament: toTournamentResponse(t),
+		Pairings:   make([]response.TournamentPairingResponse, 0, len(pairings)),
+	}
+	for _, p := range pairings {
+		resp.Pairings = append(resp.Pairings, toTournamentPairingResponse(p, "", users))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Join handles POST /api/tournaments/:tournament_id/join while entries are open.
+func (tc *TournamentController) Join(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	p, err := tc.tournamentService.Join(c.Request.Context(), userID, c.Param("tournament_id"))
+	if err != nil {
+		tc.writeError(c, "Join", err)
+		return
+	}
+	c.JSON(http.StatusCreated, response.TournamentEntryResponse{TournamentID: p.TournamentID, JoinedAt: p.JoinedAt})
+}
+
+// Leave handles DELETE /api/tournaments/:tournament_id/join while entries are open.
+func (tc *TournamentController) Leave(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	if err := tc.tournamentService.Leave(c.Request.Context(), userID, c.Param("tournament_id")); err != nil {
+		tc.writeError(c, "Leave", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Pairing handles GET /api/tournaments/:tournament_id/pairing. Returns the user's game in the current
+// round with the seed to play it with.
+func (tc *TournamentController) Pairing(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	_, pairing, err := tc.tournamentService.CurrentPairing(c.Request.Context(), userID, c.Param("tournament_id"))
+	if err != nil {
+		tc.writeError(c, "Pairing", err)
+		return
+	}
+	users, err := tc.tournamentService.Players(c.Request.Context(), []*entity.TournamentPairing{pairing}, nil)
+	if err != nil {
+		tc.writeError(c, "Pairing", err)
+		return
+	}
+	c.JSON(http.StatusOK, toTournamentPairingResponse(pairing, userID, users))
+}
+
+// Submit handles POST /api/tournaments/:tournament_id/result. Stores the user's single session for the
+// current round. Retrying with the same session_id and responses replays it.
+func (tc *TournamentController) Submit(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+
+	var req request.TournamentResultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "question_responses is required"})
+		return
+	}
+
+	pairing, result, replayed, err := tc.tournamentService.Submit(c.Request.Context(), userID, c.Param("tournament_id"), req)
+	if err != nil {
+		log.Printf("Tournament Submit: %v", err)
+		switch {
+		case errors.Is(err, service.ErrTournamentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrTournamentNoGame), errors.Is(err, service.ErrTournamentPlayed),
+			errors.Is(err, service.ErrSubmissionInProgress):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	users, err := tc.tournamentService.Players(c.Request.Context(), []*entity.TournamentPairing{pairing}, nil)
+	if err != nil {
+		log.Printf("Tournament Players: %v", err)
+	}
+	if replayed {
+		c.Header(idempotentReplayedHeader, "true")
+	}
+	c.JSON(http.StatusOK, response.TournamentResultResponse{
+		Pairing: toTournamentPairingResponse(pairing, userID, users),
+		Result:  toGameResultResponse(result),
+	})
+}
+
+func (tc *TournamentController) writeError(c *gin.Context, op string, err error) {
+	var vErr *validation.Error
+	switch {
+	case errors.Is(err, service.ErrTournamentNotFound), errors.Is(err, service.ErrTournamentNotJoined):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrTournamentClosed), errors.Is(err, service.ErrTournamentFull),
+		errors.Is(err, service.ErrTournamentJoined), errors.Is(err, service.ErrTournamentNoGame),
+		errors.Is(err, service.ErrTournamentFinished):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.As(err, &vErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Printf("Tournament %s: %v", op, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
+
+func toTournamentResponse(t *entity.Tournament) response.TournamentResponse {
+	return response.TournamentResponse{
+		TournamentID:  t.ID,
+		Name:          t.Name,
+		GameType:      t.GameType,
+		Label:         game.GameType(t.GameType).Label(),
+		Format:        t.Format,
+		Status:        t.Status,
+		EntryOpensAt:  t.EntryOpensAt,
+		EntryClosesAt: t.EntryClosesAt,
+		Rounds:        t.Rounds,
+		TotalRounds:   t.TotalRounds,
+		RoundDuration: t.RoundDuration.String(),
+		MaxPlayers:    t.MaxPlayers,
+		CurrentRound:  t.CurrentRound,
+		RoundEndsAt:   t.RoundEndsAt,
+		CreatedAt:     t.CreatedAt,
+		StartedAt:     t.StartedAt,
+		CompletedAt:   t.CompletedAt,
+	}
+}
+
+// toTournamentPairingResponse maps a pairing as seen by userID ("" for the public). Results are shown
+// once the pairing is settled; players also see their own result and the seed.
+func toTournamentPairingResponse(p *entity.TournamentPairing, userID string, users map[string]*entity.User) response.TournamentPairingResponse {
+	resp := response.TournamentPairingResponse{
+		Round:       p.Round,
+		Table:       p.Table,
+		Bye:         p.Bye(),
+		Status:      p.Status,
+		WinnerID:    p.WinnerID,
+		NoShow:      p.NoShow,
+		StartsAt:    p.StartsAt,
+		EndsAt:      p.EndsAt,
+		CompletedAt: p.CompletedAt,
+	}
+	if p.Side(userID) != "" {
+		resp.Seed = p.Seed
+	}
+	sides := []string{entity.PairingSideA}
+	if !p.Bye() {
+		sides = append(sides, entity.PairingSideB)
+	}
+	for _, side := range sides {
+		playerID := p.PlayerA
+		if side == entity.PairingSideB {
+			playerID = p.PlayerB
+		}
+		result := p.Result(side)
+		player := response.TournamentPairingPlayerResponse{User: tournamentUser(playerID, users), Played: result != nil}
+		if result != nil && (p.Status == entity.PairingCompleted || playerID == userID) {
+			player.Result = &response.DuelEntryResponse{
+				SessionID:   result.SessionID,
+				Score:       result.SessionScore.Score,
+				Accuracy:    result.SessionScore.Accuracy,
+				AvgTime:     result.SessionScore.AvgTime,
+				Flagged:     result.Flagged,
+				SubmittedAt: result.SubmittedAt,
+			}
+		}
+		resp.Players = append(resp.Players, player)
+	}
+	return resp
+}
+
+func tournamentUser(userID string, users map[string]*entity.User) response.CompositeUserSummary {
+	if u := users[userID]; u != nil {
+		return toUserSummary(u)
+	}
+	return response.CompositeUserSummary{ID: userID}
+}
@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"brainbash_backend/internal/model/request"
+	"brainbash_backend/internal/service"
+	"brainbash_backend/internal/utils"
+	"brainbash_backend/internal/validation"
+)
+
+// UserController serves the authenticated user's editable profile.
+type UserController struct {
+	userService *service.UserService
+	xpService   *service.XPService
+}
+
+// NewUserController creates a new UserController.
+func NewUserController(userService *service.UserService, xpService *service.XPService) *UserController {
+	return &UserController{
+		userService: userService,
+		xpService:   xpService,
+	}
+}
+
+// UpdateProfile handles PATCH /api/user/profile. Changes the fields present in the body and returns the
+// profile as /auth/me does.
+func (uc *UserController) UpdateProfile(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+	var req request.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := uc.userService.UpdateProfile(c.Request.Context(), userID, service.ProfileUpdate{
+		DisplayName: req.DisplayName,
+		AvatarURL:   req.AvatarURL,
+		BirthYear:   req.BirthYear,
+		Timezone:    req.Timezone,
+		Locale:      req.Locale,
+		Bio:         req.Bio,
+	})
+	var vErr *validation.Error
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, toUserInfo(user, uc.xpService))
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrDisplayNameTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.As(err, &vErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Printf("User UpdateProfile: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update profile"})
+	}
+}
@@ -0,0 +1,86 @@
+package event
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"brainbash_backend/internal/model/entity"
+)
+
+// Type identifies what happened.
+type Type string
+
+const (
+	// SessionStored is published for every leaderboard-eligible session stored by ScoreService.
+	SessionStored Type = "session_stored"
+	// LeaderboardEntered is published when a session enters a game type's top 10 on the dashboard.
+	LeaderboardEntered Type = "leaderboard_entered"
+	// RankLost is published for each user a new entry pushed down or out of a game type's top 10;
+	// Rank is the user's new best position, 0 when they dropped out.
+	RankLost Type = "rank_lost"
+	// AchievementUnlocked is published when a user unlocks an achievement.
+	AchievementUnlocked Type = "achievement_unlocked"
+	// GoalMet is published when a user meets one of their goals (daily goals once per day).
+	GoalMet Type = "goal_met"
+	// DigestReady is published when a user's weekly digest has been generated.
+	DigestReady Type = "digest_ready"
+	// DuelReceived is published to the opponent when a friend challenges them to a duel.
+	DuelReceived Type = "duel_received"
+	// DuelCompleted is published to each player when a duel is decided.
+	DuelCompleted Type = "duel_completed"
+)
+
+// Event is a domain event about one user. Session events carry the session fields; Rank is set for
+// LeaderboardEntered and RankLost, and RefID (the achievement, goal, digest or duel id) for AchievementUnlocked,
+// GoalMet, DigestReady and the duel events.
+type Event struct {
+	Type         Type
+	UserID       string
+	GameType     string
+	SessionID    string
+	SessionScore entity.SessionScoreDetail
+	Timestamp    time.Time
+	Rank         int
+	RefID        string
+}
+
+// Handler reacts to an event. Errors are logged; they never fail the publisher.
+type Handler func(ctx context.Context, e Event) error
+
+var (
+	mu       sync.RWMutex
+	handlers = make(map[Type][]handler)
+)
+
+type handler struct {
+	name string
+	fn   Handler
+}
+
+// Subscribe registers fn for events of type t. Handlers run synchronously in registration order.
+func Subscribe(t Type, name string, fn Handler) {
+	mu.Lock()
+	defer mu.Unlock()
+	handlers[t] = append(handlers[t], handler{name: name, fn: fn})
+}
+
+// Publish delivers e to every handler subscribed to its type.
+func Publish(ctx context.Context, e Event) {
+	mu.RLock()
+	hs := handlers[e.Type]
+	mu.RUnlock()
+	for _, h := range hs {
+		if err := h.fn(ctx, e); err != nil {
+			log.Printf("Event: handler %s failed for %s: %v", h.name, e.Type, err)
+		}
+	}
+}
+
+// ResetForTesting removes all handlers.
+func ResetForTesting() {
+	mu.Lock()
+	defer mu.Unlock()
+	handlers = make(map[Type][]handler)
+}
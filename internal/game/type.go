@@ -33,6 +33,23 @@ var validGameTypes = map[GameType]struct{}{
 	AttentionControl: {},
 }
 
+var labels = map[GameType]string{
+	ProcessingSpeed:  "Processing Speed",
+	WorkingMemory:    "Working Memory",
+	LogicalReasoning: "Logical Reasoning",
+	MathReasoning:    "Math Reasoning",
+	ReflexTime:       "Reflex Time",
+	AttentionControl: "Attention Control",
+}
+
+// Label returns the human-readable name of the game type (its cognitive domain).
+func (g GameType) Label() string {
+	if l, ok := labels[g]; ok {
+		return l
+	}
+	return string(g)
+}
+
 // IsValid returns true if g is an allowed game type.
 func (g GameType) IsValid() bool {
 	_, ok := validGameTypes[g]
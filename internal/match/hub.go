@@ -0,0 +1,228 @@
+package match
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"brainbash_backend/internal/scoring"
+)
+
+var (
+	// ErrAlreadyPlaying is returned when the user is already queued or in a match.
+	ErrAlreadyPlaying = errors.New("already queued or playing")
+	// ErrQueueTimeout is sent to players for whom no opponents were found in time.
+	ErrQueueTimeout = errors.New("no opponents found, try again later")
+)
+
+// Config controls matchmaking and match pacing.
+type Config struct {
+	Questions     int
+	QuestionTime  time.Duration
+	StartDelay    time.Duration
+	MaxQueueWait  time.Duration
+	SkillWindow   float64 // skill difference accepted straight away
+	SkillWidening float64 // added to the window per second a player has waited
+}
+
+// Recorder stores a finished match and returns the stored session id per user. It is called once per
+// match, after the last question.
+type Recorder func(ctx context.Context, r *Result) map[string]string
+
+// queueKey separates queues by tenant, game type and match size.
+type queueKey struct {
+	tenantID string
+	gameType string
+	size     int
+}
+
+// Hub is the in-memory matchmaking queue and the set of running matches. Matches live on the instance
+// their players connected to.
+type Hub struct {
+	cfg    Config
+	scorer *scoring.Scorer
+	record Recorder
+
+	mu      sync.Mutex
+	queues  map[queueKey][]*Player
+	players map[string]*Player // queued or playing, by user id
+}
+
+// NewHub creates a Hub.
+func NewHub(cfg Config, scorer *scoring.Scorer, record Recorder) *Hub {
+	return &Hub{
+		cfg:     cfg,
+		scorer:  scorer,
+		record:  record,
+		queues:  make(map[queueKey][]*Player),
+		players: make(map[string]*Player),
+	}
+}
+
+// Join queues the player and starts reading their messages and pinging them. Returns ErrAlreadyPlaying
+// when the user already has a connection in the queue or a match.
+func (h *Hub) Join(p *Player) error {
+	h.mu.Lock()
+	if _, ok := h.players[p.UserID]; ok {
+		h.mu.Unlock()
+		return ErrAlreadyPlaying
+	}
+	key := p.queue()
+	p.joinedAt = time.Now()
+	h.players[p.UserID] = p
+	h.queues[key] = append(h.queues[key], p)
+	h.mu.Unlock()
+
+	p.Send(QueuedMessage{Type: MsgQueued, GameType: p.GameType, Players: p.Size})
+	go p.readLoop(h)
+	go p.pingLoop()
+	h.matchmake(key, time.Now())
+	return nil
+}
+
+// Matchmake forms matches from every queue, widening the accepted skill gap the longer players wait,
+// and drops players who waited longer than MaxQueueWait. Run periodically.
+func (h *Hub) Matchmake(ctx context.Context) error {
+	h.mu.Lock()
+	keys := make([]queueKey, 0, len(h.queues))
+	for key := range h.queues {
+		keys = append(keys, key)
+	}
+	h.mu.Unlock()
+
+	now := time.Now()
+	for _, key := range keys {
+		h.expire(key, now)
+		h.matchmake(key, now)
+	}
+	return nil
+}
+
+// matchmake starts every match the queue allows. The longest-waiting player anchors each group and
+// is joined by the closest players within their skill window.
+func (h *Hub) matchmake(key queueKey, now time.Time) {
+	h.mu.Lock()
+	queue := h.queues[key]
+	var matches []*Match
+	used := make(map[*Player]bool)
+	for i, anchor := range queue {
+		if used[anchor] {
+			continue
+		}
+		window := h.window(anchor, now)
+		var candidates []*Player
+		for _, p := range queue[i+1:] {
+			if !used[p] && math.Abs(p.Skill-anchor.Skill) <= max(window, h.window(p, now)) {
+				candidates = append(candidates, p)
+			}
+		}
+		if len(candidates) < key.size-1 {
+			continue
+		}
+		sort.SliceStable(candidates, func(a, b int) bool {
+			return math.Abs(candidates[a].Skill-anchor.Skill) < math.Abs(candidates[b].Skill-anchor.Skill)
+		})
+		group := append([]*Player{anchor}, candidates[:key.size-1]...)
+		// Assign the match before unlocking so a player leaving now forfeits it instead of the queue
+		m := newMatch(h, key.gameType, group)
+		for _, p := range group {
+			used[p] = true
+			p.setMatch(m)
+		}
+		matches = append(matches, m)
+	}
+	if len(matches) > 0 {
+		rest := queue[:0:0]
+		for _, p := range queue {
+			if !used[p] {
+				rest = append(rest, p)
+			}
+		}
+		h.setQueue(key, rest)
+	}
+	h.mu.Unlock()
+
+	for _, m := range matches {
+		go m.run()
+	}
+}
+
+// expire removes players who waited longer than MaxQueueWait.
+func (h *Hub) expire(key queueKey, now time.Time) {
+	if h.cfg.MaxQueueWait <= 0 {
+		return
+	}
+	h.mu.Lock()
+	var expired []*Player
+	rest := h.queues[key][:0:0]
+	for _, p := range h.queues[key] {
+		if now.Sub(p.joinedAt) > h.cfg.MaxQueueWait {
+			expired = append(expired, p)
+			delete(h.players, p.UserID)
+			continue
+		}
+		rest = append(rest, p)
+	}
+	h.setQueue(key, rest)
+	h.mu.Unlock()
+
+	for _, p := range expired {
+		p.Send(ErrorMessage{Type: MsgError, Error: ErrQueueTimeout.Error()})
+		p.finish()
+	}
+}
+
+// leave removes a disconnected or leaving player from the queue, or forfeits their match.
+func (h *Hub) leave(p *Player) {
+	h.mu.Lock()
+	// Matches are assigned under h.mu, so the player is either still queued or already in a match
+	m := p.currentMatch()
+	if m == nil {
+		key := p.queue()
+		rest := h.queues[key][:0:0]
+		for _, q := range h.queues[key] {
+			if q != p {
+				rest = append(rest, q)
+			}
+		}
+		h.setQueue(key, rest)
+		if h.players[p.UserID] == p {
+			delete(h.players, p.UserID)
+		}
+	}
+	h.mu.Unlock()
+
+	if m != nil {
+		m.submit(answer{player: p, msg: ClientMessage{Type: MsgLeave}, at: time.Now()})
+		return
+	}
+	p.finish()
+}
+
+// release forgets players whose match is over so they can queue again.
+func (h *Hub) release(players []*Player) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, p := range players {
+		if h.players[p.UserID] == p {
+			delete(h.players, p.UserID)
+		}
+	}
+}
+
+// setQueue replaces a queue, dropping it when empty. Callers hold h.mu.
+func (h *Hub) setQueue(key queueKey, queue []*Player) {
+	if len(queue) == 0 {
+		delete(h.queues, key)
+		return
+	}
+	h.queues[key] = queue
+}
+
+// window is the skill difference p accepts after waiting until now.
+func (h *Hub) window(p *Player, now time.Time) float64 {
+	return h.cfg.SkillWindow + h.cfg.SkillWidening*now.Sub(p.joinedAt).Seconds()
+}
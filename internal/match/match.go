@@ -0,0 +1,267 @@
+package match
+
+import (
+	"context"
+	"math/rand/v2"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"brainbash_backend/internal/game"
+	"brainbash_backend/internal/model/request"
+	"brainbash_backend/internal/scoring"
+	"brainbash_backend/internal/tenant"
+)
+
+// recordTimeout bounds storing a finished match.
+const recordTimeout = 30 * time.Second
+
+// Result is a finished match as handed to the Recorder.
+type Result struct {
+	MatchID   string
+	GameType  string
+	Seed      int64
+	Questions int
+	StartedAt time.Time
+	EndedAt   time.Time
+	Players   []PlayerResult // by final place
+}
+
+// PlayerResult is one player's responses and score. Players who left are ranked last and not recorded.
+type PlayerResult struct {
+	UserID    string
+	Name      string
+	Responses []request.QuestionResponse
+	Score     scoring.ScoreResult
+	Place     int
+	Left      bool
+}
+
+// answer is a client message with its server-side arrival time.
+type answer struct {
+	player *Player
+	msg    ClientMessage
+	at     time.Time
+}
+
+// Match coordinates one game: every player gets the same question at the same time, answers are timed
+// by the server, and standings are broadcast after each question.
+type Match struct {
+	ID       string
+	GameType string
+	Seed     int64
+	Tenant   *tenant.Tenant // the players' tenant
+
+	hub       *Hub
+	players   []*Player
+	strategy  string
+	answers   chan answer
+	ended     chan struct{}
+	responses map[*Player][]request.QuestionResponse
+	left      map[*Player]bool
+}
+
+func newMatch(h *Hub, gameType string, players []*Player) *Match {
+	return &Match{
+		ID:        bson.NewObjectID().Hex(),
+		GameType:  gameType,
+		Seed:      rand.Int64(),
+		Tenant:    players[0].Tenant,
+		hub:       h,
+		players:   players,
+		strategy:  game.GameType(gameType).StrategyFor(),
+		answers:   make(chan answer),
+		ended:     make(chan struct{}),
+		responses: make(map[*Player][]request.QuestionResponse, len(players)),
+		left:      make(map[*Player]bool),
+	}
+}
+
+// submit hands a message to the match loop; messages arriving after the match ended are dropped.
+func (m *Match) submit(a answer) {
+	select {
+	case m.answers <- a:
+	case <-m.ended:
+	}
+}
+
+// run plays the match to the end, records it and releases the players.
+func (m *Match) run() {
+	cfg := m.hub.cfg
+	startedAt := time.Now().UTC()
+	infos := make([]PlayerInfo, 0, len(m.players))
+	for _, p := range m.players {
+		infos = append(infos, p.info())
+	}
+	m.broadcast(MatchFoundMessage{
+		Type:         MsgMatchFound,
+		MatchID:      m.ID,
+		GameType:     m.GameType,
+		Seed:         m.Seed,
+		Questions:    cfg.Questions,
+		QuestionTime: cfg.QuestionTime.Milliseconds(),
+		StartsAt:     startedAt.Add(cfg.StartDelay),
+		Players:      infos,
+	})
+	m.collect(-1, startedAt, startedAt.Add(cfg.StartDelay))
+
+	for i := 0; i < cfg.Questions && len(m.left) < len(m.players); i++ {
+		sentAt := time.Now()
+		deadline := sentAt.Add(cfg.QuestionTime)
+		m.broadcast(QuestionMessage{Type: MsgQuestion, Index: i, Deadline: deadline.UTC()})
+		answered := m.collect(i, sentAt, deadline)
+		for _, p := range m.players {
+			if !answered[p] && !m.left[p] {
+				m.addResponse(p, scoring.OutcomeUnsolved, cfg.QuestionTime)
+			}
+		}
+		m.broadcast(StandingsMessage{Type: MsgStandings, Index: i, Standings: toStandings(m.results(), nil)})
+	}
+
+	results := m.results()
+	var sessionIDs map[string]string
+	if len(m.left) < len(m.players) {
+		ctx, cancel := context.WithTimeout(context.Background(), recordTimeout)
+		if m.Tenant != nil {
+			// The players' tenant scopes the sessions, scores and ratings stored for them
+			ctx = tenant.NewContext(ctx, m.Tenant)
+		}
+		sessionIDs = m.hub.record(ctx, &Result{
+			MatchID:   m.ID,
+			GameType:  m.GameType,
+			Seed:      m.Seed,
+			Questions: cfg.Questions,
+			StartedAt: startedAt,
+			EndedAt:   time.Now().UTC(),
+			Players:   results,
+		})
+		cancel()
+	}
+	m.broadcast(MatchEndMessage{Type: MsgMatchEnd, MatchID: m.ID, Standings: toStandings(results, sessionIDs)})
+
+	close(m.ended)
+	m.hub.release(m.players)
+	for _, p := range m.players {
+		p.finish()
+	}
+}
+
+// collect takes messages until every remaining player answered question index or the deadline passes,
+// and returns who answered. index -1 only watches for players leaving until the deadline.
+func (m *Match) collect(index int, sentAt, deadline time.Time) map[*Player]bool {
+	answered := make(map[*Player]bool, len(m.players))
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+	for index < 0 || m.waitingOn(answered) {
+		select {
+		case a := <-m.answers:
+			switch {
+			case a.msg.Type == MsgLeave:
+				m.left[a.player] = true
+				a.player.finish()
+			case a.msg.Type != MsgAnswer, a.msg.Index != index, answered[a.player], m.left[a.player], a.at.After(deadline):
+				// Stale, duplicate or late
+			case !m.validOutcome(a.msg.Outcome):
+				a.player.Send(ErrorMessage{Type: MsgError, Error: "outcome must be correct, incorrect or unsolved"})
+			default:
+				answered[a.player] = true
+				m.addResponse(a.player, a.msg.Outcome, a.at.Sub(sentAt))
+			}
+			if len(m.left) == len(m.players) {
+				return answered
+			}
+		case <-timer.C:
+			return answered
+		}
+	}
+	return answered
+}
+
+// waitingOn reports whether a player who has not left still has to answer.
+func (m *Match) waitingOn(answered map[*Player]bool) bool {
+	for _, p := range m.players {
+		if !answered[p] && !m.left[p] {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Match) validOutcome(outcome string) bool {
+	switch outcome {
+	case scoring.OutcomeCorrect, scoring.OutcomeIncorrect, scoring.OutcomeUnsolved:
+		return true
+	case "":
+		// Sequential-time games only report that the question was solved
+		return m.strategy == scoring.StrategySequentialTime
+	}
+	return false
+}
+
+// addResponse records p's response to the current question. Sequential-time sessions only list solved
+// questions, so unsolved ones are skipped there.
+func (m *Match) addResponse(p *Player, outcome string, took time.Duration) {
+	if m.strategy == scoring.StrategySequentialTime {
+		if outcome == scoring.OutcomeUnsolved {
+			return
+		}
+		outcome = ""
+	}
+	m.responses[p] = append(m.responses[p], request.QuestionResponse{TimeTaken: took.Seconds(), Outcome: outcome})
+}
+
+// results scores every player and orders them: higher score, then more correct, then faster; players
+// who left come last.
+func (m *Match) results() []PlayerResult {
+	out := make([]PlayerResult, 0, len(m.players))
+	for _, p := range m.players {
+		r := PlayerResult{UserID: p.UserID, Name: p.Name, Responses: m.responses[p], Left: m.left[p]}
+		if score, err := m.hub.scorer.Calculate(m.strategy, r.Responses); err == nil {
+			r.Score = *score
+		}
+		out = append(out, r)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		switch {
+		case a.Left != b.Left:
+			return b.Left
+		case a.Score.Score != b.Score.Score:
+			return a.Score.Score > b.Score.Score
+		case a.Score.Correct != b.Score.Correct:
+			return a.Score.Correct > b.Score.Correct
+		}
+		return a.Score.AvgTime < b.Score.AvgTime
+	})
+	for i := range out {
+		out[i].Place = i + 1
+	}
+	return out
+}
+
+func (m *Match) broadcast(v any) {
+	for _, p := range m.players {
+		if !m.left[p] {
+			p.Send(v)
+		}
+	}
+}
+
+func toStandings(results []PlayerResult, sessionIDs map[string]string) []Standing {
+	out := make([]Standing, 0, len(results))
+	for _, r := range results {
+		out = append(out, Standing{
+			Place:     r.Place,
+			UserID:    r.UserID,
+			Name:      r.Name,
+			Score:     r.Score.Score,
+			Correct:   r.Score.Correct,
+			Answered:  r.Score.Questions,
+			AvgTime:   r.Score.AvgTime,
+			Left:      r.Left,
+			SessionID: sessionIDs[r.UserID],
+		})
+	}
+	return out
+}
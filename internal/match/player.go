@@ -0,0 +1,156 @@
+package match
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"brainbash_backend/internal/tenant"
+)
+
+const (
+	// writeWait is how long a write to a player may take before their connection is dropped, so one
+	// stalled peer cannot hold up a broadcast or the queue for long.
+	writeWait = 5 * time.Second
+	// pongWait is how long a player may stay silent, pongs included, before their connection is dropped.
+	pongWait = 60 * time.Second
+	// pingPeriod is how often players are pinged; it must be shorter than pongWait.
+	pingPeriod = pongWait * 9 / 10
+)
+
+// Conn is a player's connection. *websocket.Conn satisfies it; writes are serialised by Player.
+type Conn interface {
+	ReadJSON(v any) error
+	WriteJSON(v any) error
+	WriteControl(messageType int, data []byte, deadline time.Time) error
+	SetReadDeadline(t time.Time) error
+	SetWriteDeadline(t time.Time) error
+	SetPongHandler(h func(appData string) error)
+	Close() error
+}
+
+// Player is a connected user waiting for or playing a match.
+type Player struct {
+	Tenant   *tenant.Tenant // the tenant the user connected through; players only meet others of it
+	UserID   string
+	Name     string
+	GameType string
+	Size     int     // players wanted in the match, including this one
+	Skill    float64 // matchmaking skill; players are paired with others of similar skill
+
+	conn     Conn
+	writeMu  sync.Mutex
+	joinedAt time.Time
+
+	mu    sync.Mutex
+	match *Match
+
+	done     chan struct{}
+	doneOnce sync.Once
+}
+
+// NewPlayer creates a player of tenant t for a match of size players on gameType.
+func NewPlayer(t *tenant.Tenant, userID, name, gameType string, size int, skill float64, conn Conn) *Player {
+	return &Player{
+		Tenant:   t,
+		UserID:   userID,
+		Name:     name,
+		GameType: gameType,
+		Size:     size,
+		Skill:    skill,
+		conn:     conn,
+		done:     make(chan struct{}),
+	}
+}
+
+// Done is closed when the player's match is over or they left; the caller then closes the connection.
+func (p *Player) Done() <-chan struct{} {
+	return p.done
+}
+
+// Send writes a message to the player. A failed or timed out write closes the connection, which the read
+// loop then reports as the player leaving.
+func (p *Player) Send(v any) {
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+	if err := p.conn.SetWriteDeadline(time.Now().Add(writeWait)); err == nil {
+		err = p.conn.WriteJSON(v)
+		if err == nil {
+			return
+		}
+	}
+	_ = p.conn.Close()
+}
+
+// queue returns the key of the queue the player waits in.
+func (p *Player) queue() queueKey {
+	key := queueKey{gameType: p.GameType, size: p.Size}
+	if p.Tenant != nil {
+		key.tenantID = p.Tenant.ID
+	}
+	return key
+}
+
+func (p *Player) finish() {
+	p.doneOnce.Do(func() { close(p.done) })
+}
+
+func (p *Player) currentMatch() *Match {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.match
+}
+
+func (p *Player) setMatch(m *Match) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.match = m
+}
+
+func (p *Player) info() PlayerInfo {
+	return PlayerInfo{UserID: p.UserID, Name: p.Name}
+}
+
+// readLoop forwards the player's messages to their match, stamping each with its arrival time.
+// A read error, including pongWait without a message or pong, or a leave message removes the player.
+func (p *Player) readLoop(h *Hub) {
+	_ = p.conn.SetReadDeadline(time.Now().Add(pongWait))
+	p.conn.SetPongHandler(func(string) error {
+		return p.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+	for {
+		var msg ClientMessage
+		if err := p.conn.ReadJSON(&msg); err != nil {
+			h.leave(p)
+			return
+		}
+		at := time.Now()
+		_ = p.conn.SetReadDeadline(at.Add(pongWait))
+		if msg.Type == MsgLeave {
+			h.leave(p)
+			return
+		}
+		if m := p.currentMatch(); m != nil {
+			m.submit(answer{player: p, msg: msg, at: at})
+		}
+	}
+}
+
+// pingLoop pings the player every pingPeriod until they are done. A failed ping closes the connection.
+func (p *Player) pingLoop() {
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+			// WriteControl may run alongside Send, so it does not take writeMu
+			if err := p.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				_ = p.conn.Close()
+				return
+			}
+		}
+	}
+}
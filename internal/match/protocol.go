@@ -0,0 +1,94 @@
+package match
+
+import "time"
+
+// Message types sent by the server.
+const (
+	MsgQueued     = "queued"
+	MsgMatchFound = "match_found"
+	MsgQuestion   = "question"
+	MsgStandings  = "standings"
+	MsgMatchEnd   = "match_end"
+	MsgError      = "error"
+)
+
+// Message types sent by clients.
+const (
+	MsgAnswer = "answer"
+	MsgLeave  = "leave"
+)
+
+// ClientMessage is a message from a player. Answers carry the index of the current question and,
+// for timed_outcome game types, the outcome (correct, incorrect or unsolved). Time is measured by the server.
+type ClientMessage struct {
+	Type    string `json:"type"`
+	Index   int    `json:"index"`
+	Outcome string `json:"outcome,omitempty"`
+}
+
+// QueuedMessage confirms the player is waiting for opponents.
+type QueuedMessage struct {
+	Type     string `json:"type"`
+	GameType string `json:"gametype"`
+	Players  int    `json:"players"`
+}
+
+// MatchFoundMessage announces the match. Clients generate questions from the seed; the first question
+// is pushed at StartsAt.
+type MatchFoundMessage struct {
+	Type         string       `json:"type"`
+	MatchID      string       `json:"match_id"`
+	GameType     string       `json:"gametype"`
+	Seed         int64        `json:"seed"`
+	Questions    int          `json:"questions"`
+	QuestionTime int64        `json:"question_time_ms"`
+	StartsAt     time.Time    `json:"starts_at"`
+	Players      []PlayerInfo `json:"players"`
+}
+
+// PlayerInfo identifies a player in a match.
+type PlayerInfo struct {
+	UserID string `json:"user_id"`
+	Name   string `json:"name"`
+}
+
+// QuestionMessage asks every player question Index at the same time; answers after Deadline are ignored.
+type QuestionMessage struct {
+	Type     string    `json:"type"`
+	Index    int       `json:"index"`
+	Deadline time.Time `json:"deadline"`
+}
+
+// StandingsMessage is broadcast after each question.
+type StandingsMessage struct {
+	Type      string     `json:"type"`
+	Index     int        `json:"index"`
+	Standings []Standing `json:"standings"`
+}
+
+// MatchEndMessage is broadcast when the last question closes. SessionID is set on the standings of
+// players whose session was stored.
+type MatchEndMessage struct {
+	Type      string     `json:"type"`
+	MatchID   string     `json:"match_id"`
+	Standings []Standing `json:"standings"`
+}
+
+// ErrorMessage reports why the server is closing the connection or ignoring a message.
+type ErrorMessage struct {
+	Type  string `json:"type"`
+	Error string `json:"error"`
+}
+
+// Standing is one player's position in a match so far.
+type Standing struct {
+	Place     int     `json:"place"`
+	UserID    string  `json:"user_id"`
+	Name      string  `json:"name"`
+	Score     float64 `json:"score"`
+	Correct   int     `json:"correct"`
+	Answered  int     `json:"answered"`
+	AvgTime   float64 `json:"avgTime"`
+	Left      bool    `json:"left,omitempty"`
+	SessionID string  `json:"session_id,omitempty"`
+}
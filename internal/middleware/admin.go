@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"brainbash_backend/internal/utils"
+)
+
+// AdminMiddleware returns a Gin middleware that only lets the given user_ids (comma-separated) through.
+// Must run after AuthMiddleware so the JWT claims are in the context.
+func AdminMiddleware(adminUserIDs string) gin.HandlerFunc {
+	admins := make(map[string]struct{})
+	for _, id := range strings.Split(adminUserIDs, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			admins[id] = struct{}{}
+		}
+	}
+	return func(c *gin.Context) {
+		if _, ok := admins[utils.GetUserIDFromContext(c)]; !ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin access required"})
+			return
+		}
+		c.Next()
+	}
+}
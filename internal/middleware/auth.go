@@ -2,19 +2,27 @@ package middleware
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 
+	"brainbash_backend/internal/tenant"
 	"brainbash_backend/internal/utils"
 )
 
 // AuthMiddleware returns a Gin middleware that validates JWT tokens
 // from the Authorization header. On success, it stores the parsed
 // claims in the context under the key "claims".
+// Browsers cannot set headers on WebSocket handshakes, so upgrade
+// requests may pass the token as ?access_token= instead.
 func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		tokenString, err := utils.ExtractBearerToken(authHeader)
+		if err != nil && authHeader == "" && isWebSocketUpgrade(c) && c.Query("access_token") != "" {
+			tokenString, err = c.Query("access_token"), nil
+		}
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 			return
@@ -26,7 +34,26 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 			return
 		}
 
+		// Tokens only work for the tenant they were issued for
+		if t := tenant.FromContext(c.Request.Context()); t != nil && tokenTenant(claims) != t.ID {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token was issued for another tenant"})
+			return
+		}
+
 		c.Set(utils.ContextKeyClaims, claims)
 		c.Next()
 	}
 }
+
+// tokenTenant returns the tenant id in the claims; tokens without one belong to the default tenant.
+func tokenTenant(claims jwt.MapClaims) string {
+	if id := utils.GetTenantFromClaims(claims); id != "" {
+		return id
+	}
+	return tenant.DefaultID
+}
+
+// isWebSocketUpgrade reports whether the request is a WebSocket handshake.
+func isWebSocketUpgrade(c *gin.Context) bool {
+	return strings.EqualFold(c.GetHeader("Upgrade"), "websocket")
+}
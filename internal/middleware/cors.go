@@ -2,31 +2,30 @@ package middleware
 
 import (
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+
+	"brainbash_backend/internal/tenant"
 )
 
 // CORSMiddleware handles Cross-Origin Resource Sharing (CORS).
-// Allows the Flutter web frontend to make requests to this backend.
-func CORSMiddleware() gin.HandlerFunc {
+// Allows the Flutter web frontend to make requests to this backend, and partner frontends to call their tenant.
+// Must run after TenantMiddleware; tenantHeader is allowed on cross-origin requests.
+func CORSMiddleware(tenantHeader string) gin.HandlerFunc {
+	allowHeaders := "Origin, Content-Type, Accept, Authorization"
+	if tenantHeader != "" {
+		allowHeaders += ", " + tenantHeader
+	}
 	return func(c *gin.Context) {
 		origin := c.GetHeader("Origin")
-
-		// Allow requests from local dev (any port), GitHub Pages, and Railway
-		allowed := origin == "https://ag597482.github.io" ||
-			origin == "https://brainbashbackend-brainbash.up.railway.app" ||
-			strings.HasSuffix(origin, ".up.railway.app") ||
-			(len(origin) > 0 && (origin == "http://localhost" ||
-				strings.HasPrefix(origin, "http://localhost:") ||
-				strings.HasPrefix(origin, "http://127.0.0.1:")))
-
-		if allowed {
+		if TenantAllowsOrigin(tenant.FromContext(c.Request.Context()), origin) {
 			c.Header("Access-Control-Allow-Origin", origin)
 		}
 
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Headers", allowHeaders)
 		c.Header("Access-Control-Allow-Credentials", "true")
 		c.Header("Access-Control-Max-Age", "86400")
 
@@ -39,3 +38,23 @@ func CORSMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// TenantAllowsOrigin reports whether browsers on origin may call the API for t: the tenant's configured
+// origins, and for the default tenant also AllowedOrigin.
+func TenantAllowsOrigin(t *tenant.Tenant, origin string) bool {
+	if (t == nil || t.IsDefault()) && AllowedOrigin(origin) {
+		return true
+	}
+	return t != nil && origin != "" && slices.Contains(t.CORSOrigins, origin)
+}
+
+// AllowedOrigin reports whether browsers on origin may call the API: local dev (any port), GitHub Pages
+// and Railway.
+func AllowedOrigin(origin string) bool {
+	return origin == "https://ag597482.github.io" ||
+		origin == "https://brainbashbackend-brainbash.up.railway.app" ||
+		strings.HasSuffix(origin, ".up.railway.app") ||
+		(len(origin) > 0 && (origin == "http://localhost" ||
+			strings.HasPrefix(origin, "http://localhost:") ||
+			strings.HasPrefix(origin, "http://127.0.0.1:")))
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/utils"
+)
+
+// ContextKeyGroupRole is the key used to store the caller's role in the :group_id group in the Gin context.
+const ContextKeyGroupRole = "group_role"
+
+// GroupRoleLookup resolves a user's role in a group, or "" when they are not a member.
+type GroupRoleLookup interface {
+	MemberRole(ctx context.Context, groupID, userID string) (string, error)
+}
+
+// GroupMembership returns a Gin middleware that only lets members of the :group_id group holding at least
+// minRole through, and stores their role in the context. Non-members get 404 so groups cannot be probed.
+// Must run after AuthMiddleware.
+func GroupMembership(lookup GroupRoleLookup, minRole string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, err := lookup.MemberRole(c.Request.Context(), c.Param("group_id"), utils.GetUserIDFromContext(c))
+		if err != nil {
+			log.Printf("GroupMembership: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		if role == "" {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "group not found"})
+			return
+		}
+		if entity.GroupRoleRank(role) < entity.GroupRoleRank(minRole) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "group " + minRole + " role required"})
+			return
+		}
+		c.Set(ContextKeyGroupRole, role)
+		c.Next()
+	}
+}
+
+// GetGroupRole returns the caller's role stored by GroupMembership, or "" if missing.
+func GetGroupRole(c *gin.Context) string {
+	return c.GetString(ContextKeyGroupRole)
+}
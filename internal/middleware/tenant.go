@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"brainbash_backend/internal/tenant"
+	"brainbash_backend/internal/utils"
+)
+
+// TenantResolver finds the tenant of a request, with the settings its admins saved applied.
+type TenantResolver interface {
+	Get(ctx context.Context, id string) *tenant.Tenant
+	ForHost(ctx context.Context, host string) *tenant.Tenant
+}
+
+// TenantMiddleware returns a Gin middleware that resolves the request's tenant from the header (when
+// set) or the host, and stores it in the request context so repositories are scoped to it. Unknown
+// tenants named by the header are rejected; unknown hosts get the default tenant.
+func TenantMiddleware(resolver TenantResolver, header string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		var t *tenant.Tenant
+		if id := c.GetHeader(header); header != "" && id != "" {
+			if t = resolver.Get(ctx, id); t == nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "unknown tenant"})
+				return
+			}
+		} else {
+			t = resolver.ForHost(ctx, c.Request.Host)
+		}
+		c.Request = c.Request.WithContext(tenant.NewContext(ctx, t))
+		c.Next()
+	}
+}
+
+// LeaderboardVisibility returns a Gin middleware that answers 403 unless the request's tenant shows
+// leaderboards of the given visibility (tenant.LeaderboardsPublic or tenant.LeaderboardsGroup).
+func LeaderboardVisibility(visibility string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if t := tenant.FromContext(c.Request.Context()); t != nil && !t.ShowsLeaderboards(visibility) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "leaderboards are disabled for this tenant"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// TenantAdminMiddleware returns a Gin middleware that only lets the admins of the request's tenant
+// through. Must run after AuthMiddleware so the JWT claims are in the context.
+func TenantAdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		t := tenant.FromContext(c.Request.Context())
+		if t == nil || !t.IsAdmin(utils.GetUserIDFromContext(c)) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "tenant admin access required"})
+			return
+		}
+		c.Next()
+	}
+}
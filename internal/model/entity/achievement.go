@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// Achievement is the document stored in the "achievements" collection: one unlocked achievement of a user.
+// _id is "<user_id>:<achievement_id>", so an achievement unlocks once.
+type Achievement struct {
+	ID            string    `bson:"_id"`
+	UserID        string    `bson:"user_id"`
+	AchievementID string    `bson:"achievement_id"`
+	SessionID     string    `bson:"session_id,omitempty"` // session that triggered the unlock
+	UnlockedAt    time.Time `bson:"unlocked_at"`
+}
+
+// AchievementDocID returns the _id of the user's unlock of achievementID.
+func AchievementDocID(userID, achievementID string) string {
+	return userID + ":" + achievementID
+}
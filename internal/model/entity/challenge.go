@@ -0,0 +1,51 @@
+package entity
+
+import "time"
+
+// Challenge is the document stored in the "challenges" collection: the daily challenge of one UTC day.
+// _id is the date (YYYY-MM-DD). Every player of a tenant gets the same game type and question seed.
+type Challenge struct {
+	ID              string            `bson:"_id"`
+	GameType        string            `bson:"game_type"`                   // the default tenant's
+	TenantGameTypes map[string]string `bson:"tenant_game_types,omitempty"` // picked from each other tenant's catalog, by tenant id
+	Seed            int64             `bson:"seed"`
+	StartsAt        time.Time         `bson:"starts_at"`
+	EndsAt          time.Time         `bson:"ends_at"`
+	CreatedAt       time.Time         `bson:"created_at"`
+	RatedAt         *time.Time        `bson:"rated_at,omitempty"` // set once placements were rated
+}
+
+// GameTypeFor returns the game type the tenant plays in the challenge; tenants without a pick of their
+// own play the default tenant's.
+func (c *Challenge) GameTypeFor(tenantID string) string {
+	if gt, ok := c.TenantGameTypes[tenantID]; ok {
+		return gt
+	}
+	return c.GameType
+}
+
+// ChallengeAttempt is the document stored in the "challenge_attempts" collection: a user's ranked attempt
+// at a daily challenge. _id is "<challenge_id>:<user_id>", which allows one attempt per user and day.
+type ChallengeAttempt struct {
+	ID           string             `bson:"_id"`
+	ChallengeID  string             `bson:"challenge_id"`
+	UserID       string             `bson:"user_id"`
+	TenantID     string             `bson:"tenant_id,omitempty"` // empty for the default tenant
+	SessionID    string             `bson:"session_id"`
+	SessionScore SessionScoreDetail `bson:"session_score"`
+	Flagged      bool               `bson:"flagged"` // pending review; kept off the challenge leaderboard
+	SubmittedAt  time.Time          `bson:"submitted_at"`
+}
+
+// ChallengeDateLayout is the format of challenge IDs.
+const ChallengeDateLayout = "2006-01-02"
+
+// ChallengeID returns the ID of the daily challenge running at t.
+func ChallengeID(t time.Time) string {
+	return t.UTC().Format(ChallengeDateLayout)
+}
+
+// ChallengeAttemptID returns the _id of the user's attempt at the challenge.
+func ChallengeAttemptID(challengeID, userID string) string {
+	return challengeID + ":" + userID
+}
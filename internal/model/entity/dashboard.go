@@ -12,6 +12,7 @@ type Dashboard struct {
 	MathReasoning     []DashboardEntry           `bson:"math_reasoning,omitempty"`
 	ReflexTime        []DashboardEntry           `bson:"reflex_time,omitempty"`
 	AttentionControl  []DashboardEntry           `bson:"attention_control,omitempty"`
+	TenantID          string                     `bson:"tenant_id,omitempty"` // empty for the default tenant
 }
 
 // DashboardEntry is one top-score entry for a game type (session + user summary + score).
@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+// Digest is the document stored in the "digests" collection: a user's summary of one week (Monday to
+// Monday, UTC). _id is "<user_id>:<week_start>".
+type Digest struct {
+	ID               string              `bson:"_id"`
+	UserID           string              `bson:"user_id"`
+	WeekStart        time.Time           `bson:"week_start"`
+	WeekEnd          time.Time           `bson:"week_end"`
+	SessionsPlayed   int                 `bson:"sessions_played"`
+	PreviousSessions int                 `bson:"previous_sessions"` // sessions in the week before
+	Improvements     []DigestImprovement `bson:"improvements"`
+	OverallRank      int64               `bson:"overall_rank,omitempty"` // composite leaderboard position at generation
+	RankChange       int64               `bson:"rank_change"`            // positive when the user climbed since last digest
+	GoalsHit         []DigestGoal        `bson:"goals_hit"`
+	GeneratedAt      time.Time           `bson:"generated_at"`
+}
+
+// DigestImprovement compares a game type's mean session score with the week before.
+type DigestImprovement struct {
+	GameType    string  `bson:"game_type"`
+	Sessions    int     `bson:"sessions"`
+	AvgScore    float64 `bson:"avg_score"`
+	PreviousAvg float64 `bson:"previous_avg"` // 0 when not played the week before
+	Change      float64 `bson:"change"`
+	WeekBest    float64 `bson:"week_best"` // best session score of the week
+}
+
+// DigestGoal is a goal met during the week.
+type DigestGoal struct {
+	GoalID string  `bson:"goal_id"`
+	Kind   string  `bson:"kind"`
+	Target float64 `bson:"target"`
+	Times  int     `bson:"times"` // days met for daily goals, 1 for one-off goals
+}
+
+// DigestID returns the _id of the user's digest for the week starting at weekStart.
+func DigestID(userID string, weekStart time.Time) string {
+	return userID + ":" + weekStart.UTC().Format("2006-01-02")
+}
@@ -0,0 +1,82 @@
+package entity
+
+import "time"
+
+// Duel is the document stored in the "duels" collection: an asynchronous head-to-head between two friends.
+// Both players get the same game type and question seed and play whenever they like before ExpiresAt.
+type Duel struct {
+	ID           string     `bson:"_id"`
+	ChallengerID string     `bson:"challenger_id"`
+	OpponentID   string     `bson:"opponent_id"`
+	GameType     string     `bson:"game_type"`
+	Seed         int64      `bson:"seed"`
+	Status       string     `bson:"status"`
+	Challenger   *DuelEntry `bson:"challenger,omitempty"`
+	Opponent     *DuelEntry `bson:"opponent,omitempty"`
+	WinnerID     string     `bson:"winner_id,omitempty"` // empty for a draw or an unfinished duel
+	Forfeit      bool       `bson:"forfeit,omitempty"`   // the loser did not play before the duel expired
+	CreatedAt    time.Time  `bson:"created_at"`
+	AcceptedAt   *time.Time `bson:"accepted_at,omitempty"`
+	ExpiresAt    time.Time  `bson:"expires_at"`
+	CompletedAt  *time.Time `bson:"completed_at,omitempty"`
+}
+
+// DuelEntry is one player's session in a duel.
+type DuelEntry struct {
+	SessionID    string             `bson:"session_id"`
+	SessionScore SessionScoreDetail `bson:"session_score"`
+	Flagged      bool               `bson:"flagged"` // held for review when submitted; loses to an unflagged entry
+	SubmittedAt  time.Time          `bson:"submitted_at"`
+}
+
+// Duel statuses. A duel is pending until the opponent accepts it, then active until both entries are in
+// (completed) or it runs out of time (completed by forfeit, or expired when nobody finished).
+const (
+	DuelPending   = "pending"
+	DuelActive    = "active"
+	DuelDeclined  = "declined"
+	DuelCompleted = "completed"
+	DuelExpired   = "expired"
+)
+
+// Duel sides, which are also the field names of the entries.
+const (
+	DuelSideChallenger = "challenger"
+	DuelSideOpponent   = "opponent"
+)
+
+// Side returns the side userID plays on, or "" when they are not in the duel.
+func (d *Duel) Side(userID string) string {
+	switch userID {
+	case d.ChallengerID:
+		return DuelSideChallenger
+	case d.OpponentID:
+		return DuelSideOpponent
+	}
+	return ""
+}
+
+// Entry returns the entry of side, or nil while that player has not submitted.
+func (d *Duel) Entry(side string) *DuelEntry {
+	if side == DuelSideChallenger {
+		return d.Challenger
+	}
+	return d.Opponent
+}
+
+// OtherID returns the id of userID's counterpart.
+func (d *Duel) OtherID(userID string) string {
+	if userID == d.ChallengerID {
+		return d.OpponentID
+	}
+	return d.ChallengerID
+}
+
+// DuelRecord is the document stored in the "duel_records" collection: a user's duel results. _id is the user_id.
+type DuelRecord struct {
+	UserID    string    `bson:"_id"`
+	Wins      int       `bson:"wins"`
+	Losses    int       `bson:"losses"`
+	Draws     int       `bson:"draws"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
@@ -0,0 +1,14 @@
+package entity
+
+// GameResult is the outcome of one game result submission. It is stored with the submission's
+// idempotency key so that retries get the original response.
+type GameResult struct {
+	SessionID    string             `bson:"session_id"`
+	GameType     string             `bson:"game_type"`
+	SessionScore SessionScoreDetail `bson:"session_score"`
+	Percentile   *float64           `bson:"percentile,omitempty"` // nil until norms have been built
+	Flagged      bool               `bson:"flagged"`
+	XPGained     int64              `bson:"xp_gained"`
+	Level        int                `bson:"level,omitempty"` // level after the XP was added
+	LeveledUp    bool               `bson:"leveled_up"`
+}
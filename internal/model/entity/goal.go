@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// Goal is the document stored in the "goals" collection: a goal a user set for themselves.
+type Goal struct {
+	ID         string     `bson:"_id"`
+	UserID     string     `bson:"user_id"`
+	Kind       string     `bson:"kind"`
+	GameType   string     `bson:"game_type,omitempty"` // empty counts every game type (daily_sessions only)
+	Target     float64    `bson:"target"`
+	Active     bool       `bson:"active"`
+	Slot       *int       `bson:"slot,omitempty"`        // 0 to goals.max_per_user-1, unique per user; unset on older goals
+	HitDays    []string   `bson:"hit_days,omitempty"`    // daily goals: days (YYYY-MM-DD, user timezone) the goal was met
+	AchievedAt *time.Time `bson:"achieved_at,omitempty"` // one-off goals: when the goal was met
+	CreatedAt  time.Time  `bson:"created_at"`
+	UpdatedAt  time.Time  `bson:"updated_at"`
+}
+
+// Goal kinds.
+const (
+	GoalDailySessions = "daily_sessions" // play Target sessions in a day; recurs daily
+	GoalTargetScore   = "target_score"   // reach a session score of Target in GameType; met once
+)
@@ -0,0 +1,52 @@
+package entity
+
+import "time"
+
+// Group is the document stored in the "groups" collection: a cohort such as a classroom or team.
+// Users join with JoinCode; scores are only shared between members of the same group.
+type Group struct {
+	ID        string    `bson:"_id"`
+	TenantID  string    `bson:"tenant_id,omitempty"` // empty for the default tenant
+	Name      string    `bson:"name"`
+	OwnerID   string    `bson:"owner_id"`
+	JoinCode  string    `bson:"join_code"`
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+// GroupMember is the document stored in the "group_members" collection: a user's membership and role
+// in a group. _id is "<group_id>:<user_id>".
+type GroupMember struct {
+	ID       string    `bson:"_id"`
+	TenantID string    `bson:"tenant_id,omitempty"` // empty for the default tenant
+	GroupID  string    `bson:"group_id"`
+	UserID   string    `bson:"user_id"`
+	Role     string    `bson:"role"`
+	JoinedAt time.Time `bson:"joined_at"`
+}
+
+// Group roles, from most to least privileged. The owner manages roles and sees members' progress; admins
+// manage membership and the join code; members see the group and its leaderboard.
+const (
+	GroupRoleOwner  = "owner"
+	GroupRoleAdmin  = "admin"
+	GroupRoleMember = "member"
+)
+
+// GroupRoleRank orders roles: a higher rank includes the permissions of lower ones. Unknown roles rank 0.
+func GroupRoleRank(role string) int {
+	switch role {
+	case GroupRoleOwner:
+		return 3
+	case GroupRoleAdmin:
+		return 2
+	case GroupRoleMember:
+		return 1
+	}
+	return 0
+}
+
+// GroupMemberID returns the _id of the user's membership in the group.
+func GroupMemberID(groupID, userID string) string {
+	return groupID + ":" + userID
+}
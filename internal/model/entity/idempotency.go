@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// IdempotencyRecord is the document stored in the "idempotency_keys" collection. _id is
+// "<user_id>:<key>"; documents expire via a TTL index on created_at.
+type IdempotencyRecord struct {
+	ID          string      `bson:"_id"`
+	UserID      string      `bson:"user_id"`
+	Key         string      `bson:"key"`
+	RequestHash string      `bson:"request_hash"`
+	Status      string      `bson:"status"`
+	Result      *GameResult `bson:"result,omitempty"`
+	CreatedAt   time.Time   `bson:"created_at"`
+}
+
+// Idempotency record statuses.
+const (
+	IdempotencyPending   = "pending"
+	IdempotencyCompleted = "completed"
+)
+
+// IdempotencyID returns the _id of the record for userID and key.
+func IdempotencyID(userID, key string) string {
+	return userID + ":" + key
+}
@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// Match is the document stored in the "matches" collection: a finished real-time match. _id is the
+// match_id sessions played in it are tagged with.
+type Match struct {
+	ID        string        `bson:"_id"`
+	GameType  string        `bson:"game_type"`
+	Seed      int64         `bson:"seed"`
+	Questions int           `bson:"questions"`
+	Players   []MatchPlayer `bson:"players"` // by final place
+	StartedAt time.Time     `bson:"started_at"`
+	EndedAt   time.Time     `bson:"ended_at"`
+}
+
+// MatchPlayer is one player's final standing in a match. SessionID is empty for players who left
+// and for sessions that could not be stored.
+type MatchPlayer struct {
+	UserID       string             `bson:"user_id"`
+	Place        int                `bson:"place"`
+	SessionID    string             `bson:"session_id,omitempty"`
+	SessionScore SessionScoreDetail `bson:"session_score"`
+	Flagged      bool               `bson:"flagged,omitempty"`
+	Left         bool               `bson:"left,omitempty"`
+}
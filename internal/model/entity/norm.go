@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// Norm is the document stored in the "norms" collection: the score distribution of one game type,
+// optionally restricted to one age band. _id is "<game_type>:<age_band>".
+type Norm struct {
+	ID          string    `bson:"_id"`
+	GameType    string    `bson:"game_type"`
+	AgeBand     string    `bson:"age_band"`
+	SampleSize  int       `bson:"sample_size"`
+	Percentiles []float64 `bson:"percentiles"` // Percentiles[p] is the score at percentile p (0..100)
+	BuiltAt     time.Time `bson:"built_at"`
+}
+
+// AgeBandAll is the age band of the norm covering the whole population.
+const AgeBandAll = "all"
+
+// NormID returns the _id of the norm for gameType and ageBand.
+func NormID(gameType, ageBand string) string {
+	return gameType + ":" + ageBand
+}
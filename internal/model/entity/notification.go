@@ -0,0 +1,44 @@
+package entity
+
+import "time"
+
+// Notification is the document stored in the "notifications" collection: one message to deliver over
+// one channel. The dispatcher picks up pending and stalled sending notifications once NextAttemptAt passes.
+type Notification struct {
+	ID            string            `bson:"_id"`
+	UserID        string            `bson:"user_id"`
+	Kind          string            `bson:"kind"`
+	Channel       string            `bson:"channel"`
+	DedupKey      string            `bson:"dedup_key"` // unique per channel; repeats of the same notice are dropped
+	Subject       string            `bson:"subject"`
+	Body          string            `bson:"body"`
+	Data          map[string]string `bson:"data,omitempty"`
+	Status        string            `bson:"status"`
+	Attempts      int               `bson:"attempts"`
+	NextAttemptAt time.Time         `bson:"next_attempt_at"`
+	LastError     string            `bson:"last_error,omitempty"`
+	CreatedAt     time.Time         `bson:"created_at"`
+	SentAt        *time.Time        `bson:"sent_at,omitempty"`
+}
+
+// Notification statuses.
+const (
+	NotificationPending = "pending"
+	NotificationSending = "sending" // claimed by the dispatcher; retried if it stalls
+	NotificationSent    = "sent"
+	NotificationFailed  = "failed" // gave up after max_attempts or a permanent error
+)
+
+// NotificationPreferences is the document stored in the "notification_preferences" collection
+// (one per user, _id is the user_id). A user without a document gets the configured defaults.
+type NotificationPreferences struct {
+	UserID        string    `bson:"_id"`
+	Channels      []string  `bson:"channels"`                 // channels to deliver on
+	DisabledKinds []string  `bson:"disabled_kinds,omitempty"` // kinds the user opted out of
+	QuietStart    string    `bson:"quiet_start,omitempty"`    // HH:MM in the user's timezone; empty disables quiet hours
+	QuietEnd      string    `bson:"quiet_end,omitempty"`      // HH:MM; may be earlier than QuietStart (overnight)
+	UpdatedAt     time.Time `bson:"updated_at"`
+}
+
+// QuietHoursLayout is the layout of QuietStart and QuietEnd.
+const QuietHoursLayout = "15:04"
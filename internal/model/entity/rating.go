@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+// Rating is the document stored in the "ratings" collection: a user's Glicko-2 rating on one game type.
+// _id is "<user_id>:<game_type>".
+type Rating struct {
+	ID         string    `bson:"_id"`
+	UserID     string    `bson:"user_id"`
+	TenantID   string    `bson:"tenant_id,omitempty"` // empty for the default tenant
+	GameType   string    `bson:"game_type"`
+	Rating     float64   `bson:"rating"`
+	Deviation  float64   `bson:"deviation"`
+	Volatility float64   `bson:"volatility"`
+	Games      int       `bson:"games"` // rated games played
+	UpdatedAt  time.Time `bson:"updated_at"`
+}
+
+// RatingChange is the document stored in the "rating_history" collection: one rating update and what caused it.
+type RatingChange struct {
+	ID        string    `bson:"_id"`
+	UserID    string    `bson:"user_id"`
+	GameType  string    `bson:"game_type"`
+	Source    string    `bson:"source"` // RatingSource*
+	RefID     string    `bson:"ref_id"` // duel, match or challenge id
+	Games     int       `bson:"games"`  // games rated in this update
+	Rating    float64   `bson:"rating"`
+	Deviation float64   `bson:"deviation"`
+	Change    float64   `bson:"change"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+// Rating sources.
+const (
+	RatingSourceDuel      = "duel"
+	RatingSourceMatch     = "match"
+	RatingSourceChallenge = "challenge"
+)
+
+// RatingID returns the _id of the user's rating on gameType.
+func RatingID(userID, gameType string) string {
+	return userID + ":" + gameType
+}
@@ -14,6 +14,7 @@ type Score struct {
 	MathReasoning    *GameTypeScore  `bson:"math_reasoning,omitempty"`
 	ReflexTime       *GameTypeScore  `bson:"reflex_time,omitempty"`
 	AttentionControl *GameTypeScore  `bson:"attention_control,omitempty"`
+	TenantID         string          `bson:"tenant_id,omitempty"` // empty for the default tenant
 }
 
 // GameTypeScore holds per-game-type aggregates and sessions.
@@ -24,11 +25,27 @@ type GameTypeScore struct {
 }
 
 // Session is one game session for a game type.
+// Flags and ReviewStatus are set when the session passed validation but looked implausible.
 type Session struct {
 	SessionID        string            `bson:"session_id"`
 	QuestionResponses interface{}      `bson:"question_responses"`
 	SessionScore     SessionScoreDetail `bson:"session_score"`
 	Timestamp        time.Time         `bson:"timestamp"`
+	Flags            []string          `bson:"flags,omitempty"`
+	ReviewStatus     string            `bson:"review_status,omitempty"`
+	SessionTags      `bson:",inline"`
+}
+
+// Review statuses for flagged sessions.
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+// LeaderboardEligible returns true unless the session is flagged and not yet approved.
+func (s *Session) LeaderboardEligible() bool {
+	return len(s.Flags) == 0 || s.ReviewStatus == ReviewApproved
 }
 
 // SessionScoreDetail is the score breakdown stored per session.
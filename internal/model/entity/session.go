@@ -0,0 +1,56 @@
+package entity
+
+import "time"
+
+// SessionRecord is the document stored in the "sessions" collection: one per session, mirroring the
+// session embedded in the user's score document so history can be queried without loading it.
+// _id is generated by the server; session_id may come from the client and is only unique per user.
+type SessionRecord struct {
+	ID                string             `bson:"_id"`
+	SessionID         string             `bson:"session_id"`
+	UserID            string             `bson:"user_id"`
+	TenantID          string             `bson:"tenant_id,omitempty"` // empty for the default tenant
+	GameType          string             `bson:"game_type"`
+	QuestionResponses []ResponseRecord   `bson:"question_responses"`
+	SessionScore      SessionScoreDetail `bson:"session_score"`
+	Timestamp         time.Time          `bson:"timestamp"`
+	Flags             []string           `bson:"flags,omitempty"`
+	ReviewStatus      string             `bson:"review_status,omitempty"`
+	SessionTags       `bson:",inline"`
+}
+
+// SessionTags link a session to the game mode it was played in. All fields are empty for regular play.
+type SessionTags struct {
+	ChallengeID  string `bson:"challenge_id,omitempty"` // daily challenge date (YYYY-MM-DD)
+	DuelID       string `bson:"duel_id,omitempty"`
+	MatchID      string `bson:"match_id,omitempty"` // real-time match
+	TournamentID string `bson:"tournament_id,omitempty"`
+	Round        int    `bson:"tournament_round,omitempty"`
+}
+
+// ResponseRecord is one stored question response. Field names match how request.QuestionResponse
+// is encoded inside score documents.
+type ResponseRecord struct {
+	TimeTaken float64 `bson:"timetaken"`
+	Outcome   string  `bson:"outcome"`
+}
+
+// MemberActivity is a user's sessions over a report period, aggregated from the "sessions" collection.
+// LastActiveAt is their latest session up to the end of the period, even if it is before the start.
+type MemberActivity struct {
+	UserID       string           `bson:"_id"`
+	Sessions     int              `bson:"sessions"`
+	LastActiveAt *time.Time       `bson:"last_active_at"`
+	Domains      []DomainActivity `bson:"domains"`
+}
+
+// DomainActivity is a user's sessions of one game type over a report period. AvgScore and Improvement
+// only count leaderboard-eligible sessions; Improvement is the mean of the last few scored sessions minus
+// the mean of the first few, and is nil with fewer than two.
+type DomainActivity struct {
+	GameType    string   `bson:"game_type"`
+	Sessions    int      `bson:"sessions"`
+	Scored      int      `bson:"scored"`
+	AvgScore    *float64 `bson:"avg_score"`
+	Improvement *float64 `bson:"improvement"`
+}
@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// SessionFlag is the document stored in the "session_flags" collection: one per flagged session,
+// forming the admin review queue. _id is generated by the server; (user_id, session_id) is unique.
+type SessionFlag struct {
+	ID           string             `bson:"_id"                   json:"flag_id"`
+	SessionID    string             `bson:"session_id"            json:"session_id"`
+	UserID       string             `bson:"user_id"               json:"user_id"`
+	TenantID     string             `bson:"tenant_id,omitempty"   json:"tenant_id,omitempty"` // empty for the default tenant
+	GameType     string             `bson:"game_type"             json:"game_type"`
+	Flags        []string           `bson:"flags"                 json:"flags"`
+	Status       string             `bson:"status"                json:"status"`
+	SessionScore SessionScoreDetail `bson:"session_score"         json:"session_score"`
+	Timestamp    time.Time          `bson:"timestamp"             json:"timestamp"`
+	ReviewedBy   string             `bson:"reviewed_by,omitempty" json:"reviewed_by,omitempty"`
+	ReviewedAt   *time.Time         `bson:"reviewed_at,omitempty" json:"reviewed_at,omitempty"`
+}
@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+// Follow is the document stored in the "follows" collection: FollowerID follows FolloweeID.
+// _id is "<follower_id>:<followee_id>".
+type Follow struct {
+	ID         string    `bson:"_id"`
+	FollowerID string    `bson:"follower_id"`
+	FolloweeID string    `bson:"followee_id"`
+	CreatedAt  time.Time `bson:"created_at"`
+}
+
+// FriendRequest is the document stored in the "friend_requests" collection. An accepted request is the
+// friendship itself; declined and cancelled requests are deleted. _id is "<from_user_id>:<to_user_id>".
+type FriendRequest struct {
+	ID         string     `bson:"_id"`
+	FromUserID string     `bson:"from_user_id"`
+	ToUserID   string     `bson:"to_user_id"`
+	Status     string     `bson:"status"`
+	CreatedAt  time.Time  `bson:"created_at"`
+	AcceptedAt *time.Time `bson:"accepted_at,omitempty"`
+}
+
+// Friend request statuses.
+const (
+	FriendRequestPending  = "pending"
+	FriendRequestAccepted = "accepted"
+)
+
+// Block is the document stored in the "blocks" collection: BlockerID blocked BlockedID.
+// _id is "<blocker_id>:<blocked_id>".
+type Block struct {
+	ID        string    `bson:"_id"`
+	BlockerID string    `bson:"blocker_id"`
+	BlockedID string    `bson:"blocked_id"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+// PairID returns the _id of a directed relation (follow, friend request or block) from one user to another.
+func PairID(fromUserID, toUserID string) string {
+	return fromUserID + ":" + toUserID
+}
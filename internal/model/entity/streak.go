@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// Streak is the document stored in the "streaks" collection: a user's consecutive-day play record.
+// _id is the user_id. Days are calendar dates (YYYY-MM-DD) in the user's timezone.
+type Streak struct {
+	UserID        string    `bson:"_id"`
+	Current       int       `bson:"current"`
+	Longest       int       `bson:"longest"`
+	LastPlayedDay string    `bson:"last_played_day"`
+	FreezeTokens  int       `bson:"freeze_tokens"` // each token covers one missed day
+	FreezesUsed   int       `bson:"freezes_used"`  // lifetime
+	FrozenDays    []string  `bson:"frozen_days"`   // days covered by tokens during the current streak
+	UpdatedAt     time.Time `bson:"updated_at"`
+}
+
+// StreakDayLayout is the format of streak days.
+const StreakDayLayout = "2006-01-02"
@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// TenantSettings is the document stored in the "tenant_settings" collection (one per tenant, _id is the
+// tenant id). Saved by the tenant's admins, it replaces the tenant's configured settings as a whole.
+type TenantSettings struct {
+	TenantID        string             `bson:"_id"`
+	Name            string             `bson:"name"`
+	GameTypes       []string           `bson:"game_types"`                  // enabled game types; empty enables all
+	GameLabels      map[string]string  `bson:"game_labels,omitempty"`       // display names overriding the default labels
+	ScoringWeights  map[string]float64 `bson:"scoring_weights,omitempty"`   // composite weights; missing game types use the profile config
+	ScoringHalfLife time.Duration      `bson:"scoring_half_life,omitempty"` // 0 uses the profile config
+	Leaderboards    string             `bson:"leaderboards"`                // public, group or off
+	Retention       time.Duration      `bson:"retention,omitempty"`         // sessions older than this are deleted; 0 keeps them
+	CORSOrigins     []string           `bson:"cors_origins"`
+	UpdatedAt       time.Time          `bson:"updated_at"`
+	UpdatedBy       string             `bson:"updated_by,omitempty"`
+}
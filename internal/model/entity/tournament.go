@@ -0,0 +1,157 @@
+package entity
+
+import (
+	"strconv"
+	"time"
+)
+
+// Tournament is the document stored in the "tournaments" collection: a time-boxed competition on one game
+// type. Players join during the entry window; rounds of RoundDuration follow back to back from EntryClosesAt.
+type Tournament struct {
+	ID            string        `bson:"_id"`
+	TenantID      string        `bson:"tenant_id,omitempty"` // empty for the default tenant
+	Name          string        `bson:"name"`
+	GameType      string        `bson:"game_type"`
+	Format        string        `bson:"format"` // TournamentRoundRobin or TournamentSingleElimination
+	Status        string        `bson:"status"`
+	EntryOpensAt  time.Time     `bson:"entry_opens_at"`
+	EntryClosesAt time.Time     `bson:"entry_closes_at"`
+	Rounds        int           `bson:"rounds"`       // most rounds to play
+	TotalRounds   int           `bson:"total_rounds"` // rounds actually played, set when the tournament starts
+	RoundDuration time.Duration `bson:"round_duration"`
+	MaxPlayers    int           `bson:"max_players,omitempty"` // single elimination: 2^Rounds
+	CurrentRound  int           `bson:"current_round"`         // 0 before the first round
+	RoundEndsAt   *time.Time    `bson:"round_ends_at,omitempty"`
+	CreatedBy     string        `bson:"created_by"`
+	CreatedAt     time.Time     `bson:"created_at"`
+	StartedAt     *time.Time    `bson:"started_at,omitempty"`
+	CompletedAt   *time.Time    `bson:"completed_at,omitempty"`
+}
+
+// Tournament formats.
+const (
+	TournamentRoundRobin        = "round_robin"
+	TournamentSingleElimination = "single_elimination"
+)
+
+// Tournament statuses. A tournament takes entries until EntryClosesAt, then runs its rounds and completes;
+// it is cancelled when an admin stops it or too few players joined.
+const (
+	TournamentRegistration = "registration"
+	TournamentRunning      = "running"
+	TournamentCompleted    = "completed"
+	TournamentCancelled    = "cancelled"
+)
+
+// RoundStartsAt returns when round (1-based) opens.
+func (t *Tournament) RoundStartsAt(round int) time.Time {
+	return t.EntryClosesAt.Add(time.Duration(round-1) * t.RoundDuration)
+}
+
+// RoundEndsAtFor returns when round (1-based) closes.
+func (t *Tournament) RoundEndsAtFor(round int) time.Time {
+	return t.RoundStartsAt(round + 1)
+}
+
+// TournamentPlayer is the document stored in the "tournament_players" collection: a user's entry in a
+// tournament and their standing. _id is "<tournament_id>:<user_id>".
+type TournamentPlayer struct {
+	ID              string    `bson:"_id"`
+	TournamentID    string    `bson:"tournament_id"`
+	UserID          string    `bson:"user_id"`
+	Seed            int       `bson:"seed,omitempty"` // 1 is the top seed; set when the tournament starts
+	Rating          float64   `bson:"rating,omitempty"`
+	Points          float64   `bson:"points"`
+	Wins            int       `bson:"wins"`
+	Draws           int       `bson:"draws"`
+	Losses          int       `bson:"losses"`
+	NoShows         int       `bson:"no_shows"`
+	ScoreTotal      float64   `bson:"score_total"`                // sum of session scores, breaks ties on points
+	EliminatedRound int       `bson:"eliminated_round,omitempty"` // single elimination: round lost
+	Place           int       `bson:"place,omitempty"`            // final place, set on completion
+	JoinedAt        time.Time `bson:"joined_at"`
+}
+
+// TournamentPlayerID returns the _id of the user's entry in the tournament.
+func TournamentPlayerID(tournamentID, userID string) string {
+	return tournamentID + ":" + userID
+}
+
+// TournamentPairing is the document stored in the "tournament_pairings" collection: one game of a round.
+// _id is "<tournament_id>:<round>:<table>". PlayerB is empty for a bye, which PlayerA wins.
+type TournamentPairing struct {
+	ID           string            `bson:"_id"`
+	TournamentID string            `bson:"tournament_id"`
+	Round        int               `bson:"round"`
+	Table        int               `bson:"table"`
+	PlayerA      string            `bson:"player_a"`
+	PlayerB      string            `bson:"player_b,omitempty"`
+	Seed         int64             `bson:"seed"`
+	A            *TournamentResult `bson:"a,omitempty"`
+	B            *TournamentResult `bson:"b,omitempty"`
+	Status       string            `bson:"status"`
+	WinnerID     string            `bson:"winner_id,omitempty"` // empty for a draw or a double no-show
+	NoShow       bool              `bson:"no_show,omitempty"`   // a player did not play before the round closed
+	StartsAt     time.Time         `bson:"starts_at"`
+	EndsAt       time.Time         `bson:"ends_at"`
+	CompletedAt  *time.Time        `bson:"completed_at,omitempty"`
+}
+
+// TournamentResult is one player's session in a pairing.
+type TournamentResult struct {
+	SessionID    string             `bson:"session_id"`
+	SessionScore SessionScoreDetail `bson:"session_score"`
+	Flagged      bool               `bson:"flagged"`
+	SubmittedAt  time.Time          `bson:"submitted_at"`
+}
+
+// Pairing statuses.
+const (
+	PairingPending   = "pending"
+	PairingCompleted = "completed"
+)
+
+// Pairing sides, which are also the field names of the results.
+const (
+	PairingSideA = "a"
+	PairingSideB = "b"
+)
+
+// TournamentPairingID returns the _id of a pairing.
+func TournamentPairingID(tournamentID string, round, table int) string {
+	return tournamentID + ":" + strconv.Itoa(round) + ":" + strconv.Itoa(table)
+}
+
+// Bye reports whether the pairing has a single player.
+func (p *TournamentPairing) Bye() bool {
+	return p.PlayerB == ""
+}
+
+// Side returns the side userID plays on, or "" when they are not in the pairing.
+func (p *TournamentPairing) Side(userID string) string {
+	switch {
+	case userID == "":
+		return ""
+	case userID == p.PlayerA:
+		return PairingSideA
+	case userID == p.PlayerB:
+		return PairingSideB
+	}
+	return ""
+}
+
+// Result returns the result of side, or nil while that player has not submitted.
+func (p *TournamentPairing) Result(side string) *TournamentResult {
+	if side == PairingSideA {
+		return p.A
+	}
+	return p.B
+}
+
+// OtherID returns the id of userID's opponent; empty for a bye.
+func (p *TournamentPairing) OtherID(userID string) string {
+	if userID == p.PlayerA {
+		return p.PlayerB
+	}
+	return p.PlayerA
+}
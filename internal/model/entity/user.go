@@ -2,11 +2,39 @@ package entity
 
 import "go.mongodb.org/mongo-driver/v2/bson"
 
-// User represents a user document in the "users" MongoDB collection.
+// User represents a user document in the "users" MongoDB collection. Email, Name and Picture mirror the
+// Google account; the profile fields are set by the user and never overwritten by a login.
 type User struct {
-	UserID  bson.ObjectID `bson:"_id,omitempty" json:"user_id"`
-	GaID    string        `bson:"ga_id"         json:"ga_id"`
-	Email   string        `bson:"email"         json:"email"`
-	Name    string        `bson:"name"          json:"name"`
-	Picture string        `bson:"picture"       json:"picture"`
+	UserID         bson.ObjectID `bson:"_id,omitempty"              json:"user_id"`
+	GaID           string        `bson:"ga_id"                      json:"ga_id"`
+	Email          string        `bson:"email"                      json:"email"`
+	Name           string        `bson:"name"                       json:"name"`
+	Picture        string        `bson:"picture"                    json:"picture"`
+	DisplayName    string        `bson:"display_name,omitempty"     json:"display_name,omitempty"` // shown instead of Name; unique per tenant
+	DisplayNameKey string        `bson:"display_name_key,omitempty" json:"-"`                      // case-folded DisplayName for uniqueness
+	AvatarURL      string        `bson:"avatar_url,omitempty"       json:"avatar_url,omitempty"`   // shown instead of Picture
+	BirthYear      int           `bson:"birth_year,omitempty"       json:"birth_year,omitempty"`
+	Timezone       string        `bson:"timezone,omitempty"         json:"timezone,omitempty"` // IANA name; streak days are counted in it
+	Locale         string        `bson:"locale,omitempty"           json:"locale,omitempty"`   // BCP 47 tag, e.g. "en-GB"
+	Bio            string        `bson:"bio,omitempty"              json:"bio,omitempty"`
+	XP             int64         `bson:"xp,omitempty"               json:"xp"`
+	Level          int           `bson:"level,omitempty"            json:"level"`
+	FriendCode     string        `bson:"friend_code,omitempty"      json:"-"` // shared to receive friend requests without exposing the email
+	TenantID       string        `bson:"tenant_id,omitempty"        json:"-"` // empty for the default tenant
+}
+
+// PublicName returns the name shown to other players: the display name, or else the Google name.
+func (u *User) PublicName() string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+	return u.Name
+}
+
+// PublicPicture returns the avatar shown to other players: the custom avatar, or else the Google picture.
+func (u *User) PublicPicture() string {
+	if u.AvatarURL != "" {
+		return u.AvatarURL
+	}
+	return u.Picture
 }
@@ -0,0 +1,9 @@
+package request
+
+// ChallengeResultRequest is the request body for POST /api/challenge/today/result. The game type comes from
+// the challenge. ChallengeID defaults to today's; yesterday's is accepted for a short grace period after midnight.
+type ChallengeResultRequest struct {
+	ChallengeID       string             `json:"challenge_id,omitempty"`
+	QuestionResponses []QuestionResponse `json:"question_responses" binding:"required"`
+	SessionID         string             `json:"session_id,omitempty"`
+}
@@ -0,0 +1,13 @@
+package request
+
+// CreateDuelRequest is the request body for POST /api/duels. The opponent must be a friend.
+type CreateDuelRequest struct {
+	OpponentID string `json:"opponent_id" binding:"required"`
+	GameType   string `json:"gametype" binding:"required"`
+}
+
+// DuelResultRequest is the request body for POST /api/duels/:duel_id/result. The game type comes from the duel.
+type DuelResultRequest struct {
+	QuestionResponses []QuestionResponse `json:"question_responses" binding:"required"`
+	SessionID         string             `json:"session_id,omitempty"`
+}
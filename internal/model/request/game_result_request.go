@@ -1,7 +1,26 @@
 package request
 
+import "time"
+
 // GameResultRequest is the request body for POST /api/game/result.
+// SessionID is an optional client-generated ID; a retried submission with the same ID is stored once.
 type GameResultRequest struct {
 	GameType          string             `json:"gametype" binding:"required"`
 	QuestionResponses []QuestionResponse `json:"question_responses" binding:"required"`
+	SessionID         string             `json:"session_id,omitempty"`
+}
+
+// GameResultBatchRequest is the request body for POST /api/game/results/batch (offline sync).
+// Items are checked individually so one bad session does not reject the others.
+type GameResultBatchRequest struct {
+	Sessions []BatchGameResult `json:"sessions" binding:"required"`
+}
+
+// BatchGameResult is one offline session. SessionID is required so re-syncing a batch is safe;
+// PlayedAt is when the session was played on the device.
+type BatchGameResult struct {
+	GameType          string             `json:"gametype"`
+	QuestionResponses []QuestionResponse `json:"question_responses"`
+	SessionID         string             `json:"session_id"`
+	PlayedAt          time.Time          `json:"played_at"`
 }
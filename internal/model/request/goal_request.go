@@ -0,0 +1,15 @@
+package request
+
+// CreateGoalRequest is the request body for POST /api/user/goals.
+type CreateGoalRequest struct {
+	Kind     string   `json:"kind" binding:"required"` // daily_sessions or target_score
+	GameType string   `json:"game_type"`               // required for target_score; optional for daily_sessions
+	Target   *float64 `json:"target" binding:"required"`
+	Active   *bool    `json:"active"` // defaults to true
+}
+
+// UpdateGoalRequest is the request body for PATCH /api/user/goals/:goal_id. Omitted fields are unchanged.
+type UpdateGoalRequest struct {
+	Target *float64 `json:"target"`
+	Active *bool    `json:"active"`
+}
@@ -0,0 +1,16 @@
+package request
+
+// CreateGroupRequest is the request body for POST /api/groups.
+type CreateGroupRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// JoinGroupRequest is the request body for POST /api/groups/join.
+type JoinGroupRequest struct {
+	JoinCode string `json:"join_code" binding:"required"`
+}
+
+// GroupRoleRequest is the request body for PUT /api/groups/:group_id/members/:user_id/role.
+type GroupRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
@@ -0,0 +1,10 @@
+package request
+
+// NotificationPreferencesRequest is the request body for PUT /api/user/notifications/preferences.
+// It replaces the saved preferences; an empty channels list turns notifications off.
+type NotificationPreferencesRequest struct {
+	Channels      []string `json:"channels" binding:"required"`
+	DisabledKinds []string `json:"disabled_kinds"`
+	QuietStart    string   `json:"quiet_start"` // HH:MM in the user's timezone
+	QuietEnd      string   `json:"quiet_end"`
+}
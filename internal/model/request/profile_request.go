@@ -0,0 +1,12 @@
+package request
+
+// UpdateProfileRequest is the request body for PATCH /api/user/profile. Fields left out are unchanged;
+// empty values (0 for birth_year) clear them.
+type UpdateProfileRequest struct {
+	DisplayName *string `json:"display_name"` // 3–30 letters, digits, spaces and . _ -; unique
+	AvatarURL   *string `json:"avatar_url"`   // https URL
+	BirthYear   *int    `json:"birth_year"`
+	Timezone    *string `json:"timezone"` // IANA name, e.g. "Europe/Berlin"
+	Locale      *string `json:"locale"`   // BCP 47 tag, e.g. "en-GB"
+	Bio         *string `json:"bio"`      // at most 280 characters
+}
@@ -0,0 +1,8 @@
+package request
+
+// FriendRequestRequest is the request body for POST /api/friends/requests. Exactly one of user_id and
+// friend_code is required.
+type FriendRequestRequest struct {
+	UserID     string `json:"user_id"`
+	FriendCode string `json:"friend_code"`
+}
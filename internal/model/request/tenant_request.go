@@ -0,0 +1,14 @@
+package request
+
+// TenantSettingsRequest is the request body for PUT /api/tenant/settings and
+// PUT /api/admin/tenants/:tenant_id/settings. It replaces the tenant's settings as a whole.
+type TenantSettingsRequest struct {
+	Name                string             `json:"name" binding:"required"`
+	GameTypes           []string           `json:"game_types"` // empty enables all
+	GameLabels          map[string]string  `json:"game_labels"`
+	ScoringWeights      map[string]float64 `json:"scoring_weights"`                 // game types left out use the default weight
+	ScoringHalfLifeDays float64            `json:"scoring_half_life_days"`          // 0 uses the default half-life
+	Leaderboards        string             `json:"leaderboards" binding:"required"` // public, group or off
+	RetentionDays       int                `json:"retention_days"`                  // 0 keeps sessions forever
+	CORSOrigins         []string           `json:"cors_origins"`
+}
@@ -0,0 +1,6 @@
+package request
+
+// TimezoneRequest is the request body for PUT /api/user/timezone.
+type TimezoneRequest struct {
+	Timezone string `json:"timezone" binding:"required"` // IANA name, e.g. "Europe/Berlin"
+}
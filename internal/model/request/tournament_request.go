@@ -0,0 +1,22 @@
+package request
+
+import "time"
+
+// CreateTournamentRequest is the request body for POST /api/admin/tournaments. Entries open straight away
+// unless entry_opens_at is set; round_duration is a Go duration such as "24h".
+type CreateTournamentRequest struct {
+	Name          string     `json:"name" binding:"required"`
+	GameType      string     `json:"gametype" binding:"required"`
+	Format        string     `json:"format" binding:"required"`
+	EntryOpensAt  *time.Time `json:"entry_opens_at,omitempty"`
+	EntryClosesAt time.Time  `json:"entry_closes_at" binding:"required"`
+	Rounds        int        `json:"rounds" binding:"required"`
+	RoundDuration string     `json:"round_duration" binding:"required"`
+}
+
+// TournamentResultRequest is the request body for POST /api/tournaments/:tournament_id/result. The game
+// type comes from the tournament and the round is the current one.
+type TournamentResultRequest struct {
+	QuestionResponses []QuestionResponse `json:"question_responses" binding:"required"`
+	SessionID         string             `json:"session_id,omitempty"`
+}
@@ -0,0 +1,19 @@
+package response
+
+import "time"
+
+// AchievementsResponse is the response body for GET /api/user/achievements.
+type AchievementsResponse struct {
+	Achievements []AchievementResponse `json:"achievements"`
+	Unlocked     int                   `json:"unlocked"`
+	Total        int                   `json:"total"`
+}
+
+// AchievementResponse is one achievement; UnlockedAt is omitted while locked.
+type AchievementResponse struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Unlocked    bool       `json:"unlocked"`
+	UnlockedAt  *time.Time `json:"unlocked_at,omitempty"`
+}
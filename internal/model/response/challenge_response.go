@@ -0,0 +1,38 @@
+package response
+
+import "time"
+
+// ChallengeResponse describes a daily challenge. Attempt is the caller's attempt on GET /api/challenge/today.
+type ChallengeResponse struct {
+	ChallengeID string                    `json:"challenge_id"` // YYYY-MM-DD (UTC)
+	GameType    string                    `json:"gametype"`
+	Label       string                    `json:"label"`
+	Seed        int64                     `json:"seed"` // seeds the client's question generator
+	StartsAt    time.Time                 `json:"starts_at"`
+	EndsAt      time.Time                 `json:"ends_at"`
+	Attempt     *ChallengeAttemptResponse `json:"attempt,omitempty"`
+}
+
+// ChallengeAttemptResponse is the user's ranked attempt at a challenge. Rank is omitted while flagged.
+type ChallengeAttemptResponse struct {
+	SessionID   string          `json:"session_id"`
+	Result      ScoringResponse `json:"result"`
+	Rank        int64           `json:"rank,omitempty"`
+	Flagged     bool            `json:"flagged"`
+	SubmittedAt time.Time       `json:"submitted_at"`
+}
+
+// ChallengeListResponse is the response body for GET /api/challenge.
+type ChallengeListResponse struct {
+	Challenges []ChallengeResponse `json:"challenges"`
+}
+
+// ChallengeLeaderboardEntry is one row of GET /api/challenge/:challenge_id/leaderboard.
+type ChallengeLeaderboardEntry struct {
+	Rank        int                  `json:"rank"`
+	User        CompositeUserSummary `json:"user"`
+	Score       float64              `json:"score"`
+	Accuracy    float64              `json:"accuracy"`
+	AvgTime     float64              `json:"avgTime"`
+	SubmittedAt time.Time            `json:"submitted_at"`
+}
@@ -0,0 +1,40 @@
+package response
+
+import "time"
+
+// DigestListResponse is the response body for GET /api/user/digests.
+type DigestListResponse struct {
+	Digests []DigestResponse `json:"digests"`
+}
+
+// DigestResponse is a user's weekly summary (week_start inclusive, week_end exclusive, UTC).
+type DigestResponse struct {
+	WeekStart        time.Time                   `json:"week_start"`
+	WeekEnd          time.Time                   `json:"week_end"`
+	SessionsPlayed   int                         `json:"sessions_played"`
+	PreviousSessions int                         `json:"previous_sessions"`
+	Improvements     []DigestImprovementResponse `json:"improvements"`
+	OverallRank      int64                       `json:"overall_rank,omitempty"`
+	RankChange       int64                       `json:"rank_change"` // positive when the user climbed
+	GoalsHit         []DigestGoalResponse        `json:"goals_hit"`
+	GeneratedAt      time.Time                   `json:"generated_at"`
+}
+
+// DigestImprovementResponse is one game type played during the week, compared with the week before.
+type DigestImprovementResponse struct {
+	GameType    string  `json:"game_type"`
+	Label       string  `json:"label"`
+	Sessions    int     `json:"sessions"`
+	AvgScore    float64 `json:"avg_score"`
+	PreviousAvg float64 `json:"previous_avg"`
+	Change      float64 `json:"change"`
+	WeekBest    float64 `json:"week_best"`
+}
+
+// DigestGoalResponse is a goal met during the week.
+type DigestGoalResponse struct {
+	GoalID string  `json:"goal_id"`
+	Kind   string  `json:"kind"`
+	Target float64 `json:"target"`
+	Times  int     `json:"times"`
+}
@@ -0,0 +1,58 @@
+package response
+
+import "time"
+
+// DuelResponse describes a duel from the caller's side. The opponent's result is hidden until the caller
+// has played or the duel is over. Outcome (won, lost or draw) is set once the duel is completed.
+type DuelResponse struct {
+	DuelID      string             `json:"duel_id"`
+	GameType    string             `json:"gametype"`
+	Label       string             `json:"label"`
+	Seed        int64              `json:"seed"` // seeds the client's question generator
+	Status      string             `json:"status"`
+	Role        string             `json:"role"` // challenger or opponent
+	Me          DuelPlayerResponse `json:"me"`
+	Opponent    DuelPlayerResponse `json:"opponent"`
+	Outcome     string             `json:"outcome,omitempty"`
+	Forfeit     bool               `json:"forfeit,omitempty"`
+	CreatedAt   time.Time          `json:"created_at"`
+	AcceptedAt  *time.Time         `json:"accepted_at,omitempty"`
+	ExpiresAt   time.Time          `json:"expires_at"`
+	CompletedAt *time.Time         `json:"completed_at,omitempty"`
+}
+
+// DuelPlayerResponse is one player of a duel and their result, if submitted and visible.
+type DuelPlayerResponse struct {
+	User   CompositeUserSummary `json:"user"`
+	Played bool                 `json:"played"`
+	Result *DuelEntryResponse   `json:"result,omitempty"`
+}
+
+// DuelEntryResponse is a player's session in a duel.
+type DuelEntryResponse struct {
+	SessionID   string    `json:"session_id"`
+	Score       float64   `json:"score"`
+	Accuracy    float64   `json:"accuracy"`
+	AvgTime     float64   `json:"avgTime"`
+	Flagged     bool      `json:"flagged"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+// DuelListResponse is the response body for GET /api/duels.
+type DuelListResponse struct {
+	Duels []DuelResponse `json:"duels"`
+}
+
+// DuelResultResponse is the response body for POST /api/duels/:duel_id/result.
+type DuelResultResponse struct {
+	Duel   DuelResponse    `json:"duel"`
+	Result ScoringResponse `json:"result"`
+}
+
+// DuelRecordResponse is the response body for GET /api/user/duels/record.
+type DuelRecordResponse struct {
+	Wins   int `json:"wins"`
+	Losses int `json:"losses"`
+	Draws  int `json:"draws"`
+	Played int `json:"played"`
+}
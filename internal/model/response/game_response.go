@@ -0,0 +1,14 @@
+package response
+
+// GameCatalogResponse is the response body for GET /api/games.
+type GameCatalogResponse struct {
+	TenantID  string             `json:"tenant_id"`
+	Name      string             `json:"name"`
+	GameTypes []GameCatalogEntry `json:"game_types"`
+}
+
+// GameCatalogEntry is one game type the tenant offers.
+type GameCatalogEntry struct {
+	GameType string `json:"gametype"`
+	Label    string `json:"label"`
+}
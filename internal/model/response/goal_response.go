@@ -0,0 +1,23 @@
+package response
+
+import "time"
+
+// GoalsResponse is the response body for GET /api/user/goals.
+type GoalsResponse struct {
+	Goals []GoalResponse `json:"goals"`
+}
+
+// GoalResponse is one goal with the user's progress: today's sessions for daily_sessions goals,
+// the best session score for target_score goals.
+type GoalResponse struct {
+	GoalID     string     `json:"goal_id"`
+	Kind       string     `json:"kind"`
+	GameType   string     `json:"game_type,omitempty"`
+	Target     float64    `json:"target"`
+	Active     bool       `json:"active"`
+	Progress   float64    `json:"progress"`
+	Met        bool       `json:"met"`                   // today for daily goals, ever for one-off goals
+	TimesMet   int        `json:"times_met"`             // days met for daily goals
+	AchievedAt *time.Time `json:"achieved_at,omitempty"` // one-off goals only
+	CreatedAt  time.Time  `json:"created_at"`
+}
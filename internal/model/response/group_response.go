@@ -0,0 +1,90 @@
+package response
+
+import "time"
+
+// GroupResponse is a group as seen by one of its members. JoinCode is only set for admins and the owner.
+type GroupResponse struct {
+	GroupID   string    `json:"group_id"`
+	Name      string    `json:"name"`
+	OwnerID   string    `json:"owner_id"`
+	JoinCode  string    `json:"join_code,omitempty"`
+	Role      string    `json:"role"`
+	Members   int64     `json:"members"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// GroupListResponse is the response body for GET /api/groups.
+type GroupListResponse struct {
+	Groups []GroupResponse `json:"groups"`
+}
+
+// GroupMemberResponse is one member of a group.
+type GroupMemberResponse struct {
+	User     CompositeUserSummary `json:"user"`
+	Role     string               `json:"role"`
+	JoinedAt time.Time            `json:"joined_at"`
+}
+
+// GroupMembersResponse is the response body for GET /api/groups/:group_id/members.
+type GroupMembersResponse struct {
+	Members []GroupMemberResponse `json:"members"`
+}
+
+// GroupLeaderboardResponse is the response body for GET /api/groups/:group_id/leaderboard.
+type GroupLeaderboardResponse struct {
+	GroupID     string             `json:"group_id"`
+	GameType    string             `json:"gametype,omitempty"`
+	Window      string             `json:"window"`
+	Leaderboard []LeaderboardEntry `json:"leaderboard"`
+}
+
+// GroupProgressResponse is the response body for GET /api/groups/:group_id/progress.
+type GroupProgressResponse struct {
+	GroupID string                   `json:"group_id"`
+	Members []GroupMemberProgressRow `json:"members"`
+}
+
+// GroupMemberProgressRow is one member on the owner's progress dashboard.
+type GroupMemberProgressRow struct {
+	User         CompositeUserSummary    `json:"user"`
+	Role         string                  `json:"role"`
+	OverallScore float64                 `json:"overall_score"`
+	GameTypes    []GroupGameTypeProgress `json:"game_types"`
+	LastActiveAt *time.Time              `json:"last_active_at,omitempty"`
+}
+
+// GroupGameTypeProgress is a member's aggregates on one game type.
+type GroupGameTypeProgress struct {
+	GameType  string  `json:"gametype"`
+	Label     string  `json:"label"`
+	AvgScore  float64 `json:"avg_score"`
+	HighScore float64 `json:"high_score"`
+	Sessions  int     `json:"sessions"`
+}
+
+// GroupReportResponse is the JSON response body for GET /api/groups/:group_id/report.
+type GroupReportResponse struct {
+	GroupID string                 `json:"group_id"`
+	From    string                 `json:"from"`
+	To      string                 `json:"to"`
+	Members []GroupMemberReportRow `json:"members"`
+}
+
+// GroupMemberReportRow is one member of a group report.
+type GroupMemberReportRow struct {
+	User         CompositeUserSummary `json:"user"`
+	Role         string               `json:"role"`
+	Sessions     int                  `json:"sessions"`
+	LastActiveAt *time.Time           `json:"last_active_at,omitempty"`
+	Domains      []GroupDomainReport  `json:"domains"`
+}
+
+// GroupDomainReport is a member's results on one game type over the report period. AvgScore and
+// Improvement are null when no session counted towards them.
+type GroupDomainReport struct {
+	GameType    string   `json:"gametype"`
+	Label       string   `json:"label"`
+	Sessions    int      `json:"sessions"`
+	AvgScore    *float64 `json:"avg_score"`
+	Improvement *float64 `json:"improvement"`
+}
@@ -0,0 +1,27 @@
+package response
+
+// HistoryResponse is the response body for GET /api/user/history.
+type HistoryResponse struct {
+	GameType string          `json:"gametype"`
+	Bucket   string          `json:"bucket"`
+	From     string          `json:"from"` // dd-mm-yyyy
+	To       string          `json:"to"`   // dd-mm-yyyy
+	Buckets  []HistoryBucket `json:"buckets"`
+	Trend    HistoryTrend    `json:"trend"`
+}
+
+// HistoryBucket is one point of the progress chart; empty buckets have count 0.
+type HistoryBucket struct {
+	Start    string  `json:"start"` // dd-mm-yyyy
+	Count    int     `json:"count"`
+	Mean     float64 `json:"mean"`
+	Best     float64 `json:"best"`
+	Accuracy float64 `json:"accuracy"`
+	AvgTime  float64 `json:"avgTime"`
+}
+
+// HistoryTrend is the least-squares slope of scores over the requested range.
+type HistoryTrend struct {
+	SlopePerDay    float64 `json:"slope_per_day"`
+	SlopePerBucket float64 `json:"slope_per_bucket"`
+}
@@ -7,12 +7,27 @@ type LoginResponse struct {
 	User        UserInfo `json:"user"`
 }
 
-// UserInfo represents user details returned in auth responses.
+// UserInfo represents user details and profile returned in auth responses and by PATCH /api/user/profile.
 type UserInfo struct {
-	UserID    string `json:"user_id"`
-	Email     string `json:"email"`
-	Name      string `json:"name"`
-	Picture   string `json:"picture"`
-	FirstName string `json:"first_name,omitempty"`
-	LastName  string `json:"last_name,omitempty"`
+	UserID      string     `json:"user_id"`
+	Email       string     `json:"email"`
+	Name        string     `json:"name"`         // from Google
+	Picture     string     `json:"picture"`      // from Google
+	DisplayName string     `json:"display_name"` // the user's display name, or else name
+	AvatarURL   string     `json:"avatar_url"`   // the user's avatar, or else picture
+	BirthYear   int        `json:"birth_year,omitempty"`
+	Timezone    string     `json:"timezone,omitempty"`
+	Locale      string     `json:"locale,omitempty"`
+	Bio         string     `json:"bio,omitempty"`
+	FirstName   string     `json:"first_name,omitempty"`
+	LastName    string     `json:"last_name,omitempty"`
+	Level       *LevelInfo `json:"level,omitempty"`
+}
+
+// LevelInfo is the user's XP and progress towards the next level.
+type LevelInfo struct {
+	Level        int   `json:"level"`
+	XP           int64 `json:"xp"`
+	IntoLevel    int64 `json:"xp_into_level"`
+	ForNextLevel int64 `json:"xp_for_next_level"`
 }
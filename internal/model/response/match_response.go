@@ -0,0 +1,31 @@
+package response
+
+import "time"
+
+// MatchResponse describes a finished real-time match.
+type MatchResponse struct {
+	MatchID   string                `json:"match_id"`
+	GameType  string                `json:"gametype"`
+	Label     string                `json:"label"`
+	Questions int                   `json:"questions"`
+	Place     int                   `json:"place"` // the caller's final place
+	Players   []MatchPlayerResponse `json:"players"`
+	StartedAt time.Time             `json:"started_at"`
+	EndedAt   time.Time             `json:"ended_at"`
+}
+
+// MatchPlayerResponse is one player's final standing in a match.
+type MatchPlayerResponse struct {
+	Place     int                  `json:"place"`
+	User      CompositeUserSummary `json:"user"`
+	SessionID string               `json:"session_id,omitempty"`
+	Score     float64              `json:"score"`
+	Accuracy  float64              `json:"accuracy"`
+	AvgTime   float64              `json:"avgTime"`
+	Left      bool                 `json:"left,omitempty"`
+}
+
+// MatchListResponse is the response body for GET /api/matches.
+type MatchListResponse struct {
+	Matches []MatchResponse `json:"matches"`
+}
@@ -0,0 +1,31 @@
+package response
+
+import "time"
+
+// NotificationListResponse is the response body for GET /api/user/notifications.
+type NotificationListResponse struct {
+	Notifications []NotificationResponse `json:"notifications"`
+}
+
+// NotificationResponse is one outbox entry as the user sees it.
+type NotificationResponse struct {
+	ID        string            `json:"id"`
+	Kind      string            `json:"kind"`
+	Channel   string            `json:"channel"`
+	Subject   string            `json:"subject"`
+	Body      string            `json:"body"`
+	Data      map[string]string `json:"data,omitempty"`
+	Status    string            `json:"status"`
+	CreatedAt time.Time         `json:"created_at"`
+	SentAt    *time.Time        `json:"sent_at,omitempty"`
+}
+
+// NotificationPreferencesResponse is the response body for GET and PUT /api/user/notifications/preferences.
+type NotificationPreferencesResponse struct {
+	Channels          []string `json:"channels"`
+	DisabledKinds     []string `json:"disabled_kinds"`
+	QuietStart        string   `json:"quiet_start,omitempty"`
+	QuietEnd          string   `json:"quiet_end,omitempty"`
+	AvailableChannels []string `json:"available_channels"`
+	Kinds             []string `json:"kinds"`
+}
@@ -0,0 +1,44 @@
+package response
+
+import "time"
+
+// CognitiveProfileResponse is the response body for GET /api/user/profile/cognitive.
+// Radar holds parallel labels/values arrays ready for a radar chart.
+type CognitiveProfileResponse struct {
+	Composite    float64          `json:"composite"`
+	HalfLifeDays float64          `json:"half_life_days"`
+	Domains      []DomainScore    `json:"domains"`
+	Radar        RadarChartSeries `json:"radar"`
+}
+
+// DomainScore is one cognitive domain of the profile.
+type DomainScore struct {
+	GameType     string     `json:"game_type"`
+	Label        string     `json:"label"`
+	Score        float64    `json:"score"`
+	Weight       float64    `json:"weight"`
+	Sessions     int        `json:"sessions"`
+	LastPlayedAt *time.Time `json:"last_played_at,omitempty"`
+}
+
+// RadarChartSeries is a single radar series; Labels[i] belongs to Values[i].
+type RadarChartSeries struct {
+	Labels []string  `json:"labels"`
+	Keys   []string  `json:"keys"`
+	Values []float64 `json:"values"`
+	Max    float64   `json:"max"`
+}
+
+// CompositeLeaderboardEntry is one row of GET /api/dashboard/composite.
+type CompositeLeaderboardEntry struct {
+	Rank         int                  `json:"rank"`
+	User         CompositeUserSummary `json:"user"`
+	OverallScore float64              `json:"overall_score"`
+}
+
+// CompositeUserSummary is the public user info shown on the composite leaderboard.
+type CompositeUserSummary struct {
+	ID    string `json:"_id"`
+	Name  string `json:"name"`
+	Photo string `json:"photo"`
+}
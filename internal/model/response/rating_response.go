@@ -0,0 +1,36 @@
+package response
+
+import "time"
+
+// RatingResponse is a Glicko-2 rating. Provisional ratings are too uncertain to rank yet.
+type RatingResponse struct {
+	Rating      float64 `json:"rating"`
+	Deviation   float64 `json:"deviation"`
+	Games       int     `json:"games"`
+	Provisional bool    `json:"provisional"`
+}
+
+// RatingLeaderboardEntry is one row of GET /api/dashboard/ratings.
+type RatingLeaderboardEntry struct {
+	Rank   int                  `json:"rank"`
+	User   CompositeUserSummary `json:"user"`
+	Rating RatingResponse       `json:"rating"`
+}
+
+// RatingChangeResponse is one entry of GET /api/user/ratings/history.
+type RatingChangeResponse struct {
+	Source    string    `json:"source"`
+	RefID     string    `json:"ref_id"`
+	Games     int       `json:"games"`
+	Rating    float64   `json:"rating"`
+	Deviation float64   `json:"deviation"`
+	Change    float64   `json:"change"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RatingHistoryResponse is returned by GET /api/user/ratings/history.
+type RatingHistoryResponse struct {
+	GameType string                 `json:"gametype"`
+	Current  RatingResponse         `json:"current"`
+	History  []RatingChangeResponse `json:"history"`
+}
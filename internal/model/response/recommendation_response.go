@@ -0,0 +1,17 @@
+package response
+
+// RecommendationsResponse is the response body for GET /api/user/recommendations.
+type RecommendationsResponse struct {
+	Date        string               `json:"date"` // YYYY-MM-DD in the user's timezone
+	Recommender string               `json:"recommender"`
+	Experiment  string               `json:"experiment,omitempty"`
+	Plan        []RecommendationItem `json:"plan"`
+}
+
+// RecommendationItem is one suggested game with the reason it was picked.
+type RecommendationItem struct {
+	GameType   string `json:"gametype"`
+	Label      string `json:"label"`
+	Difficulty string `json:"difficulty"` // easy, medium or hard
+	Reason     string `json:"reason"`
+}
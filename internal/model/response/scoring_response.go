@@ -2,9 +2,30 @@ package response
 
 // ScoringResponse is the response body for the scoring API.
 type ScoringResponse struct {
-	Score     float64 `json:"score"`     // 0–100
-	Questions int     `json:"questions"` // total number of questions
-	Correct   int     `json:"correct"`   // number correct (or solved in sequential)
-	Accuracy  float64 `json:"accuracy"`  // correct / questions (0–1)
-	AvgTime   float64 `json:"avgTime"`   // average time per question in seconds
+	Score      float64  `json:"score"`                // 0–100
+	Questions  int      `json:"questions"`            // total number of questions
+	Correct    int      `json:"correct"`              // number correct (or solved in sequential)
+	Accuracy   float64  `json:"accuracy"`             // correct / questions (0–1)
+	AvgTime    float64  `json:"avgTime"`              // average time per question in seconds
+	Percentile *float64 `json:"percentile,omitempty"` // 0–100 against all players of the game type (when norms exist)
+	SessionID  string   `json:"session_id,omitempty"` // stored session (authenticated submissions only)
+	XPGained   int64    `json:"xp_gained,omitempty"`  // XP earned by the session (authenticated submissions only)
+	Level      int      `json:"level,omitempty"`      // level after the XP was added
+	LevelUp    bool     `json:"level_up,omitempty"`   // the session reached a new level
+}
+
+// GameResultBatchResponse is the response body for POST /api/game/results/batch.
+type GameResultBatchResponse struct {
+	Results  []BatchItemResponse `json:"results"` // in request order
+	Created  int                 `json:"created"`
+	Replayed int                 `json:"replayed"`
+	Failed   int                 `json:"failed"`
+}
+
+// BatchItemResponse is the outcome of one synced session.
+type BatchItemResponse struct {
+	SessionID string           `json:"session_id"`
+	Status    string           `json:"status"` // "created", "replayed" or "error"
+	Result    *ScoringResponse `json:"result,omitempty"`
+	Error     string           `json:"error,omitempty"`
 }
@@ -0,0 +1,32 @@
+package response
+
+import "time"
+
+// SessionListResponse is the response body for GET /api/user/sessions.
+// NextCursor is omitted on the last page.
+type SessionListResponse struct {
+	Sessions   []SessionSummary `json:"sessions"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+// SessionSummary is one past session without its question_responses.
+type SessionSummary struct {
+	SessionID    string          `json:"session_id"`
+	GameType     string          `json:"gametype"`
+	SessionScore ScoringResponse `json:"session_score"`
+	Timestamp    time.Time       `json:"timestamp"`
+	Flags        []string        `json:"flags,omitempty"`
+	ReviewStatus string          `json:"review_status,omitempty"`
+}
+
+// SessionDetailResponse is the response body for GET /api/user/sessions/:session_id.
+type SessionDetailResponse struct {
+	SessionSummary
+	QuestionResponses []QuestionResponse `json:"question_responses"`
+}
+
+// QuestionResponse is one stored question response, as submitted.
+type QuestionResponse struct {
+	TimeTaken float64 `json:"time_taken"`
+	Outcome   string  `json:"outcome,omitempty"`
+}
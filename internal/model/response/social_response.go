@@ -0,0 +1,42 @@
+package response
+
+import "time"
+
+// FriendCodeResponse is the response body for GET /api/user/friend-code.
+type FriendCodeResponse struct {
+	FriendCode string `json:"friend_code"`
+	InviteURL  string `json:"invite_url,omitempty"`
+}
+
+// ConnectionsResponse is the response body for GET /api/friends.
+type ConnectionsResponse struct {
+	Friends   []CompositeUserSummary `json:"friends"`
+	Following []CompositeUserSummary `json:"following"`
+	Followers []CompositeUserSummary `json:"followers"`
+}
+
+// FriendRequestsResponse is the response body for GET /api/friends/requests.
+type FriendRequestsResponse struct {
+	Incoming []FriendRequestResponse `json:"incoming"`
+	Outgoing []FriendRequestResponse `json:"outgoing"`
+}
+
+// FriendRequestResponse is one pending friend request; User is the other side.
+type FriendRequestResponse struct {
+	User      CompositeUserSummary `json:"user"`
+	CreatedAt time.Time            `json:"created_at"`
+}
+
+// BlockedUsersResponse is the response body for GET /api/blocks.
+type BlockedUsersResponse struct {
+	Blocked []CompositeUserSummary `json:"blocked"`
+}
+
+// LeaderboardEntry is one row of GET /api/dashboard/friends and GET /api/groups/:group_id/leaderboard.
+type LeaderboardEntry struct {
+	Rank     int                  `json:"rank"`
+	User     CompositeUserSummary `json:"user"`
+	Score    float64              `json:"score"`
+	Sessions int                  `json:"sessions,omitempty"` // sessions in the window (not set for window=all)
+	IsMe     bool                 `json:"is_me"`
+}
@@ -0,0 +1,14 @@
+package response
+
+// StreakResponse is the response body for GET /api/user/streak and the "streak" key of GET /api/user/stats.
+type StreakResponse struct {
+	Current        int    `json:"current"`
+	Longest        int    `json:"longest"`
+	LastPlayedDay  string `json:"last_played_day,omitempty"` // YYYY-MM-DD in Timezone
+	PlayedToday    bool   `json:"played_today"`
+	FreezeTokens   int    `json:"freeze_tokens"`
+	FreezesPending int    `json:"freezes_pending"` // tokens the next play will spend on missed days
+	FreezesUsed    int    `json:"freezes_used"`
+	NextFreezeIn   int    `json:"next_freeze_in"` // streak days until another token is earned (0: at maximum)
+	Timezone       string `json:"timezone"`
+}
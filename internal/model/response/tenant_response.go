@@ -0,0 +1,19 @@
+package response
+
+import "time"
+
+// TenantSettingsResponse is the response body for GET and PUT /api/tenant/settings and
+// /api/admin/tenants/:tenant_id/settings.
+type TenantSettingsResponse struct {
+	TenantID            string             `json:"tenant_id"`
+	Name                string             `json:"name"`
+	GameTypes           []string           `json:"game_types"`
+	GameLabels          map[string]string  `json:"game_labels,omitempty"`
+	ScoringWeights      map[string]float64 `json:"scoring_weights,omitempty"`
+	ScoringHalfLifeDays float64            `json:"scoring_half_life_days"`
+	Leaderboards        string             `json:"leaderboards"`
+	RetentionDays       int                `json:"retention_days"`
+	CORSOrigins         []string           `json:"cors_origins"`
+	UpdatedAt           *time.Time         `json:"updated_at,omitempty"` // missing until the settings are first saved
+	UpdatedBy           string             `json:"updated_by,omitempty"`
+}
@@ -0,0 +1,90 @@
+package response
+
+import "time"
+
+// TournamentResponse describes a tournament. Round times are set while it is running.
+type TournamentResponse struct {
+	TournamentID  string     `json:"tournament_id"`
+	Name          string     `json:"name"`
+	GameType      string     `json:"gametype"`
+	Label         string     `json:"label"`
+	Format        string     `json:"format"`
+	Status        string     `json:"status"`
+	EntryOpensAt  time.Time  `json:"entry_opens_at"`
+	EntryClosesAt time.Time  `json:"entry_closes_at"`
+	Rounds        int        `json:"rounds"`
+	TotalRounds   int        `json:"total_rounds,omitempty"`
+	RoundDuration string     `json:"round_duration"`
+	MaxPlayers    int        `json:"max_players,omitempty"`
+	CurrentRound  int        `json:"current_round"`
+	RoundEndsAt   *time.Time `json:"round_ends_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	StartedAt     *time.Time `json:"started_at,omitempty"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+}
+
+// TournamentListResponse is the response body for GET /api/tournaments.
+type TournamentListResponse struct {
+	Tournaments []TournamentResponse `json:"tournaments"`
+}
+
+// TournamentEntryResponse is the response body for POST /api/tournaments/:tournament_id/join.
+type TournamentEntryResponse struct {
+	TournamentID string    `json:"tournament_id"`
+	JoinedAt     time.Time `json:"joined_at"`
+}
+
+// TournamentStandingResponse is one row of a tournament's standings.
+type TournamentStandingResponse struct {
+	Place           int                  `json:"place"`
+	User            CompositeUserSummary `json:"user"`
+	Seed            int                  `json:"seed,omitempty"`
+	Points          float64              `json:"points"`
+	Wins            int                  `json:"wins"`
+	Draws           int                  `json:"draws"`
+	Losses          int                  `json:"losses"`
+	NoShows         int                  `json:"no_shows"`
+	ScoreTotal      float64              `json:"score_total"`
+	EliminatedRound int                  `json:"eliminated_round,omitempty"`
+}
+
+// TournamentStandingsResponse is the response body for GET /api/tournaments/:tournament_id/standings.
+type TournamentStandingsResponse struct {
+	Tournament TournamentResponse           `json:"tournament"`
+	Standings  []TournamentStandingResponse `json:"standings"`
+}
+
+// TournamentPairingResponse is one game of a round. Results are hidden until the game is settled, except
+// the caller's own; the seed is only given to the players.
+type TournamentPairingResponse struct {
+	Round       int                               `json:"round"`
+	Table       int                               `json:"table"`
+	Seed        int64                             `json:"seed,omitempty"`
+	Players     []TournamentPairingPlayerResponse `json:"players"`
+	Bye         bool                              `json:"bye,omitempty"`
+	Status      string                            `json:"status"`
+	WinnerID    string                            `json:"winner_id,omitempty"`
+	NoShow      bool                              `json:"no_show,omitempty"`
+	StartsAt    time.Time                         `json:"starts_at"`
+	EndsAt      time.Time                         `json:"ends_at"`
+	CompletedAt *time.Time                        `json:"completed_at,omitempty"`
+}
+
+// TournamentPairingPlayerResponse is one player of a pairing and their result, if submitted and visible.
+type TournamentPairingPlayerResponse struct {
+	User   CompositeUserSummary `json:"user"`
+	Played bool                 `json:"played"`
+	Result *DuelEntryResponse   `json:"result,omitempty"`
+}
+
+// TournamentPairingsResponse is the response body for GET /api/tournaments/:tournament_id/pairings.
+type TournamentPairingsResponse struct {
+	Tournament TournamentResponse          `json:"tournament"`
+	Pairings   []TournamentPairingResponse `json:"pairings"`
+}
+
+// TournamentResultResponse is the response body for POST /api/tournaments/:tournament_id/result.
+type TournamentResultResponse struct {
+	Pairing TournamentPairingResponse `json:"pairing"`
+	Result  ScoringResponse           `json:"result"`
+}
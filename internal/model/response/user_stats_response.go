@@ -1,7 +1,12 @@
 package response
 
 // GameTypeStats is per-game-type stats in GET /api/user/stats.
+// Percentiles are 0–100 against all players of the game type and omitted until norms exist; the rating
+// is omitted until the user played a rated game.
 type GameTypeStats struct {
-	AvgScore float64 `json:"avg_score"`
-	MaxScore float64 `json:"max_score"`
+	AvgScore      float64         `json:"avg_score"`
+	MaxScore      float64         `json:"max_score"`
+	AvgPercentile *float64        `json:"avg_percentile,omitempty"`
+	MaxPercentile *float64        `json:"max_percentile,omitempty"`
+	Rating        *RatingResponse `json:"rating,omitempty"`
 }
@@ -0,0 +1,72 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"sort"
+)
+
+// Channel names.
+const (
+	ChannelLog     = "log"
+	ChannelFile    = "file"
+	ChannelWebhook = "webhook"
+	ChannelEmail   = "email"
+	ChannelPush    = "push"
+)
+
+// ErrNoAddress is returned by a channel that cannot reach the recipient (e.g. email without an address).
+// The dispatcher does not retry it.
+var ErrNoAddress = errors.New("recipient has no address for this channel")
+
+// Recipient is the user a message is delivered to.
+type Recipient struct {
+	UserID string
+	Name   string
+	Email  string
+}
+
+// Message is one rendered notification.
+type Message struct {
+	ID      string // outbox id; receivers can use it to drop duplicates on retries
+	Kind    string
+	To      Recipient
+	Subject string
+	Body    string
+	Data    map[string]string
+}
+
+// Channel delivers messages over one medium. Send must be safe for concurrent use.
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, msg Message) error
+}
+
+// Registry holds the configured channels by name.
+type Registry struct {
+	channels map[string]Channel
+}
+
+// NewRegistry builds a Registry from channels. A later channel replaces an earlier one of the same name.
+func NewRegistry(channels ...Channel) *Registry {
+	r := &Registry{channels: make(map[string]Channel, len(channels))}
+	for _, c := range channels {
+		r.channels[c.Name()] = c
+	}
+	return r
+}
+
+// Get returns the named channel, or nil if it is not configured.
+func (r *Registry) Get(name string) Channel {
+	return r.channels[name]
+}
+
+// Names returns the configured channel names, sorted.
+func (r *Registry) Names() []string {
+	out := make([]string, 0, len(r.channels))
+	for name := range r.channels {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,52 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+)
+
+// EmailChannel sends messages as plain-text email over SMTP.
+type EmailChannel struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewEmailChannel creates a new EmailChannel. Without username, mail is sent unauthenticated.
+func NewEmailChannel(host string, port int, username, password, from string) *EmailChannel {
+	c := &EmailChannel{
+		addr: net.JoinHostPort(host, strconv.Itoa(port)),
+		from: from,
+	}
+	if username != "" {
+		c.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return c
+}
+
+// Name returns "email".
+func (c *EmailChannel) Name() string { return ChannelEmail }
+
+// Send mails msg to the recipient's address.
+func (c *EmailChannel) Send(_ context.Context, msg Message) error {
+	if msg.To.Email == "" {
+		return ErrNoAddress
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", c.from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To.Email)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Message-ID: <%s@brainbash>\r\n", msg.ID)
+	b.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(msg.Body)
+	b.WriteString("\r\n")
+
+	if err := smtp.SendMail(c.addr, c.auth, c.from, []string{msg.To.Email}, []byte(b.String())); err != nil {
+		return fmt.Errorf("send email: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// FileChannel appends messages as JSON lines to a file, so development setups and tests can inspect
+// what would have been sent.
+type FileChannel struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileChannel creates a new FileChannel writing to path.
+func NewFileChannel(path string) *FileChannel {
+	return &FileChannel{path: path}
+}
+
+// Name returns "file".
+func (c *FileChannel) Name() string { return ChannelFile }
+
+// Send appends msg to the file.
+func (c *FileChannel) Send(_ context.Context, msg Message) error {
+	line, err := json.Marshal(struct {
+		Message
+		SentAt time.Time
+	}{msg, time.Now().UTC()})
+	if err != nil {
+		return fmt.Errorf("encode notification: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f, err := os.OpenFile(c.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open notification file: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write notification file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package notify
+
+import (
+	"context"
+	"log"
+)
+
+// LogChannel writes messages to the application log. Meant for development.
+type LogChannel struct{}
+
+// NewLogChannel creates a new LogChannel.
+func NewLogChannel() *LogChannel {
+	return &LogChannel{}
+}
+
+// Name returns "log".
+func (c *LogChannel) Name() string { return ChannelLog }
+
+// Send logs the message.
+func (c *LogChannel) Send(_ context.Context, msg Message) error {
+	log.Printf("Notify [%s] to %s: %s — %s", msg.Kind, msg.To.UserID, msg.Subject, msg.Body)
+	return nil
+}
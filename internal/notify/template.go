@@ -0,0 +1,108 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Notification kinds.
+const (
+	KindStreakRisk          = "streak_risk"
+	KindRankLost            = "rank_lost"
+	KindAchievementUnlocked = "achievement_unlocked"
+	KindGoalMet             = "goal_met"
+	KindWeeklyDigest        = "weekly_digest"
+	KindDuelReceived        = "duel_received"
+	KindDuelResult          = "duel_result"
+)
+
+// Kinds lists every notification kind.
+var Kinds = []string{KindStreakRisk, KindRankLost, KindAchievementUnlocked, KindGoalMet, KindWeeklyDigest, KindDuelReceived, KindDuelResult}
+
+// Template is the subject and body of a kind, in text/template syntax over the message data.
+type Template struct {
+	Subject string
+	Body    string
+}
+
+// defaultTemplates are used for kinds without a configured template.
+var defaultTemplates = map[string]Template{
+	KindStreakRisk: {
+		Subject: "Your {{.streak}}-day streak ends at midnight",
+		Body:    "You haven't played today. One game keeps your {{.streak}}-day streak alive.",
+	},
+	KindRankLost: {
+		Subject: "You were overtaken in {{.game}}",
+		Body:    "{{if eq .rank \"0\"}}Someone knocked you out of the {{.game}} top 10.{{else}}You are now #{{.rank}} in {{.game}}.{{end}} Play again to win your spot back.",
+	},
+	KindAchievementUnlocked: {
+		Subject: "Achievement unlocked: {{.name}}",
+		Body:    "{{.description}}",
+	},
+	KindGoalMet: {
+		Subject: "Goal reached!",
+		Body:    "You met your goal: {{.goal}}.",
+	},
+	KindWeeklyDigest: {
+		Subject: "Your week in BrainBash",
+		Body:    "You played {{.sessions}} sessions this week{{if .goals_hit}} and met {{.goals_hit}} goals{{end}}. Open the app to see your progress.",
+	},
+	KindDuelReceived: {
+		Subject: "{{.challenger}} challenged you to a duel",
+		Body:    "{{.challenger}} wants to duel you in {{.game}}. Accept before {{.expires_at}} to play.",
+	},
+	KindDuelResult: {
+		Subject: "{{if eq .outcome \"won\"}}You won your duel{{else if eq .outcome \"lost\"}}You lost your duel{{else}}Your duel was a draw{{end}}",
+		Body:    "Your {{.game}} duel against {{.opponent}} is over{{if .forfeit}} by forfeit{{end}}. Open the app to see the scores.",
+	},
+}
+
+// Renderer turns message data into a subject and body per kind.
+type Renderer struct {
+	subjects map[string]*template.Template
+	bodies   map[string]*template.Template
+}
+
+// NewRenderer parses the default templates with overrides applied. Overrides may set only a
+// subject or only a body. Returns error if a template does not parse.
+func NewRenderer(overrides map[string]Template) (*Renderer, error) {
+	r := &Renderer{
+		subjects: make(map[string]*template.Template),
+		bodies:   make(map[string]*template.Template),
+	}
+	for kind, t := range defaultTemplates {
+		if o, ok := overrides[kind]; ok {
+			if o.Subject != "" {
+				t.Subject = o.Subject
+			}
+			if o.Body != "" {
+				t.Body = o.Body
+			}
+		}
+		var err error
+		if r.subjects[kind], err = template.New(kind + ".subject").Option("missingkey=zero").Parse(t.Subject); err != nil {
+			return nil, fmt.Errorf("parse %s subject: %w", kind, err)
+		}
+		if r.bodies[kind], err = template.New(kind + ".body").Option("missingkey=zero").Parse(t.Body); err != nil {
+			return nil, fmt.Errorf("parse %s body: %w", kind, err)
+		}
+	}
+	return r, nil
+}
+
+// Render returns the subject and body of kind for data. Returns error for an unknown kind.
+func (r *Renderer) Render(kind string, data map[string]string) (subject, body string, err error) {
+	st, ok := r.subjects[kind]
+	if !ok {
+		return "", "", fmt.Errorf("unknown notification kind: %s", kind)
+	}
+	var sb, bb strings.Builder
+	if err := st.Execute(&sb, data); err != nil {
+		return "", "", fmt.Errorf("render %s subject: %w", kind, err)
+	}
+	if err := r.bodies[kind].Execute(&bb, data); err != nil {
+		return "", "", fmt.Errorf("render %s body: %w", kind, err)
+	}
+	return sb.String(), bb.String(), nil
+}
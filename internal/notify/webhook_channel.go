@@ -0,0 +1,75 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WebhookChannel POSTs messages as JSON to a URL. It backs both the webhook channel and the push
+// channel, where the URL is a push gateway that resolves the user's devices.
+type WebhookChannel struct {
+	name       string
+	url        string
+	httpClient *http.Client
+}
+
+// webhookPayload is the JSON body posted for each message.
+type webhookPayload struct {
+	ID      string            `json:"id"`
+	Kind    string            `json:"kind"`
+	UserID  string            `json:"user_id"`
+	Email   string            `json:"email,omitempty"`
+	Subject string            `json:"subject"`
+	Body    string            `json:"body"`
+	Data    map[string]string `json:"data,omitempty"`
+}
+
+// NewWebhookChannel creates a channel named name posting to url.
+func NewWebhookChannel(name, url string, timeout time.Duration) *WebhookChannel {
+	return &WebhookChannel{
+		name: name,
+		url:  url,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// Name returns the channel name given at construction.
+func (c *WebhookChannel) Name() string { return c.name }
+
+// Send posts msg. Any non-2xx response is an error.
+func (c *WebhookChannel) Send(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(webhookPayload{
+		ID:      msg.ID,
+		Kind:    msg.Kind,
+		UserID:  msg.To.UserID,
+		Email:   msg.To.Email,
+		Subject: msg.Subject,
+		Body:    msg.Body,
+		Data:    msg.Data,
+	})
+	if err != nil {
+		return fmt.Errorf("encode %s payload: %w", c.name, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create %s request: %w", c.name, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", msg.ID)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s request failed: %w", c.name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s request failed with status: %d", c.name, resp.StatusCode)
+	}
+	return nil
+}
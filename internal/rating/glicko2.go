@@ -0,0 +1,149 @@
+package rating
+
+import "math"
+
+// Glicko-2 defaults for unrated players, on the Glicko scale.
+const (
+	DefaultRating     = 1500.0
+	DefaultDeviation  = 350.0
+	DefaultVolatility = 0.06
+	DefaultTau        = 0.5 // constrains how fast volatility changes; 0.3–1.2 is sensible
+
+	scale       = 173.7178 // Glicko to Glicko-2 scale factor
+	convergence = 0.000001 // volatility iteration tolerance
+)
+
+// Rating is a player's Glicko-2 rating on the Glicko scale (1500 ± deviation).
+type Rating struct {
+	Rating     float64
+	Deviation  float64
+	Volatility float64
+}
+
+// Default returns the rating of a player who has not played.
+func Default() Rating {
+	return Rating{Rating: DefaultRating, Deviation: DefaultDeviation, Volatility: DefaultVolatility}
+}
+
+// Result is one game of a rating period: the opponent's rating before the period and the score
+// against them (1 win, 0.5 draw, 0 loss).
+type Result struct {
+	Opponent Rating
+	Score    float64
+}
+
+// Score values of a Result.
+const (
+	Win  = 1.0
+	Draw = 0.5
+	Loss = 0.0
+)
+
+// Calculator applies Glicko-2 rating periods.
+type Calculator struct {
+	tau float64
+}
+
+// NewCalculator creates a Calculator. tau <= 0 uses DefaultTau.
+func NewCalculator(tau float64) *Calculator {
+	if tau <= 0 {
+		tau = DefaultTau
+	}
+	return &Calculator{tau: tau}
+}
+
+// Update returns r after a rating period with the given results. A period without results only
+// increases the deviation.
+func (c *Calculator) Update(r Rating, results []Result) Rating {
+	mu, phi := toGlicko2(r)
+	if len(results) == 0 {
+		return fromGlicko2(mu, math.Sqrt(phi*phi+r.Volatility*r.Volatility), r.Volatility)
+	}
+
+	var vInv, sum float64
+	for _, res := range results {
+		muJ, phiJ := toGlicko2(res.Opponent)
+		g := g(phiJ)
+		e := expected(mu, muJ, g)
+		vInv += g * g * e * (1 - e)
+		sum += g * (res.Score - e)
+	}
+	v := 1 / vInv
+	delta := v * sum
+
+	sigma := c.volatility(phi, v, delta, r.Volatility)
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	phiNew := 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	muNew := mu + phiNew*phiNew*sum
+	return fromGlicko2(muNew, phiNew, sigma)
+}
+
+// Decay returns r after periods rating periods without games, capped at the default deviation.
+func (c *Calculator) Decay(r Rating, periods float64) Rating {
+	if periods <= 0 {
+		return r
+	}
+	_, phi := toGlicko2(r)
+	phi = math.Sqrt(phi*phi + periods*r.Volatility*r.Volatility)
+	r.Deviation = math.Min(phi*scale, DefaultDeviation)
+	return r
+}
+
+// Expected returns the probability that a beats b.
+func Expected(a, b Rating) float64 {
+	muA, _ := toGlicko2(a)
+	muB, phiB := toGlicko2(b)
+	return expected(muA, muB, g(phiB))
+}
+
+// volatility finds the new volatility with the Illinois algorithm (step 5 of Glickman's paper).
+func (c *Calculator) volatility(phi, v, delta, sigma float64) float64 {
+	a := math.Log(sigma * sigma)
+	tau2 := c.tau * c.tau
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/tau2
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*c.tau) < 0 {
+			k++
+		}
+		B = a - k*c.tau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > convergence {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+	return math.Exp(A / 2)
+}
+
+func g(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+func expected(mu, muJ, g float64) float64 {
+	return 1 / (1 + math.Exp(-g*(mu-muJ)))
+}
+
+func toGlicko2(r Rating) (mu, phi float64) {
+	return (r.Rating - DefaultRating) / scale, r.Deviation / scale
+}
+
+func fromGlicko2(mu, phi, sigma float64) Rating {
+	return Rating{Rating: mu*scale + DefaultRating, Deviation: phi * scale, Volatility: sigma}
+}
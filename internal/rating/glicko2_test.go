@@ -0,0 +1,122 @@
+package rating
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculatorUpdate(t *testing.T) {
+	tests := []struct {
+		name    string
+		r       Rating
+		results []Result
+		want    Rating
+		tol     Rating
+	}{
+		{
+			// The example in Glickman's "Example of the Glicko-2 system"
+			name: "paper example",
+			r:    Rating{Rating: 1500, Deviation: 200, Volatility: 0.06},
+			results: []Result{
+				{Opponent: Rating{Rating: 1400, Deviation: 30, Volatility: 0.06}, Score: Win},
+				{Opponent: Rating{Rating: 1550, Deviation: 100, Volatility: 0.06}, Score: Loss},
+				{Opponent: Rating{Rating: 1700, Deviation: 300, Volatility: 0.06}, Score: Loss},
+			},
+			want: Rating{Rating: 1464.06, Deviation: 151.52, Volatility: 0.05999},
+			tol:  Rating{Rating: 0.01, Deviation: 0.01, Volatility: 0.00001},
+		},
+		{
+			name: "no games only grows the deviation",
+			r:    Rating{Rating: 1600, Deviation: 100, Volatility: 0.06},
+			want: Rating{Rating: 1600, Deviation: math.Sqrt(100*100 + (0.06*scale)*(0.06*scale)), Volatility: 0.06},
+			tol:  Rating{Rating: 1e-9, Deviation: 1e-9, Volatility: 1e-12},
+		},
+		{
+			name:    "draw between equals keeps the rating",
+			r:       Default(),
+			results: []Result{{Opponent: Default(), Score: Draw}},
+			want:    Rating{Rating: DefaultRating, Deviation: 290.32, Volatility: DefaultVolatility},
+			tol:     Rating{Rating: 1e-9, Deviation: 0.01, Volatility: 0.00001},
+		},
+	}
+
+	c := NewCalculator(DefaultTau)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := c.Update(tt.r, tt.results)
+			if math.Abs(got.Rating-tt.want.Rating) > tt.tol.Rating ||
+				math.Abs(got.Deviation-tt.want.Deviation) > tt.tol.Deviation ||
+				math.Abs(got.Volatility-tt.want.Volatility) > tt.tol.Volatility {
+				t.Errorf("Update = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculatorUpdateDirection(t *testing.T) {
+	c := NewCalculator(DefaultTau)
+	opponent := Default()
+	tests := []struct {
+		name  string
+		score float64
+		check func(before, after float64) bool
+	}{
+		{name: "win raises the rating", score: Win, check: func(before, after float64) bool { return after > before }},
+		{name: "loss lowers the rating", score: Loss, check: func(before, after float64) bool { return after < before }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := c.Update(Default(), []Result{{Opponent: opponent, Score: tt.score}})
+			if !tt.check(DefaultRating, got.Rating) {
+				t.Errorf("rating after score %v = %v", tt.score, got.Rating)
+			}
+			if got.Deviation >= DefaultDeviation {
+				t.Errorf("deviation after a game = %v, want below %v", got.Deviation, DefaultDeviation)
+			}
+		})
+	}
+}
+
+func TestCalculatorDecay(t *testing.T) {
+	tests := []struct {
+		name    string
+		r       Rating
+		periods float64
+		want    float64
+	}{
+		{name: "no periods", r: Rating{Rating: 1500, Deviation: 50, Volatility: 0.06}, periods: 0, want: 50},
+		{name: "negative periods", r: Rating{Rating: 1500, Deviation: 50, Volatility: 0.06}, periods: -3, want: 50},
+		{name: "one period", r: Rating{Rating: 1500, Deviation: 50, Volatility: 0.06}, periods: 1, want: math.Sqrt(50*50 + (0.06*scale)*(0.06*scale))},
+		{name: "capped at the default deviation", r: Rating{Rating: 1500, Deviation: 300, Volatility: 0.06}, periods: 1000, want: DefaultDeviation},
+	}
+
+	c := NewCalculator(0)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := c.Decay(tt.r, tt.periods)
+			if math.Abs(got.Deviation-tt.want) > 1e-9 || got.Rating != tt.r.Rating || got.Volatility != tt.r.Volatility {
+				t.Errorf("Decay = %+v, want deviation %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpected(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Rating
+		want float64
+		tol  float64
+	}{
+		{name: "equal ratings", a: Default(), b: Default(), want: 0.5, tol: 1e-12},
+		{name: "paper opponent 1", a: Rating{Rating: 1500, Deviation: 200}, b: Rating{Rating: 1400, Deviation: 30}, want: 0.639, tol: 0.001},
+		{name: "paper opponent 3", a: Rating{Rating: 1500, Deviation: 200}, b: Rating{Rating: 1700, Deviation: 300}, want: 0.303, tol: 0.001},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Expected(tt.a, tt.b); math.Abs(got-tt.want) > tt.tol {
+				t.Errorf("Expected = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package recommend
+
+// recentWindow is how many recent sessions decide the suggested difficulty.
+const recentWindow = 3
+
+// difficultyFor suggests a difficulty from the last few sessions: step up after consistently strong,
+// accurate play; step down while struggling. New players start at easy.
+func difficultyFor(d DomainStats) string {
+	n := len(d.RecentScores)
+	if n == 0 {
+		return DifficultyEasy
+	}
+	if n > recentWindow {
+		n = recentWindow
+	}
+	var score, acc float64
+	for i := len(d.RecentScores) - n; i < len(d.RecentScores); i++ {
+		score += d.RecentScores[i]
+		acc += d.RecentAcc[i]
+	}
+	score /= float64(n)
+	acc /= float64(n)
+
+	switch {
+	case n == recentWindow && score >= 75 && acc >= 0.85:
+		return DifficultyHard
+	case score < 40:
+		return DifficultyEasy
+	default:
+		return DifficultyMedium
+	}
+}
+
+// daysSince returns the whole days between the domain's last session and now (-1 if never played).
+func daysSince(d DomainStats, in Input) int {
+	if d.LastPlayedAt == nil {
+		return -1
+	}
+	days := int(in.Now.Sub(*d.LastPlayedAt).Hours() / 24)
+	if days < 0 {
+		return 0
+	}
+	return days
+}
@@ -0,0 +1,48 @@
+package recommend
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Engine selects a recommender by name and builds plans.
+type Engine struct {
+	recommenders map[string]Recommender
+}
+
+// NewEngine builds an Engine with all recommenders registered.
+func NewEngine() *Engine {
+	return &Engine{
+		recommenders: map[string]Recommender{
+			RecommenderWeakestFirst: NewWeakestFirstRecommender(),
+			RecommenderSpaced:       NewSpacedRecommender(),
+		},
+	}
+}
+
+// Has returns true if a recommender with the given name is registered.
+func (e *Engine) Has(name string) bool {
+	_, ok := e.recommenders[name]
+	return ok
+}
+
+// Recommend returns up to n suggestions from the named recommender, highest priority first.
+// Returns error if the recommender is unknown.
+func (e *Engine) Recommend(name string, in Input, n int) ([]Suggestion, error) {
+	r, ok := e.recommenders[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown recommender: %s", name)
+	}
+	return r.Recommend(in, n), nil
+}
+
+// top sorts suggestions by priority (stable, so input order breaks ties) and keeps the first n.
+func top(suggestions []Suggestion, n int) []Suggestion {
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Priority > suggestions[j].Priority
+	})
+	if n >= 0 && len(suggestions) > n {
+		suggestions = suggestions[:n]
+	}
+	return suggestions
+}
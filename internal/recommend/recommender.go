@@ -0,0 +1,47 @@
+package recommend
+
+import "time"
+
+// DomainStats is a user's history in one game type, built from leaderboard-eligible sessions.
+type DomainStats struct {
+	GameType     string
+	Label        string
+	AvgScore     float64
+	HighScore    float64
+	Sessions     int
+	LastPlayedAt *time.Time // nil when never played
+	RecentScores []float64  // most recent sessions, oldest first
+	RecentAcc    []float64  // accuracy (0–1) of the same sessions
+}
+
+// Input is everything a Recommender may use.
+type Input struct {
+	Domains []DomainStats
+	Now     time.Time
+}
+
+// Suggestion is one game in a daily plan.
+type Suggestion struct {
+	GameType   string
+	Label      string
+	Difficulty string
+	Reason     string
+	Priority   float64 // higher is suggested first
+}
+
+// Recommender ranks game types for a user's daily plan.
+type Recommender interface {
+	Recommend(in Input, n int) []Suggestion
+}
+
+const (
+	RecommenderWeakestFirst = "weakest_first" // lowest avg_score first, nudged by time since last played
+	RecommenderSpaced       = "spaced"        // longest unplayed first (spaced practice), weakest breaks ties
+)
+
+// Difficulty levels suggested alongside each game.
+const (
+	DifficultyEasy   = "easy"
+	DifficultyMedium = "medium"
+	DifficultyHard   = "hard"
+)
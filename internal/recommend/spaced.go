@@ -0,0 +1,33 @@
+package recommend
+
+import "fmt"
+
+// SpacedRecommender prioritises the domains played longest ago, so practice is spread over all domains.
+// Among domains played equally recently, the weaker one comes first.
+type SpacedRecommender struct{}
+
+// NewSpacedRecommender creates the spaced recommender.
+func NewSpacedRecommender() *SpacedRecommender {
+	return &SpacedRecommender{}
+}
+
+// Recommend implements Recommender.
+func (r *SpacedRecommender) Recommend(in Input, n int) []Suggestion {
+	out := make([]Suggestion, 0, len(in.Domains))
+	for _, d := range in.Domains {
+		s := Suggestion{GameType: d.GameType, Label: d.Label, Difficulty: difficultyFor(d)}
+		switch days := daysSince(d, in); {
+		case days < 0:
+			s.Priority = 1000
+			s.Reason = fmt.Sprintf("You haven't tried %s yet", d.Label)
+		case days == 0:
+			s.Priority = (100 - d.AvgScore) / 100
+			s.Reason = fmt.Sprintf("Keep your %s practice going (average %.0f)", d.Label, d.AvgScore)
+		default:
+			s.Priority = float64(days) + (100-d.AvgScore)/100
+			s.Reason = fmt.Sprintf("Last played %d days ago", days)
+		}
+		out = append(out, s)
+	}
+	return top(out, n)
+}
@@ -0,0 +1,43 @@
+package recommend
+
+import (
+	"fmt"
+	"math"
+)
+
+// staleAfterDays is when a domain's staleness stops growing.
+const staleAfterDays = 7
+
+// WeakestFirstRecommender prioritises the domains with the lowest avg_score, adding weight to domains
+// that have not been played for a while. Unplayed domains come first.
+type WeakestFirstRecommender struct{}
+
+// NewWeakestFirstRecommender creates the weakest_first recommender.
+func NewWeakestFirstRecommender() *WeakestFirstRecommender {
+	return &WeakestFirstRecommender{}
+}
+
+// Recommend implements Recommender.
+func (r *WeakestFirstRecommender) Recommend(in Input, n int) []Suggestion {
+	out := make([]Suggestion, 0, len(in.Domains))
+	for _, d := range in.Domains {
+		s := Suggestion{GameType: d.GameType, Label: d.Label, Difficulty: difficultyFor(d)}
+		days := daysSince(d, in)
+		if days < 0 {
+			s.Priority = 2
+			s.Reason = fmt.Sprintf("You haven't tried %s yet", d.Label)
+			out = append(out, s)
+			continue
+		}
+		weakness := (100 - d.AvgScore) / 100
+		staleness := math.Min(float64(days)/staleAfterDays, 1)
+		s.Priority = 0.7*weakness + 0.3*staleness
+		if staleness >= 1 && weakness < 0.5 {
+			s.Reason = fmt.Sprintf("Not played for %d days", days)
+		} else {
+			s.Reason = fmt.Sprintf("One of your weaker domains (average %.0f)", d.AvgScore)
+		}
+		out = append(out, s)
+	}
+	return top(out, n)
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"brainbash_backend/internal/model/entity"
+)
+
+const achievementCollection = "achievements"
+
+// AchievementRepository handles MongoDB operations for the achievements collection (one document per unlock).
+type AchievementRepository struct {
+	collection *mongo.Collection
+}
+
+// NewAchievementRepository creates a new AchievementRepository.
+func NewAchievementRepository(db *mongo.Database) *AchievementRepository {
+	return &AchievementRepository{
+		collection: db.Collection(achievementCollection),
+	}
+}
+
+// EnsureIndexes creates the index used to list a user's unlocks.
+func (r *AchievementRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "unlocked_at", Value: 1}},
+	})
+	if err != nil {
+		return fmt.Errorf("create achievement index: %w", err)
+	}
+	return nil
+}
+
+// Insert stores an unlock. Returns inserted=false when the user already unlocked the achievement.
+func (r *AchievementRepository) Insert(ctx context.Context, a *entity.Achievement) (inserted bool, err error) {
+	if _, err := r.collection.InsertOne(ctx, a); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("insert achievement: %w", err)
+	}
+	return true, nil
+}
+
+// FindByUserID returns the user's unlocks, oldest first.
+func (r *AchievementRepository) FindByUserID(ctx context.Context, userID string) ([]*entity.Achievement, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "unlocked_at", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find achievements: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	out := []*entity.Achievement{}
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, fmt.Errorf("decode achievements: %w", err)
+	}
+	return out, nil
+}
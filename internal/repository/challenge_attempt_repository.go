@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"brainbash_backend/internal/model/entity"
+)
+
+const challengeAttemptCollection = "challenge_attempts"
+
+// ChallengeAttemptRepository handles MongoDB operations for the challenge_attempts collection.
+type ChallengeAttemptRepository struct {
+	collection *mongo.Collection
+}
+
+// NewChallengeAttemptRepository creates a new ChallengeAttemptRepository.
+func NewChallengeAttemptRepository(db *mongo.Database) *ChallengeAttemptRepository {
+	return &ChallengeAttemptRepository{
+		collection: db.Collection(challengeAttemptCollection),
+	}
+}
+
+// EnsureIndexes creates the leaderboard index (best score first, earliest submission breaking ties)
+// and the session_id index used when a flagged attempt is reviewed.
+func (r *ChallengeAttemptRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "challenge_id", Value: 1}, {Key: "tenant_id", Value: 1}, {Key: "flagged", Value: 1}, {Key: "session_score.score", Value: -1}, {Key: "submitted_at", Value: 1}}},
+		{Keys: bson.D{{Key: "session_id", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("create challenge attempt indexes: %w", err)
+	}
+	return nil
+}
+
+// Insert stores the attempt, stamped with the tenant of ctx. Returns inserted=false when the user already
+// has an attempt at the challenge.
+func (r *ChallengeAttemptRepository) Insert(ctx context.Context, attempt *entity.ChallengeAttempt) (inserted bool, err error) {
+	attempt.TenantID = tenantID(ctx)
+	if _, err := r.collection.InsertOne(ctx, attempt); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("insert challenge attempt: %w", err)
+	}
+	return true, nil
+}
+
+// FindByID returns the attempt, or nil if not found.
+func (r *ChallengeAttemptRepository) FindByID(ctx context.Context, id string) (*entity.ChallengeAttempt, error) {
+	var attempt entity.ChallengeAttempt
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&attempt)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find challenge attempt: %w", err)
+	}
+	return &attempt, nil
+}
+
+// FindTop returns the limit best unflagged attempts of the tenant at the challenge.
+func (r *ChallengeAttemptRepository) FindTop(ctx context.Context, challengeID string, limit int64) ([]*entity.ChallengeAttempt, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "session_score.score", Value: -1}, {Key: "submitted_at", Value: 1}}).
+		SetLimit(limit)
+	cursor, err := r.collection.Find(ctx, scoped(ctx, bson.M{"challenge_id": challengeID, "flagged": false}), opts)
+	if err != nil {
+		return nil, fmt.Errorf("find challenge attempts: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	out := []*entity.ChallengeAttempt{}
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, fmt.Errorf("decode challenge attempts: %w", err)
+	}
+	return out, nil
+}
+
+// Rank returns the 1-based position of an unflagged attempt on its tenant's leaderboard.
+func (r *ChallengeAttemptRepository) Rank(ctx context.Context, attempt *entity.ChallengeAttempt) (int64, error) {
+	filter := bson.M{
+		"challenge_id": attempt.ChallengeID,
+		"flagged":      false,
+		"$or": bson.A{
+			bson.M{"session_score.score": bson.M{"$gt": attempt.SessionScore.Score}},
+			bson.M{"session_score.score": attempt.SessionScore.Score, "submitted_at": bson.M{"$lt": attempt.SubmittedAt}},
+		},
+	}
+	ahead, err := r.collection.CountDocuments(ctx, scoped(ctx, filter))
+	if err != nil {
+		return 0, fmt.Errorf("rank challenge attempt: %w", err)
+	}
+	return ahead + 1, nil
+}
+
+// SetFlaggedBySession updates the flagged state of the user's attempt that stored the session, if any.
+func (r *ChallengeAttemptRepository) SetFlaggedBySession(ctx context.Context, userID, sessionID string, flagged bool) error {
+	filter := bson.M{"user_id": userID, "session_id": sessionID}
+	_, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"flagged": flagged}})
+	if err != nil {
+		return fmt.Errorf("update challenge attempt: %w", err)
+	}
+	return nil
+}
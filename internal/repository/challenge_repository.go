@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"brainbash_backend/internal/model/entity"
+)
+
+const challengeCollection = "challenges"
+
+// ChallengeRepository handles MongoDB operations for the challenges collection (one document per day).
+type ChallengeRepository struct {
+	collection *mongo.Collection
+}
+
+// NewChallengeRepository creates a new ChallengeRepository.
+func NewChallengeRepository(db *mongo.Database) *ChallengeRepository {
+	return &ChallengeRepository{
+		collection: db.Collection(challengeCollection),
+	}
+}
+
+// GetOrCreate inserts challenge unless a challenge with the same _id exists, and returns the stored one.
+// Concurrent callers with different candidates all get the first one written.
+func (r *ChallengeRepository) GetOrCreate(ctx context.Context, challenge *entity.Challenge) (*entity.Challenge, error) {
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	update := bson.M{"$setOnInsert": challenge}
+	var stored entity.Challenge
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": challenge.ID}, update, opts).Decode(&stored)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			// Lost an upsert race; the winner's document is there now
+			return r.FindByID(ctx, challenge.ID)
+		}
+		return nil, fmt.Errorf("get or create challenge: %w", err)
+	}
+	return &stored, nil
+}
+
+// FindByID returns the challenge of the given date, or nil if not found.
+func (r *ChallengeRepository) FindByID(ctx context.Context, id string) (*entity.Challenge, error) {
+	var challenge entity.Challenge
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&challenge)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find challenge: %w", err)
+	}
+	return &challenge, nil
+}
+
+// SetTenantGameType stores the game type the tenant plays in the challenge unless one was stored already.
+func (r *ChallengeRepository) SetTenantGameType(ctx context.Context, id, tenantID, gameType string) error {
+	field := "tenant_game_types." + tenantID
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id, field: bson.M{"$exists": false}}, bson.M{"$set": bson.M{field: gameType}})
+	if err != nil {
+		return fmt.Errorf("set challenge game type: %w", err)
+	}
+	return nil
+}
+
+// FindBefore returns up to limit challenges with _id (date) before the given one, newest first.
+func (r *ChallengeRepository) FindBefore(ctx context.Context, before string, limit int64) ([]*entity.Challenge, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(limit)
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$lt": before}}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find challenges: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	out := []*entity.Challenge{}
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, fmt.Errorf("decode challenges: %w", err)
+	}
+	return out, nil
+}
+
+// FindUnrated returns up to limit challenges before the given date whose placements were not rated yet,
+// oldest first.
+func (r *ChallengeRepository) FindUnrated(ctx context.Context, before string, limit int64) ([]*entity.Challenge, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(limit)
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$lt": before}, "rated_at": bson.M{"$exists": false}}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find unrated challenges: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	out := []*entity.Challenge{}
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, fmt.Errorf("decode challenges: %w", err)
+	}
+	return out, nil
+}
+
+// MarkRated records that the challenge's placements were rated. Returns false if it already was, so only
+// one instance rates each challenge.
+func (r *ChallengeRepository) MarkRated(ctx context.Context, id string, at time.Time) (bool, error) {
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": id, "rated_at": bson.M{"$exists": false}}, bson.M{"$set": bson.M{"rated_at": at}})
+	if err != nil {
+		return false, fmt.Errorf("mark challenge rated: %w", err)
+	}
+	return res.ModifiedCount == 1, nil
+}
@@ -14,7 +14,8 @@ import (
 
 const dashboardCollection = "dashboard"
 
-// DashboardRepository handles MongoDB operations for the dashboard (leaderboard) collection.
+// DashboardRepository handles MongoDB operations for the dashboard (leaderboard) collection. Each tenant
+// has its own documents: ids are suffixed with the tenant, except for the default tenant.
 type DashboardRepository struct {
 	collection *mongo.Collection
 }
@@ -26,10 +27,10 @@ func NewDashboardRepository(db *mongo.Database) *DashboardRepository {
 	}
 }
 
-// FindByID returns the dashboard document (e.g. leaderboard), or nil if not found.
+// FindByID returns the tenant's dashboard document (e.g. leaderboard), or nil if not found.
 func (r *DashboardRepository) FindByID(ctx context.Context, id string) (*entity.Dashboard, error) {
 	var doc entity.Dashboard
-	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&doc)
+	err := r.collection.FindOne(ctx, scoped(ctx, bson.M{"_id": dashboardDocID(ctx, id)})).Decode(&doc)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, nil
@@ -39,10 +40,11 @@ func (r *DashboardRepository) FindByID(ctx context.Context, id string) (*entity.
 	return &doc, nil
 }
 
-// Upsert replaces the dashboard document (full document replace).
+// Upsert replaces the tenant's dashboard document (full document replace).
 func (r *DashboardRepository) Upsert(ctx context.Context, d *entity.Dashboard) error {
-	d.ID = entity.DashboardDocID
-	filter := bson.M{"_id": d.ID}
+	d.ID = dashboardDocID(ctx, entity.DashboardDocID)
+	d.TenantID = tenantID(ctx)
+	filter := scoped(ctx, bson.M{"_id": d.ID})
 	opts := options.Replace().SetUpsert(true)
 	_, err := r.collection.ReplaceOne(ctx, filter, d, opts)
 	if err != nil {
@@ -70,6 +72,14 @@ func (r *DashboardRepository) DeleteEntriesInDateRange(ctx context.Context, star
 	return r.Upsert(ctx, d)
 }
 
+// dashboardDocID returns the _id of the document id of the tenant in ctx.
+func dashboardDocID(ctx context.Context, id string) string {
+	if t := tenantID(ctx); t != "" {
+		return id + ":" + t
+	}
+	return id
+}
+
 func filterEntriesByDateRange(entries []entity.DashboardEntry, start, end time.Time) []entity.DashboardEntry {
 	out := make([]entity.DashboardEntry, 0, len(entries))
 	for _, e := range entries {
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"brainbash_backend/internal/model/entity"
+)
+
+const digestCollection = "digests"
+
+// DigestRepository handles MongoDB operations for the digests collection (one document per user and week).
+type DigestRepository struct {
+	collection *mongo.Collection
+}
+
+// NewDigestRepository creates a new DigestRepository.
+func NewDigestRepository(db *mongo.Database) *DigestRepository {
+	return &DigestRepository{
+		collection: db.Collection(digestCollection),
+	}
+}
+
+// EnsureIndexes creates the index used to list a user's digests newest first.
+func (r *DigestRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "week_start", Value: -1}},
+	})
+	if err != nil {
+		return fmt.Errorf("create digest index: %w", err)
+	}
+	return nil
+}
+
+// Insert stores a digest. Returns inserted=false when the user's digest for that week already exists.
+func (r *DigestRepository) Insert(ctx context.Context, digest *entity.Digest) (inserted bool, err error) {
+	if _, err := r.collection.InsertOne(ctx, digest); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("insert digest: %w", err)
+	}
+	return true, nil
+}
+
+// FindByID returns the digest, or nil if not found.
+func (r *DigestRepository) FindByID(ctx context.Context, id string) (*entity.Digest, error) {
+	var digest entity.Digest
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&digest)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find digest: %w", err)
+	}
+	return &digest, nil
+}
+
+// FindByUserID returns up to limit of the user's digests, newest week first.
+func (r *DigestRepository) FindByUserID(ctx context.Context, userID string, limit int64) ([]*entity.Digest, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "week_start", Value: -1}}).
+		SetLimit(limit)
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find digests: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	out := []*entity.Digest{}
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, fmt.Errorf("decode digests: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"brainbash_backend/internal/model/entity"
+)
+
+const (
+	duelCollection       = "duels"
+	duelRecordCollection = "duel_records"
+)
+
+// DuelRepository handles MongoDB operations for the duels and duel_records collections.
+type DuelRepository struct {
+	duels   *mongo.Collection
+	records *mongo.Collection
+}
+
+// NewDuelRepository creates a new DuelRepository.
+func NewDuelRepository(db *mongo.Database) *DuelRepository {
+	return &DuelRepository{
+		duels:   db.Collection(duelCollection),
+		records: db.Collection(duelRecordCollection),
+	}
+}
+
+// EnsureIndexes creates the indexes used to list a user's duels from either side and to find expired duels.
+func (r *DuelRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.duels.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "challenger_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "opponent_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "expires_at", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("create duel indexes: %w", err)
+	}
+	return nil
+}
+
+// Insert stores a new duel.
+func (r *DuelRepository) Insert(ctx context.Context, duel *entity.Duel) error {
+	if _, err := r.duels.InsertOne(ctx, duel); err != nil {
+		return fmt.Errorf("insert duel: %w", err)
+	}
+	return nil
+}
+
+// FindByID returns the duel, or nil if not found.
+func (r *DuelRepository) FindByID(ctx context.Context, id string) (*entity.Duel, error) {
+	var duel entity.Duel
+	err := r.duels.FindOne(ctx, bson.M{"_id": id}).Decode(&duel)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find duel: %w", err)
+	}
+	return &duel, nil
+}
+
+// FindByUserID returns up to limit duels the user is in, newest first, optionally restricted to one status.
+func (r *DuelRepository) FindByUserID(ctx context.Context, userID, status string, limit int64) ([]*entity.Duel, error) {
+	filter := bson.M{"$or": bson.A{bson.M{"challenger_id": userID}, bson.M{"opponent_id": userID}}}
+	if status != "" {
+		filter["status"] = status
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+	cursor, err := r.duels.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find duels: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	out := []*entity.Duel{}
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, fmt.Errorf("decode duels: %w", err)
+	}
+	return out, nil
+}
+
+// FindExpired returns up to limit pending or active duels whose expires_at is at or before cutoff.
+func (r *DuelRepository) FindExpired(ctx context.Context, cutoff time.Time, limit int64) ([]*entity.Duel, error) {
+	filter := bson.M{
+		"status":     bson.M{"$in": bson.A{entity.DuelPending, entity.DuelActive}},
+		"expires_at": bson.M{"$lte": cutoff},
+	}
+	cursor, err := r.duels.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "expires_at", Value: 1}}).SetLimit(limit))
+	if err != nil {
+		return nil, fmt.Errorf("find expired duels: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	out := []*entity.Duel{}
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, fmt.Errorf("decode duels: %w", err)
+	}
+	return out, nil
+}
+
+// Transition applies set to the duel if its status is one of from. Returns false when the duel is
+// missing or already moved on, so concurrent transitions apply at most once.
+func (r *DuelRepository) Transition(ctx context.Context, id string, from []string, set bson.M) (bool, error) {
+	res, err := r.duels.UpdateOne(ctx, bson.M{"_id": id, "status": bson.M{"$in": from}}, bson.M{"$set": set})
+	if err != nil {
+		return false, fmt.Errorf("update duel: %w", err)
+	}
+	return res.ModifiedCount > 0, nil
+}
+
+// SetEntry stores side's entry if the duel's status is one of statuses and that side has not submitted yet.
+// Returns the updated duel, or nil when the entry was not stored.
+func (r *DuelRepository) SetEntry(ctx context.Context, id, side string, statuses []string, entry *entity.DuelEntry) (*entity.Duel, error) {
+	filter := bson.M{"_id": id, "status": bson.M{"$in": statuses}, side: bson.M{"$exists": false}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var duel entity.Duel
+	err := r.duels.FindOneAndUpdate(ctx, filter, bson.M{"$set": bson.M{side: entry}}, opts).Decode(&duel)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("set duel entry: %w", err)
+	}
+	return &duel, nil
+}
+
+// IncRecord adds one to the user's wins, losses or draws.
+func (r *DuelRepository) IncRecord(ctx context.Context, userID, field string, at time.Time) error {
+	_, err := r.records.UpdateOne(ctx,
+		bson.M{"_id": userID},
+		bson.M{"$inc": bson.M{field: 1}, "$set": bson.M{"updated_at": at}},
+		options.UpdateOne().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("update duel record: %w", err)
+	}
+	return nil
+}
+
+// FindRecord returns the user's duel record, or nil when they have not finished a duel.
+func (r *DuelRepository) FindRecord(ctx context.Context, userID string) (*entity.DuelRecord, error) {
+	var record entity.DuelRecord
+	err := r.records.FindOne(ctx, bson.M{"_id": userID}).Decode(&record)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find duel record: %w", err)
+	}
+	return &record, nil
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/tenant"
+)
+
+const flagCollection = "session_flags"
+
+// FlagRepository handles MongoDB operations for the session_flags (review queue) collection.
+type FlagRepository struct {
+	collection *mongo.Collection
+}
+
+// NewFlagRepository creates a new FlagRepository.
+func NewFlagRepository(db *mongo.Database) *FlagRepository {
+	return &FlagRepository{
+		collection: db.Collection(flagCollection),
+	}
+}
+
+// EnsureIndexes creates the unique index on (user_id, session_id). Flags stored when _id was the
+// session_id get that id as session_id first, so their ids stay valid.
+func (r *FlagRepository) EnsureIndexes(ctx context.Context) error {
+	if err := copyIDToSessionID(ctx, r.collection); err != nil {
+		return fmt.Errorf("migrate session flag ids: %w", err)
+	}
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "session_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("create session flag index: %w", err)
+	}
+	return nil
+}
+
+// Upsert stores the flag for the user's session, keeping the id of a flag stored before. flag.ID is set
+// to the stored id. Within a tenant the flag is stamped with it, so its review runs in that tenant.
+func (r *FlagRepository) Upsert(ctx context.Context, flag *entity.SessionFlag) error {
+	if tenant.FromContext(ctx) != nil {
+		flag.TenantID = tenantID(ctx)
+	}
+	filter := bson.M{"user_id": flag.UserID, "session_id": flag.SessionID}
+	set := bson.M{
+		"game_type":     flag.GameType,
+		"flags":         flag.Flags,
+		"status":        flag.Status,
+		"session_score": flag.SessionScore,
+		"timestamp":     flag.Timestamp,
+	}
+	if flag.TenantID != "" {
+		set["tenant_id"] = flag.TenantID
+	}
+	update := bson.M{
+		"$set":         set,
+		"$setOnInsert": bson.M{"_id": bson.NewObjectID().Hex()},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After).SetProjection(bson.M{"_id": 1})
+	var stored entity.SessionFlag
+	if err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&stored); err != nil {
+		return fmt.Errorf("upsert session flag: %w", err)
+	}
+	flag.ID = stored.ID
+	return nil
+}
+
+// FindByID returns the flag, or nil if not found.
+func (r *FlagRepository) FindByID(ctx context.Context, id string) (*entity.SessionFlag, error) {
+	var flag entity.SessionFlag
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&flag)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find session flag: %w", err)
+	}
+	return &flag, nil
+}
+
+// FindByStatus returns up to limit flags with the given status, oldest first.
+func (r *FlagRepository) FindByStatus(ctx context.Context, status string, limit int64) ([]*entity.SessionFlag, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}}).SetLimit(limit)
+	cursor, err := r.collection.Find(ctx, bson.M{"status": status}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find session flags: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	out := []*entity.SessionFlag{}
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, fmt.Errorf("decode session flags: %w", err)
+	}
+	return out, nil
+}
+
+// SetStatus records the review decision for a session flag.
+func (r *FlagRepository) SetStatus(ctx context.Context, id, status, reviewedBy string, reviewedAt time.Time) error {
+	update := bson.M{"$set": bson.M{
+		"status":      status,
+		"reviewed_by": reviewedBy,
+		"reviewed_at": reviewedAt,
+	}}
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return fmt.Errorf("update session flag: %w", err)
+	}
+	return nil
+}
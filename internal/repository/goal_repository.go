@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"brainbash_backend/internal/model/entity"
+)
+
+const goalCollection = "goals"
+
+// GoalRepository handles MongoDB operations for the goals collection (one document per goal).
+type GoalRepository struct {
+	collection *mongo.Collection
+}
+
+// NewGoalRepository creates a new GoalRepository.
+func NewGoalRepository(db *mongo.Database) *GoalRepository {
+	return &GoalRepository{
+		collection: db.Collection(goalCollection),
+	}
+}
+
+// EnsureIndexes creates the index used to list a user's goals and the unique index on a user's goal
+// slots. Goals created before slots existed have none.
+func (r *GoalRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "slot", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"slot": bson.M{"$exists": true}}),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("create goal indexes: %w", err)
+	}
+	return nil
+}
+
+// Insert stores a new goal. Returns false when the user already has a goal in its slot.
+func (r *GoalRepository) Insert(ctx context.Context, goal *entity.Goal) (inserted bool, err error) {
+	if _, err := r.collection.InsertOne(ctx, goal); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("insert goal: %w", err)
+	}
+	return true, nil
+}
+
+// FindByID returns the user's goal, or nil if the user has no goal with that id.
+func (r *GoalRepository) FindByID(ctx context.Context, userID, goalID string) (*entity.Goal, error) {
+	var goal entity.Goal
+	err := r.collection.FindOne(ctx, bson.M{"_id": goalID, "user_id": userID}).Decode(&goal)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find goal: %w", err)
+	}
+	return &goal, nil
+}
+
+// FindByUserID returns the user's goals, oldest first. activeOnly skips paused goals.
+func (r *GoalRepository) FindByUserID(ctx context.Context, userID string, activeOnly bool) ([]*entity.Goal, error) {
+	filter := bson.M{"user_id": userID}
+	if activeOnly {
+		filter["active"] = true
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find goals: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	out := []*entity.Goal{}
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, fmt.Errorf("decode goals: %w", err)
+	}
+	return out, nil
+}
+
+// Update replaces the user's goal. Returns false when it no longer exists.
+func (r *GoalRepository) Update(ctx context.Context, goal *entity.Goal) (bool, error) {
+	res, err := r.collection.ReplaceOne(ctx, bson.M{"_id": goal.ID, "user_id": goal.UserID}, goal)
+	if err != nil {
+		return false, fmt.Errorf("update goal: %w", err)
+	}
+	return res.MatchedCount > 0, nil
+}
+
+// AddHitDay records that a daily goal was met on day. Returns false when the day was already recorded.
+func (r *GoalRepository) AddHitDay(ctx context.Context, goalID, day string) (bool, error) {
+	res, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": goalID, "hit_days": bson.M{"$ne": day}},
+		bson.M{"$push": bson.M{"hit_days": day}})
+	if err != nil {
+		return false, fmt.Errorf("add goal hit day: %w", err)
+	}
+	return res.ModifiedCount > 0, nil
+}
+
+// SetAchieved records when a one-off goal was met. Returns false when it was already achieved.
+func (r *GoalRepository) SetAchieved(ctx context.Context, goalID string, at time.Time) (bool, error) {
+	res, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": goalID, "achieved_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"achieved_at": at}})
+	if err != nil {
+		return false, fmt.Errorf("set goal achieved: %w", err)
+	}
+	return res.ModifiedCount > 0, nil
+}
+
+// Delete removes the user's goal. Returns false when it did not exist.
+func (r *GoalRepository) Delete(ctx context.Context, userID, goalID string) (bool, error) {
+	res, err := r.collection.DeleteOne(ctx, bson.M{"_id": goalID, "user_id": userID})
+	if err != nil {
+		return false, fmt.Errorf("delete goal: %w", err)
+	}
+	return res.DeletedCount > 0, nil
+}
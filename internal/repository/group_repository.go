@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"brainbash_backend/internal/model/entity"
+)
+
+const (
+	groupCollection       = "groups"
+	groupMemberCollection = "group_members"
+)
+
+// GroupRepository handles MongoDB operations for the groups and group_members collections. Queries are
+// scoped to the tenant carried by ctx.
+type GroupRepository struct {
+	groups  *mongo.Collection
+	members *mongo.Collection
+}
+
+// NewGroupRepository creates a new GroupRepository.
+func NewGroupRepository(db *mongo.Database) *GroupRepository {
+	return &GroupRepository{
+		groups:  db.Collection(groupCollection),
+		members: db.Collection(groupMemberCollection),
+	}
+}
+
+// EnsureIndexes creates the unique join code index and the indexes used to list a group's members and a
+// user's groups.
+func (r *GroupRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.groups.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "join_code", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("create group index: %w", err)
+	}
+	_, err = r.members.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "group_id", Value: 1}, {Key: "joined_at", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "joined_at", Value: -1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("create group member indexes: %w", err)
+	}
+	return nil
+}
+
+// Insert stores a new group of the tenant in ctx. Returns false when its join code is taken.
+func (r *GroupRepository) Insert(ctx context.Context, g *entity.Group) (inserted bool, err error) {
+	g.TenantID = tenantID(ctx)
+	if _, err := r.groups.InsertOne(ctx, g); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("insert group: %w", err)
+	}
+	return true, nil
+}
+
+// FindByID returns the group, or nil if not found.
+func (r *GroupRepository) FindByID(ctx context.Context, id string) (*entity.Group, error) {
+	return r.findOne(ctx, scoped(ctx, bson.M{"_id": id}))
+}
+
+// FindByJoinCode returns the group with the join code, or nil if not found.
+func (r *GroupRepository) FindByJoinCode(ctx context.Context, code string) (*entity.Group, error) {
+	return r.findOne(ctx, scoped(ctx, bson.M{"join_code": code}))
+}
+
+// FindByIDs returns the groups with the given ids.
+func (r *GroupRepository) FindByIDs(ctx context.Context, ids []string) ([]*entity.Group, error) {
+	cursor, err := r.groups.Find(ctx, scoped(ctx, bson.M{"_id": bson.M{"$in": ids}}))
+	if err != nil {
+		return nil, fmt.Errorf("find groups: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	out := []*entity.Group{}
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, fmt.Errorf("decode groups: %w", err)
+	}
+	return out, nil
+}
+
+// Update applies set to the group. Returns false when the new join code is taken.
+func (r *GroupRepository) Update(ctx context.Context, id string, set bson.M) (updated bool, err error) {
+	if _, err := r.groups.UpdateOne(ctx, scoped(ctx, bson.M{"_id": id}), bson.M{"$set": set}); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("update group: %w", err)
+	}
+	return true, nil
+}
+
+// Delete removes the group and all its memberships.
+func (r *GroupRepository) Delete(ctx context.Context, id string) error {
+	if _, err := r.members.DeleteMany(ctx, scoped(ctx, bson.M{"group_id": id})); err != nil {
+		return fmt.Errorf("delete group members: %w", err)
+	}
+	if _, err := r.groups.DeleteOne(ctx, scoped(ctx, bson.M{"_id": id})); err != nil {
+		return fmt.Errorf("delete group: %w", err)
+	}
+	return nil
+}
+
+// InsertMember adds a membership in the tenant in ctx. Returns false if the user is already a member.
+func (r *GroupRepository) InsertMember(ctx context.Context, m *entity.GroupMember) (inserted bool, err error) {
+	m.TenantID = tenantID(ctx)
+	if _, err := r.members.InsertOne(ctx, m); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("insert group member: %w", err)
+	}
+	return true, nil
+}
+
+// FindMember returns the user's membership in the group, or nil if they are not a member.
+func (r *GroupRepository) FindMember(ctx context.Context, groupID, userID string) (*entity.GroupMember, error) {
+	var m entity.GroupMember
+	err := r.members.FindOne(ctx, scoped(ctx, bson.M{"_id": entity.GroupMemberID(groupID, userID)})).Decode(&m)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find group member: %w", err)
+	}
+	return &m, nil
+}
+
+// FindMembers returns the group's members in the order they joined.
+func (r *GroupRepository) FindMembers(ctx context.Context, groupID string) ([]*entity.GroupMember, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "joined_at", Value: 1}})
+	return r.findMembers(ctx, scoped(ctx, bson.M{"group_id": groupID}), opts)
+}
+
+// FindMemberships returns the user's memberships, most recently joined first.
+func (r *GroupRepository) FindMemberships(ctx context.Context, userID string) ([]*entity.GroupMember, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "joined_at", Value: -1}})
+	return r.findMembers(ctx, scoped(ctx, bson.M{"user_id": userID}), opts)
+}
+
+// CountMembers returns how many members the group has.
+func (r *GroupRepository) CountMembers(ctx context.Context, groupID string) (int64, error) {
+	n, err := r.members.CountDocuments(ctx, scoped(ctx, bson.M{"group_id": groupID}))
+	if err != nil {
+		return 0, fmt.Errorf("count group members: %w", err)
+	}
+	return n, nil
+}
+
+// CountMemberships returns how many groups the user is in.
+func (r *GroupRepository) CountMemberships(ctx context.Context, userID string) (int64, error) {
+	n, err := r.members.CountDocuments(ctx, scoped(ctx, bson.M{"user_id": userID}))
+	if err != nil {
+		return 0, fmt.Errorf("count group memberships: %w", err)
+	}
+	return n, nil
+}
+
+// SetRole changes a member's role. Returns false if they are not a member.
+func (r *GroupRepository) SetRole(ctx context.Context, groupID, userID, role string) (bool, error) {
+	res, err := r.members.UpdateOne(ctx, scoped(ctx, bson.M{"_id": entity.GroupMemberID(groupID, userID)}), bson.M{"$set": bson.M{"role": role}})
+	if err != nil {
+		return false, fmt.Errorf("set group role: %w", err)
+	}
+	return res.MatchedCount > 0, nil
+}
+
+// DeleteMember removes a membership. Returns false if the user was not a member.
+func (r *GroupRepository) DeleteMember(ctx context.Context, groupID, userID string) (bool, error) {
+	res, err := r.members.DeleteOne(ctx, scoped(ctx, bson.M{"_id": entity.GroupMemberID(groupID, userID)}))
+	if err != nil {
+		return false, fmt.Errorf("delete group member: %w", err)
+	}
+	return res.DeletedCount > 0, nil
+}
+
+func (r *GroupRepository) findOne(ctx context.Context, filter bson.M) (*entity.Group, error) {
+	var g entity.Group
+	if err := r.groups.FindOne(ctx, filter).Decode(&g); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find group: %w", err)
+	}
+	return &g, nil
+}
+
+func (r *GroupRepository) findMembers(ctx context.Context, filter bson.M, opts *options.FindOptionsBuilder) ([]*entity.GroupMember, error) {
+	cursor, err := r.members.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find group members: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	out := []*entity.GroupMember{}
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, fmt.Errorf("decode group members: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"brainbash_backend/internal/model/entity"
+)
+
+const idempotencyCollection = "idempotency_keys"
+
+// IdempotencyRepository handles MongoDB operations for the idempotency_keys collection.
+type IdempotencyRepository struct {
+	collection *mongo.Collection
+	ttl        time.Duration
+}
+
+// NewIdempotencyRepository creates a new IdempotencyRepository whose records expire after ttl.
+func NewIdempotencyRepository(db *mongo.Database, ttl time.Duration) *IdempotencyRepository {
+	return &IdempotencyRepository{
+		collection: db.Collection(idempotencyCollection),
+		ttl:        ttl,
+	}
+}
+
+// EnsureIndexes creates the TTL index that expires records ttl after created_at.
+func (r *IdempotencyRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "created_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(int32(r.ttl.Seconds())),
+	})
+	if err != nil {
+		return fmt.Errorf("create idempotency ttl index: %w", err)
+	}
+	return nil
+}
+
+// Reserve inserts a pending record. If a record with the same _id exists, it is returned
+// with reserved=false and nothing is written.
+func (r *IdempotencyRepository) Reserve(ctx context.Context, record *entity.IdempotencyRecord) (reserved bool, existing *entity.IdempotencyRecord, err error) {
+	_, err = r.collection.InsertOne(ctx, record)
+	if err == nil {
+		return true, nil, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return false, nil, fmt.Errorf("reserve idempotency key: %w", err)
+	}
+
+	var doc entity.IdempotencyRecord
+	if err := r.collection.FindOne(ctx, bson.M{"_id": record.ID}).Decode(&doc); err != nil {
+		if err == mongo.ErrNoDocuments {
+			// Expired or released between insert and read; caller may retry
+			return false, nil, nil
+		}
+		return false, nil, fmt.Errorf("find idempotency key: %w", err)
+	}
+	return false, &doc, nil
+}
+
+// Complete stores the result of the submission and marks the record completed.
+func (r *IdempotencyRepository) Complete(ctx context.Context, id string, result *entity.GameResult) error {
+	update := bson.M{"$set": bson.M{"status": entity.IdempotencyCompleted, "result": result}}
+	if _, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update); err != nil {
+		return fmt.Errorf("complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+// Release deletes a pending record so the submission can be retried.
+func (r *IdempotencyRepository) Release(ctx context.Context, id string) error {
+	filter := bson.M{"_id": id, "status": entity.IdempotencyPending}
+	if _, err := r.collection.DeleteOne(ctx, filter); err != nil {
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// ReleaseIfStale deletes a pending record created before cutoff (an abandoned submission).
+func (r *IdempotencyRepository) ReleaseIfStale(ctx context.Context, id string, cutoff time.Time) error {
+	filter := bson.M{"_id": id, "status": entity.IdempotencyPending, "created_at": bson.M{"$lt": cutoff}}
+	if _, err := r.collection.DeleteOne(ctx, filter); err != nil {
+		return fmt.Errorf("release stale idempotency key: %w", err)
+	}
+	return nil
+}
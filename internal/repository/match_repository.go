@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"brainbash_backend/internal/model/entity"
+)
+
+const matchCollection = "matches"
+
+// MatchRepository handles MongoDB operations for the matches collection.
+type MatchRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMatchRepository creates a new MatchRepository.
+func NewMatchRepository(db *mongo.Database) *MatchRepository {
+	return &MatchRepository{
+		collection: db.Collection(matchCollection),
+	}
+}
+
+// EnsureIndexes creates the index used to list a player's matches.
+func (r *MatchRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "players.user_id", Value: 1}, {Key: "ended_at", Value: -1}},
+	})
+	if err != nil {
+		return fmt.Errorf("create match index: %w", err)
+	}
+	return nil
+}
+
+// Insert stores a finished match.
+func (r *MatchRepository) Insert(ctx context.Context, m *entity.Match) error {
+	if _, err := r.collection.InsertOne(ctx, m); err != nil {
+		return fmt.Errorf("insert match: %w", err)
+	}
+	return nil
+}
+
+// FindByID returns the match, or nil if not found.
+func (r *MatchRepository) FindByID(ctx context.Context, id string) (*entity.Match, error) {
+	var m entity.Match
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&m)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find match: %w", err)
+	}
+	return &m, nil
+}
+
+// FindByUserID returns up to limit matches the user played in, most recent first.
+func (r *MatchRepository) FindByUserID(ctx context.Context, userID string, limit int64) ([]*entity.Match, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "ended_at", Value: -1}}).SetLimit(limit)
+	cursor, err := r.collection.Find(ctx, bson.M{"players.user_id": userID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find matches: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	out := []*entity.Match{}
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, fmt.Errorf("decode matches: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"brainbash_backend/internal/model/entity"
+)
+
+const normCollection = "norms"
+
+// NormRepository handles MongoDB operations for the norms (percentile tables) collection.
+type NormRepository struct {
+	collection *mongo.Collection
+}
+
+// NewNormRepository creates a new NormRepository.
+func NewNormRepository(db *mongo.Database) *NormRepository {
+	return &NormRepository{
+		collection: db.Collection(normCollection),
+	}
+}
+
+// Upsert replaces the norm document (keyed by game type and age band).
+func (r *NormRepository) Upsert(ctx context.Context, norm *entity.Norm) error {
+	norm.ID = entity.NormID(norm.GameType, norm.AgeBand)
+	opts := options.Replace().SetUpsert(true)
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": norm.ID}, norm, opts)
+	if err != nil {
+		return fmt.Errorf("upsert norm: %w", err)
+	}
+	return nil
+}
+
+// FindAll returns all norm documents.
+func (r *NormRepository) FindAll(ctx context.Context) ([]*entity.Norm, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("find norms: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var out []*entity.Norm
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, fmt.Errorf("decode norms: %w", err)
+	}
+	return out, nil
+}
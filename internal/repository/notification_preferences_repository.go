@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"brainbash_backend/internal/model/entity"
+)
+
+const notificationPreferencesCollection = "notification_preferences"
+
+// NotificationPreferencesRepository handles MongoDB operations for the notification_preferences
+// collection (one document per user).
+type NotificationPreferencesRepository struct {
+	collection *mongo.Collection
+}
+
+// NewNotificationPreferencesRepository creates a new NotificationPreferencesRepository.
+func NewNotificationPreferencesRepository(db *mongo.Database) *NotificationPreferencesRepository {
+	return &NotificationPreferencesRepository{
+		collection: db.Collection(notificationPreferencesCollection),
+	}
+}
+
+// FindByUserID returns the user's preferences, or nil if the user never saved any.
+func (r *NotificationPreferencesRepository) FindByUserID(ctx context.Context, userID string) (*entity.NotificationPreferences, error) {
+	var prefs entity.NotificationPreferences
+	err := r.collection.FindOne(ctx, bson.M{"_id": userID}).Decode(&prefs)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find notification preferences: %w", err)
+	}
+	return &prefs, nil
+}
+
+// Upsert replaces the user's preferences.
+func (r *NotificationPreferencesRepository) Upsert(ctx context.Context, prefs *entity.NotificationPreferences) error {
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.collection.ReplaceOne(ctx, bson.M{"_id": prefs.UserID}, prefs, opts); err != nil {
+		return fmt.Errorf("upsert notification preferences: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"brainbash_backend/internal/model/entity"
+)
+
+const notificationCollection = "notifications"
+
+// NotificationRepository handles MongoDB operations for the notifications collection (the outbox).
+type NotificationRepository struct {
+	collection *mongo.Collection
+}
+
+// NewNotificationRepository creates a new NotificationRepository.
+func NewNotificationRepository(db *mongo.Database) *NotificationRepository {
+	return &NotificationRepository{
+		collection: db.Collection(notificationCollection),
+	}
+}
+
+// EnsureIndexes creates the unique dedup index, the dispatcher's due index and the per-user listing index.
+func (r *NotificationRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "dedup_key", Value: 1}, {Key: "channel", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("create notification indexes: %w", err)
+	}
+	return nil
+}
+
+// Insert adds a notification to the outbox. Returns inserted=false when one with the same dedup key
+// and channel exists.
+func (r *NotificationRepository) Insert(ctx context.Context, n *entity.Notification) (inserted bool, err error) {
+	if _, err := r.collection.InsertOne(ctx, n); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("insert notification: %w", err)
+	}
+	return true, nil
+}
+
+// ClaimDue marks the oldest due notification as sending until lockUntil and returns it, or nil when none
+// is due. Sending notifications whose lock expired are due again, so a crashed dispatcher's work is retried.
+func (r *NotificationRepository) ClaimDue(ctx context.Context, now, lockUntil time.Time) (*entity.Notification, error) {
+	filter := bson.M{
+		"status":          bson.M{"$in": bson.A{entity.NotificationPending, entity.NotificationSending}},
+		"next_attempt_at": bson.M{"$lte": now},
+	}
+	update := bson.M{"$set": bson.M{"status": entity.NotificationSending, "next_attempt_at": lockUntil}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var n entity.Notification
+	if err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&n); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("claim notification: %w", err)
+	}
+	return &n, nil
+}
+
+// MarkSent records a successful delivery.
+func (r *NotificationRepository) MarkSent(ctx context.Context, id string, attempts int, at time.Time) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set":   bson.M{"status": entity.NotificationSent, "attempts": attempts, "sent_at": at},
+		"$unset": bson.M{"last_error": ""},
+	})
+	if err != nil {
+		return fmt.Errorf("mark notification sent: %w", err)
+	}
+	return nil
+}
+
+// Reschedule returns a notification to pending with the given next attempt. lastErr is "" when it was
+// deferred rather than failed (e.g. by quiet hours).
+func (r *NotificationRepository) Reschedule(ctx context.Context, id string, attempts int, next time.Time, lastErr string) error {
+	set := bson.M{"status": entity.NotificationPending, "attempts": attempts, "next_attempt_at": next}
+	if lastErr != "" {
+		set["last_error"] = lastErr
+	}
+	if _, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": set}); err != nil {
+		return fmt.Errorf("reschedule notification: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records that delivery was given up.
+func (r *NotificationRepository) MarkFailed(ctx context.Context, id string, attempts int, lastErr string) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set": bson.M{"status": entity.NotificationFailed, "attempts": attempts, "last_error": lastErr},
+	})
+	if err != nil {
+		return fmt.Errorf("mark notification failed: %w", err)
+	}
+	return nil
+}
+
+// FindByUserID returns up to limit of the user's notifications, newest first.
+func (r *NotificationRepository) FindByUserID(ctx context.Context, userID string, limit int64) ([]*entity.Notification, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(limit)
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find notifications: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	out := []*entity.Notification{}
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, fmt.Errorf("decode notifications: %w", err)
+	}
+	return out, nil
+}
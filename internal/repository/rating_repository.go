@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/tenant"
+)
+
+const (
+	ratingCollection        = "ratings"
+	ratingHistoryCollection = "rating_history"
+)
+
+// RatingRepository handles MongoDB operations for the ratings and rating_history collections.
+type RatingRepository struct {
+	ratings *mongo.Collection
+	history *mongo.Collection
+}
+
+// NewRatingRepository creates a new RatingRepository.
+func NewRatingRepository(db *mongo.Database) *RatingRepository {
+	return &RatingRepository{
+		ratings: db.Collection(ratingCollection),
+		history: db.Collection(ratingHistoryCollection),
+	}
+}
+
+// EnsureIndexes creates the indexes used by the rating leaderboard, a user's ratings and their history.
+func (r *RatingRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.ratings.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "game_type", Value: 1}, {Key: "rating", Value: -1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("create rating indexes: %w", err)
+	}
+	_, err = r.history.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "game_type", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		return fmt.Errorf("create rating history index: %w", err)
+	}
+	return nil
+}
+
+// FindByUserID returns all of the user's ratings.
+func (r *RatingRepository) FindByUserID(ctx context.Context, userID string) ([]*entity.Rating, error) {
+	return r.find(ctx, bson.M{"user_id": userID}, nil)
+}
+
+// FindByUserIDs returns the ratings on gameType of the given users; users without one are missing.
+func (r *RatingRepository) FindByUserIDs(ctx context.Context, gameType string, userIDs []string) ([]*entity.Rating, error) {
+	ids := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		ids = append(ids, entity.RatingID(userID, gameType))
+	}
+	return r.find(ctx, bson.M{"_id": bson.M{"$in": ids}}, nil)
+}
+
+// FindTop returns the tenant's limit highest ratings on gameType with a deviation of at most maxDeviation.
+func (r *RatingRepository) FindTop(ctx context.Context, gameType string, maxDeviation float64, limit int64) ([]*entity.Rating, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "rating", Value: -1}, {Key: "updated_at", Value: 1}}).SetLimit(limit)
+	return r.find(ctx, scoped(ctx, bson.M{"game_type": gameType, "deviation": bson.M{"$lte": maxDeviation}}), opts)
+}
+
+// Upsert replaces the rating document, creating it if needed. Within a tenant the document is stamped
+// with it; without one (background jobs) rating.TenantID is stored as is.
+func (r *RatingRepository) Upsert(ctx context.Context, rating *entity.Rating) error {
+	if tenant.FromContext(ctx) != nil {
+		rating.TenantID = tenantID(ctx)
+	}
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.ratings.ReplaceOne(ctx, bson.M{"_id": rating.ID}, rating, opts); err != nil {
+		return fmt.Errorf("upsert rating: %w", err)
+	}
+	return nil
+}
+
+// InsertHistory stores rating changes.
+func (r *RatingRepository) InsertHistory(ctx context.Context, changes []*entity.RatingChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	if _, err := r.history.InsertMany(ctx, changes); err != nil {
+		return fmt.Errorf("insert rating history: %w", err)
+	}
+	return nil
+}
+
+// FindHistory returns up to limit of the user's rating changes on gameType, newest first.
+func (r *RatingRepository) FindHistory(ctx context.Context, userID, gameType string, limit int64) ([]*entity.RatingChange, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+	cursor, err := r.history.Find(ctx, bson.M{"user_id": userID, "game_type": gameType}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find rating history: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	out := []*entity.RatingChange{}
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, fmt.Errorf("decode rating history: %w", err)
+	}
+	return out, nil
+}
+
+func (r *RatingRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptionsBuilder) ([]*entity.Rating, error) {
+	var findOpts []options.Lister[options.FindOptions]
+	if opts != nil {
+		findOpts = append(findOpts, opts)
+	}
+	cursor, err := r.ratings.Find(ctx, filter, findOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("find ratings: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	out := []*entity.Rating{}
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, fmt.Errorf("decode ratings: %w", err)
+	}
+	return out, nil
+}
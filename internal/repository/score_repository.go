@@ -9,11 +9,13 @@ import (
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 
 	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/tenant"
 )
 
 const scoreCollection = "scores"
 
-// ScoreRepository handles MongoDB operations for the score collection.
+// ScoreRepository handles MongoDB operations for the score collection. Queries are scoped to the tenant
+// of the context, so leaderboards built from it only rank the tenant's users.
 type ScoreRepository struct {
 	collection *mongo.Collection
 }
@@ -28,7 +30,7 @@ func NewScoreRepository(db *mongo.Database) *ScoreRepository {
 // FindByUserID returns the score document for the user, or nil if not found.
 func (r *ScoreRepository) FindByUserID(ctx context.Context, userID string) (*entity.Score, error) {
 	var doc entity.Score
-	err := r.collection.FindOne(ctx, bson.M{"user_id": userID}).Decode(&doc)
+	err := r.collection.FindOne(ctx, scoped(ctx, bson.M{"user_id": userID})).Decode(&doc)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, nil
@@ -38,10 +40,14 @@ func (r *ScoreRepository) FindByUserID(ctx context.Context, userID string) (*ent
 	return &doc, nil
 }
 
-// Upsert replaces the score document for the user (one doc per user, keyed by user_id).
+// Upsert replaces the score document for the user (one doc per user, keyed by user_id). Within a tenant
+// the document is stamped with it; without one (background jobs) its stored tenant is kept.
 func (r *ScoreRepository) Upsert(ctx context.Context, score *entity.Score) error {
 	score.ID = score.UserID
-	filter := bson.M{"user_id": score.UserID}
+	if tenant.FromContext(ctx) != nil {
+		score.TenantID = tenantID(ctx)
+	}
+	filter := scoped(ctx, bson.M{"user_id": score.UserID})
 	opts := options.Replace().SetUpsert(true)
 	_, err := r.collection.ReplaceOne(ctx, filter, score, opts)
 	if err != nil {
@@ -50,9 +56,10 @@ func (r *ScoreRepository) Upsert(ctx context.Context, score *entity.Score) error
 	return nil
 }
 
-// FindAll returns all score documents (for cleanup by date range).
+// FindAll returns all score documents of the tenant, or of every tenant outside a request (for cleanup by
+// date range and background jobs).
 func (r *ScoreRepository) FindAll(ctx context.Context) ([]*entity.Score, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{})
+	cursor, err := r.collection.Find(ctx, scoped(ctx, bson.M{}))
 	if err != nil {
 		return nil, fmt.Errorf("find all scores: %w", err)
 	}
@@ -64,3 +71,68 @@ func (r *ScoreRepository) FindAll(ctx context.Context) ([]*entity.Score, error)
 	}
 	return out, nil
 }
+
+// SetOverallScore updates only overall_score for the user.
+func (r *ScoreRepository) SetOverallScore(ctx context.Context, userID string, overall float64) error {
+	_, err := r.collection.UpdateOne(ctx, scoped(ctx, bson.M{"user_id": userID}), bson.M{"$set": bson.M{"overall_score": overall}})
+	if err != nil {
+		return fmt.Errorf("set overall score: %w", err)
+	}
+	return nil
+}
+
+// FindTopByOverall returns the limit score documents with the highest overall_score.
+// Only user_id and overall_score are loaded (sessions are not needed for ranking).
+func (r *ScoreRepository) FindTopByOverall(ctx context.Context, limit int64) ([]*entity.Score, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "overall_score", Value: -1}}).
+		SetLimit(limit).
+		SetProjection(bson.M{"user_id": 1, "overall_score": 1})
+	cursor, err := r.collection.Find(ctx, scoped(ctx, bson.M{"overall_score": bson.M{"$gt": 0}}), opts)
+	if err != nil {
+		return nil, fmt.Errorf("find top scores by overall: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var out []*entity.Score
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, fmt.Errorf("decode scores: %w", err)
+	}
+	return out, nil
+}
+
+// CountAboveOverall counts users whose overall_score is strictly greater than overall.
+func (r *ScoreRepository) CountAboveOverall(ctx context.Context, overall float64) (int64, error) {
+	n, err := r.collection.CountDocuments(ctx, scoped(ctx, bson.M{"overall_score": bson.M{"$gt": overall}}))
+	if err != nil {
+		return 0, fmt.Errorf("count scores above overall: %w", err)
+	}
+	return n, nil
+}
+
+// FindByUserIDs returns the score documents of the given users. Without sessions only user_id,
+// overall_score and the per-game-type aggregates are loaded.
+func (r *ScoreRepository) FindByUserIDs(ctx context.Context, userIDs []string, withSessions bool) ([]*entity.Score, error) {
+	opts := options.Find()
+	if !withSessions {
+		opts.SetProjection(bson.M{
+			"processing_speed.sessions":  0,
+			"working_memory.sessions":    0,
+			"logical_reasoning.sessions": 0,
+			"math_reasoning.sessions":    0,
+			"reflex_time.sessions":       0,
+			"attention_control.sessions": 0,
+		})
+	}
+	cursor, err := r.collection.Find(ctx, scoped(ctx, bson.M{"user_id": bson.M{"$in": userIDs}}), opts)
+	if err != nil {
+		return nil, fmt.Errorf("find scores by user_ids: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var out []*entity.Score
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, fmt.Errorf("decode scores: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,317 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"brainbash_backend/internal/model/entity"
+)
+
+const sessionCollection = "sessions"
+
+// SessionRepository handles MongoDB operations for the sessions collection (one document per session).
+type SessionRepository struct {
+	collection *mongo.Collection
+}
+
+// NewSessionRepository creates a new SessionRepository.
+func NewSessionRepository(db *mongo.Database) *SessionRepository {
+	return &SessionRepository{
+		collection: db.Collection(sessionCollection),
+	}
+}
+
+// EnsureIndexes creates the unique index on (user_id, session_id) and the indexes used for newest-first
+// pagination per user (optionally per game type) and for date-range cleanup. Records stored when _id was
+// the session_id get that id as session_id first.
+func (r *SessionRepository) EnsureIndexes(ctx context.Context) error {
+	if err := copyIDToSessionID(ctx, r.collection); err != nil {
+		return fmt.Errorf("migrate session ids: %w", err)
+	}
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "session_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "game_type", Value: 1}, {Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "timestamp", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("create session indexes: %w", err)
+	}
+	return nil
+}
+
+// Insert stores a session record.
+func (r *SessionRepository) Insert(ctx context.Context, record *entity.SessionRecord) error {
+	if _, err := r.collection.InsertOne(ctx, record); err != nil {
+		return fmt.Errorf("insert session: %w", err)
+	}
+	return nil
+}
+
+// InsertMissing inserts records whose (user_id, session_id) is not stored yet and returns how many were inserted.
+func (r *SessionRepository) InsertMissing(ctx context.Context, records []*entity.SessionRecord) (int, error) {
+	if len(records) == 0 {
+		return 0, nil
+	}
+	res, err := r.collection.InsertMany(ctx, records, options.InsertMany().SetOrdered(false))
+	if err != nil {
+		var bulkErr mongo.BulkWriteException
+		if errors.As(err, &bulkErr) && onlyDuplicateKeyErrors(bulkErr) {
+			return len(records) - len(bulkErr.WriteErrors), nil
+		}
+		return 0, fmt.Errorf("insert sessions: %w", err)
+	}
+	return len(res.InsertedIDs), nil
+}
+
+// FindBySessionID returns the user's session record, or nil if not found.
+func (r *SessionRepository) FindBySessionID(ctx context.Context, userID, sessionID string) (*entity.SessionRecord, error) {
+	var record entity.SessionRecord
+	err := r.collection.FindOne(ctx, bson.M{"user_id": userID, "session_id": sessionID}).Decode(&record)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find session: %w", err)
+	}
+	return &record, nil
+}
+
+// FindPage returns up to limit of the user's sessions newest first, without question_responses.
+// gameType "" matches all game types. When afterTimestamp is non-zero, only sessions strictly older
+// than (afterTimestamp, afterID) in (timestamp, _id) order are returned.
+func (r *SessionRepository) FindPage(ctx context.Context, userID, gameType string, afterTimestamp time.Time, afterID string, limit int64) ([]*entity.SessionRecord, error) {
+	filter := bson.M{"user_id": userID}
+	if gameType != "" {
+		filter["game_type"] = gameType
+	}
+	if !afterTimestamp.IsZero() {
+		filter["$or"] = bson.A{
+			bson.M{"timestamp": bson.M{"$lt": afterTimestamp}},
+			bson.M{"timestamp": afterTimestamp, "_id": bson.M{"$lt": afterID}},
+		}
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(limit).
+		SetProjection(bson.M{"question_responses": 0})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find sessions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	out := []*entity.SessionRecord{}
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, fmt.Errorf("decode sessions: %w", err)
+	}
+	return out, nil
+}
+
+// FindInRange returns the user's sessions of gameType with timestamp in [from, to], oldest first,
+// without question_responses.
+func (r *SessionRepository) FindInRange(ctx context.Context, userID, gameType string, from, to time.Time) ([]*entity.SessionRecord, error) {
+	filter := bson.M{
+		"user_id":   userID,
+		"game_type": gameType,
+		"timestamp": bson.M{"$gte": from, "$lte": to},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: 1}}).
+		SetProjection(bson.M{"question_responses": 0})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find sessions in range: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	out := []*entity.SessionRecord{}
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, fmt.Errorf("decode sessions: %w", err)
+	}
+	return out, nil
+}
+
+// CountInRange counts the user's sessions with timestamp in [from, to]. gameType "" counts all game types.
+func (r *SessionRepository) CountInRange(ctx context.Context, userID, gameType string, from, to time.Time) (int64, error) {
+	filter := bson.M{
+		"user_id":   userID,
+		"timestamp": bson.M{"$gte": from, "$lte": to},
+	}
+	if gameType != "" {
+		filter["game_type"] = gameType
+	}
+	n, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("count sessions in range: %w", err)
+	}
+	return n, nil
+}
+
+// CountEligibleInRange counts like CountInRange, skipping sessions that are flagged and not approved.
+func (r *SessionRepository) CountEligibleInRange(ctx context.Context, userID, gameType string, from, to time.Time) (int64, error) {
+	filter := bson.M{
+		"user_id":   userID,
+		"timestamp": bson.M{"$gte": from, "$lte": to},
+		"$or": bson.A{
+			bson.M{"flags.0": bson.M{"$exists": false}},
+			bson.M{"review_status": entity.ReviewApproved},
+		},
+	}
+	if gameType != "" {
+		filter["game_type"] = gameType
+	}
+	n, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("count eligible sessions in range: %w", err)
+	}
+	return n, nil
+}
+
+// DistinctUserIDs returns the users of the tenant with at least one session with timestamp in [from, to].
+func (r *SessionRepository) DistinctUserIDs(ctx context.Context, from, to time.Time) ([]string, error) {
+	var out []string
+	err := r.collection.Distinct(ctx, "user_id", scoped(ctx, bson.M{"timestamp": bson.M{"$gte": from, "$lte": to}})).Decode(&out)
+	if err != nil {
+		return nil, fmt.Errorf("find distinct session users: %w", err)
+	}
+	return out, nil
+}
+
+// improvementWindow is how many of the first and last scored sessions are compared for improvement.
+const improvementWindow = 3
+
+// AggregateActivity returns the activity of the given users over [from, to) per game type, built with an
+// aggregation pipeline so sessions are not loaded. Users without sessions before to are left out.
+func (r *SessionRepository) AggregateActivity(ctx context.Context, userIDs []string, from, to time.Time) ([]*entity.MemberActivity, error) {
+	inRange := bson.D{{Key: "$gte", Value: bson.A{"$timestamp", from}}}
+	// Same rule as Session.LeaderboardEligible
+	eligible := bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: "$eq", Value: bson.A{bson.D{{Key: "$size", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$flags", bson.A{}}}}}}, 0}}},
+		bson.D{{Key: "$eq", Value: bson.A{"$review_status", entity.ReviewApproved}}},
+	}}}
+	window := bson.D{{Key: "$toInt", Value: bson.D{{Key: "$min", Value: bson.A{
+		improvementWindow,
+		bson.D{{Key: "$floor", Value: bson.D{{Key: "$divide", Value: bson.A{"$scored", 2}}}}},
+	}}}}}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{
+			{Key: "user_id", Value: bson.D{{Key: "$in", Value: userIDs}}},
+			{Key: "timestamp", Value: bson.D{{Key: "$lt", Value: to}}},
+		}}},
+		{{Key: "$project", Value: bson.D{
+			{Key: "user_id", Value: 1},
+			{Key: "game_type", Value: 1},
+			{Key: "timestamp", Value: 1},
+			{Key: "in_range", Value: inRange},
+			{Key: "score", Value: bson.D{{Key: "$cond", Value: bson.A{
+				bson.D{{Key: "$and", Value: bson.A{inRange, eligible}}}, "$session_score.score", nil,
+			}}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "timestamp", Value: 1}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "user_id", Value: "$user_id"}, {Key: "game_type", Value: "$game_type"}}},
+			{Key: "sessions", Value: bson.D{{Key: "$sum", Value: bson.D{{Key: "$cond", Value: bson.A{"$in_range", 1, 0}}}}}},
+			{Key: "last_active_at", Value: bson.D{{Key: "$max", Value: "$timestamp"}}},
+			{Key: "scores", Value: bson.D{{Key: "$push", Value: "$score"}}},
+		}}},
+		{{Key: "$set", Value: bson.D{
+			{Key: "scores", Value: bson.D{{Key: "$filter", Value: bson.D{
+				{Key: "input", Value: "$scores"},
+				{Key: "cond", Value: bson.D{{Key: "$ne", Value: bson.A{"$$this", nil}}}},
+			}}}},
+		}}},
+		{{Key: "$set", Value: bson.D{{Key: "scored", Value: bson.D{{Key: "$size", Value: "$scores"}}}}}},
+		{{Key: "$set", Value: bson.D{{Key: "window", Value: window}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$_id.user_id"},
+			{Key: "sessions", Value: bson.D{{Key: "$sum", Value: "$sessions"}}},
+			{Key: "last_active_at", Value: bson.D{{Key: "$max", Value: "$last_active_at"}}},
+			{Key: "domains", Value: bson.D{{Key: "$push", Value: bson.D{
+				{Key: "game_type", Value: "$_id.game_type"},
+				{Key: "sessions", Value: "$sessions"},
+				{Key: "scored", Value: "$scored"},
+				{Key: "avg_score", Value: bson.D{{Key: "$avg", Value: "$scores"}}},
+				{Key: "improvement", Value: bson.D{{Key: "$cond", Value: bson.A{
+					bson.D{{Key: "$gte", Value: bson.A{"$window", 1}}},
+					bson.D{{Key: "$subtract", Value: bson.A{
+						bson.D{{Key: "$avg", Value: bson.D{{Key: "$slice", Value: bson.A{"$scores", bson.D{{Key: "$multiply", Value: bson.A{-1, "$window"}}}}}}}},
+						bson.D{{Key: "$avg", Value: bson.D{{Key: "$slice", Value: bson.A{"$scores", "$window"}}}}},
+					}}},
+					nil,
+				}}}},
+			}}}},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate session activity: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	out := []*entity.MemberActivity{}
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, fmt.Errorf("decode session activity: %w", err)
+	}
+	return out, nil
+}
+
+// SetReviewStatus updates the review status of the user's flagged session.
+func (r *SessionRepository) SetReviewStatus(ctx context.Context, userID, sessionID, status string) error {
+	filter := bson.M{"user_id": userID, "session_id": sessionID}
+	_, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"review_status": status}})
+	if err != nil {
+		return fmt.Errorf("update session review status: %w", err)
+	}
+	return nil
+}
+
+// DeleteBySessionID removes the user's session record.
+func (r *SessionRepository) DeleteBySessionID(ctx context.Context, userID, sessionID string) error {
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"user_id": userID, "session_id": sessionID}); err != nil {
+		return fmt.Errorf("delete session: %w", err)
+	}
+	return nil
+}
+
+// DeleteInDateRange removes the session records of the tenant in ctx whose timestamp falls within [start, end].
+func (r *SessionRepository) DeleteInDateRange(ctx context.Context, start, end time.Time) error {
+	_, err := r.collection.DeleteMany(ctx, scoped(ctx, bson.M{"timestamp": bson.M{"$gte": start, "$lte": end}}))
+	if err != nil {
+		return fmt.Errorf("delete sessions in date range: %w", err)
+	}
+	return nil
+}
+
+// copyIDToSessionID sets session_id to _id on documents that have none, from when _id was the session_id.
+func copyIDToSessionID(ctx context.Context, collection *mongo.Collection) error {
+	_, err := collection.UpdateMany(ctx,
+		bson.M{"session_id": bson.M{"$exists": false}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"session_id": "$_id"}}}})
+	return err
+}
+
+func onlyDuplicateKeyErrors(e mongo.BulkWriteException) bool {
+	if e.WriteConcernError != nil {
+		return false
+	}
+	for _, we := range e.WriteErrors {
+		if !mongo.IsDuplicateKeyError(we) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"brainbash_backend/internal/model/entity"
+)
+
+const (
+	followCollection        = "follows"
+	friendRequestCollection = "friend_requests"
+	blockCollection         = "blocks"
+)
+
+// SocialRepository handles MongoDB operations for the social graph: the follows, friend_requests and
+// blocks collections. All relations are directed documents keyed by entity.PairID.
+type SocialRepository struct {
+	follows  *mongo.Collection
+	requests *mongo.Collection
+	blocks   *mongo.Collection
+}
+
+// NewSocialRepository creates a new SocialRepository.
+func NewSocialRepository(db *mongo.Database) *SocialRepository {
+	return &SocialRepository{
+		follows:  db.Collection(followCollection),
+		requests: db.Collection(friendRequestCollection),
+		blocks:   db.Collection(blockCollection),
+	}
+}
+
+// EnsureIndexes creates the indexes used to list relations from either side.
+func (r *SocialRepository) EnsureIndexes(ctx context.Context) error {
+	if _, err := r.follows.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "follower_id", Value: 1}}},
+		{Keys: bson.D{{Key: "followee_id", Value: 1}}},
+	}); err != nil {
+		return fmt.Errorf("create follow indexes: %w", err)
+	}
+	if _, err := r.requests.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "from_user_id", Value: 1}, {Key: "status", Value: 1}}},
+		{Keys: bson.D{{Key: "to_user_id", Value: 1}, {Key: "status", Value: 1}}},
+	}); err != nil {
+		return fmt.Errorf("create friend request indexes: %w", err)
+	}
+	if _, err := r.blocks.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "blocker_id", Value: 1}},
+	}); err != nil {
+		return fmt.Errorf("create block index: %w", err)
+	}
+	return nil
+}
+
+// InsertFollow stores a follow. Following twice is a no-op.
+func (r *SocialRepository) InsertFollow(ctx context.Context, f *entity.Follow) error {
+	if _, err := r.follows.InsertOne(ctx, f); err != nil && !mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("insert follow: %w", err)
+	}
+	return nil
+}
+
+// DeleteFollow removes the follow from followerID to followeeID, if any.
+func (r *SocialRepository) DeleteFollow(ctx context.Context, followerID, followeeID string) error {
+	if _, err := r.follows.DeleteOne(ctx, bson.M{"_id": entity.PairID(followerID, followeeID)}); err != nil {
+		return fmt.Errorf("delete follow: %w", err)
+	}
+	return nil
+}
+
+// FindFollowing returns the ids of the users userID follows.
+func (r *SocialRepository) FindFollowing(ctx context.Context, userID string) ([]string, error) {
+	return r.distinct(ctx, r.follows, "followee_id", bson.M{"follower_id": userID})
+}
+
+// FindFollowers returns the ids of the users following userID.
+func (r *SocialRepository) FindFollowers(ctx context.Context, userID string) ([]string, error) {
+	return r.distinct(ctx, r.follows, "follower_id", bson.M{"followee_id": userID})
+}
+
+// CountFollowing counts the users userID follows.
+func (r *SocialRepository) CountFollowing(ctx context.Context, userID string) (int64, error) {
+	n, err := r.follows.CountDocuments(ctx, bson.M{"follower_id": userID})
+	if err != nil {
+		return 0, fmt.Errorf("count following: %w", err)
+	}
+	return n, nil
+}
+
+// InsertRequest stores a friend request. Returns inserted=false when a request between the same users
+// in the same direction exists.
+func (r *SocialRepository) InsertRequest(ctx context.Context, req *entity.FriendRequest) (inserted bool, err error) {
+	if _, err := r.requests.InsertOne(ctx, req); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("insert friend request: %w", err)
+	}
+	return true, nil
+}
+
+// FindRequest returns the request from fromUserID to toUserID, or nil if there is none.
+func (r *SocialRepository) FindRequest(ctx context.Context, fromUserID, toUserID string) (*entity.FriendRequest, error) {
+	var req entity.FriendRequest
+	err := r.requests.FindOne(ctx, bson.M{"_id": entity.PairID(fromUserID, toUserID)}).Decode(&req)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find friend request: %w", err)
+	}
+	return &req, nil
+}
+
+// AcceptRequest marks the pending request from fromUserID to toUserID accepted. Returns false when
+// there is no such pending request.
+func (r *SocialRepository) AcceptRequest(ctx context.Context, fromUserID, toUserID string, at time.Time) (bool, error) {
+	res, err := r.requests.UpdateOne(ctx,
+		bson.M{"_id": entity.PairID(fromUserID, toUserID), "status": entity.FriendRequestPending},
+		bson.M{"$set": bson.M{"status": entity.FriendRequestAccepted, "accepted_at": at}})
+	if err != nil {
+		return false, fmt.Errorf("accept friend request: %w", err)
+	}
+	return res.ModifiedCount > 0, nil
+}
+
+// DeleteRequest removes the request from fromUserID to toUserID with the given status ("" for any).
+// Returns false when there was none.
+func (r *SocialRepository) DeleteRequest(ctx context.Context, fromUserID, toUserID, status string) (bool, error) {
+	filter := bson.M{"_id": entity.PairID(fromUserID, toUserID)}
+	if status != "" {
+		filter["status"] = status
+	}
+	res, err := r.requests.DeleteOne(ctx, filter)
+	if err != nil {
+		return false, fmt.Errorf("delete friend request: %w", err)
+	}
+	return res.DeletedCount > 0, nil
+}
+
+// FindRequests returns requests with status sent by userID (outgoing) or received by userID, oldest first.
+func (r *SocialRepository) FindRequests(ctx context.Context, userID, status string, outgoing bool) ([]*entity.FriendRequest, error) {
+	filter := bson.M{"to_user_id": userID, "status": status}
+	if outgoing {
+		filter = bson.M{"from_user_id": userID, "status": status}
+	}
+	cursor, err := r.requests.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("find friend requests: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	out := []*entity.FriendRequest{}
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, fmt.Errorf("decode friend requests: %w", err)
+	}
+	return out, nil
+}
+
+// FindFriendIDs returns the ids of userID's friends (accepted requests in either direction).
+func (r *SocialRepository) FindFriendIDs(ctx context.Context, userID string) ([]string, error) {
+	sent, err := r.distinct(ctx, r.requests, "to_user_id", bson.M{"from_user_id": userID, "status": entity.FriendRequestAccepted})
+	if err != nil {
+		return nil, err
+	}
+	received, err := r.distinct(ctx, r.requests, "from_user_id", bson.M{"to_user_id": userID, "status": entity.FriendRequestAccepted})
+	if err != nil {
+		return nil, err
+	}
+	return append(sent, received...), nil
+}
+
+// InsertBlock stores a block. Blocking twice is a no-op.
+func (r *SocialRepository) InsertBlock(ctx context.Context, b *entity.Block) error {
+	if _, err := r.blocks.InsertOne(ctx, b); err != nil && !mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("insert block: %w", err)
+	}
+	return nil
+}
+
+// DeleteBlock removes the block of blockedID by blockerID. Returns false when there was none.
+func (r *SocialRepository) DeleteBlock(ctx context.Context, blockerID, blockedID string) (bool, error) {
+	res, err := r.blocks.DeleteOne(ctx, bson.M{"_id": entity.PairID(blockerID, blockedID)})
+	if err != nil {
+		return false, fmt.Errorf("delete block: %w", err)
+	}
+	return res.DeletedCount > 0, nil
+}
+
+// IsBlocked returns true if either user blocked the other.
+func (r *SocialRepository) IsBlocked(ctx context.Context, userA, userB string) (bool, error) {
+	n, err := r.blocks.CountDocuments(ctx, bson.M{"_id": bson.M{"$in": bson.A{entity.PairID(userA, userB), entity.PairID(userB, userA)}}})
+	if err != nil {
+		return false, fmt.Errorf("check block: %w", err)
+	}
+	return n > 0, nil
+}
+
+// FindBlocked returns the ids of the users blockerID blocked.
+func (r *SocialRepository) FindBlocked(ctx context.Context, blockerID string) ([]string, error) {
+	return r.distinct(ctx, r.blocks, "blocked_id", bson.M{"blocker_id": blockerID})
+}
+
+func (r *SocialRepository) distinct(ctx context.Context, coll *mongo.Collection, field string, filter bson.M) ([]string, error) {
+	out := []string{}
+	if err := coll.Distinct(ctx, field, filter).Decode(&out); err != nil {
+		return nil, fmt.Errorf("find distinct %s in %s: %w", field, coll.Name(), err)
+	}
+	return out, nil
+}
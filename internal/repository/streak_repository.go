@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"brainbash_backend/internal/model/entity"
+)
+
+const streakCollection = "streaks"
+
+// StreakRepository handles MongoDB operations for the streaks collection (one document per user).
+type StreakRepository struct {
+	collection *mongo.Collection
+}
+
+// NewStreakRepository creates a new StreakRepository.
+func NewStreakRepository(db *mongo.Database) *StreakRepository {
+	return &StreakRepository{
+		collection: db.Collection(streakCollection),
+	}
+}
+
+// FindByUserID returns the user's streak, or nil if the user never played.
+func (r *StreakRepository) FindByUserID(ctx context.Context, userID string) (*entity.Streak, error) {
+	var streak entity.Streak
+	err := r.collection.FindOne(ctx, bson.M{"_id": userID}).Decode(&streak)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find streak: %w", err)
+	}
+	return &streak, nil
+}
+
+// Upsert replaces the user's streak document.
+func (r *StreakRepository) Upsert(ctx context.Context, streak *entity.Streak) error {
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.collection.ReplaceOne(ctx, bson.M{"_id": streak.UserID}, streak, opts); err != nil {
+		return fmt.Errorf("upsert streak: %w", err)
+	}
+	return nil
+}
+
+// FindByLastPlayedDays returns streaks of at least minCurrent days whose last played day is one of days.
+func (r *StreakRepository) FindByLastPlayedDays(ctx context.Context, days []string, minCurrent int) ([]*entity.Streak, error) {
+	filter := bson.M{"last_played_day": bson.M{"$in": days}, "current": bson.M{"$gte": minCurrent}}
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("find streaks by last played day: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	out := []*entity.Streak{}
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, fmt.Errorf("decode streaks: %w", err)
+	}
+	return out, nil
+}
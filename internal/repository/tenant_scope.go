@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"brainbash_backend/internal/tenant"
+)
+
+// scoped adds the tenant carried by ctx to filter and returns it. Default-tenant documents have no
+// tenant_id, which {tenant_id: null} matches. Contexts without a tenant (background jobs) are not scoped.
+func scoped(ctx context.Context, filter bson.M) bson.M {
+	t := tenant.FromContext(ctx)
+	switch {
+	case t == nil:
+	case t.IsDefault():
+		filter["tenant_id"] = nil
+	default:
+		filter["tenant_id"] = t.ID
+	}
+	return filter
+}
+
+// tenantID returns the tenant_id to store on documents created in ctx: "" (omitted) for the default
+// tenant and for contexts without one.
+func tenantID(ctx context.Context) string {
+	if t := tenant.FromContext(ctx); t != nil && !t.IsDefault() {
+		return t.ID
+	}
+	return ""
+}
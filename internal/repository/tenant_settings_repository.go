@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"brainbash_backend/internal/model/entity"
+)
+
+const tenantSettingsCollection = "tenant_settings"
+
+// TenantSettingsRepository handles MongoDB operations for the tenant_settings collection (one document
+// per tenant). It is not tenant-scoped: the tenant is the document id.
+type TenantSettingsRepository struct {
+	collection *mongo.Collection
+}
+
+// NewTenantSettingsRepository creates a new TenantSettingsRepository.
+func NewTenantSettingsRepository(db *mongo.Database) *TenantSettingsRepository {
+	return &TenantSettingsRepository{
+		collection: db.Collection(tenantSettingsCollection),
+	}
+}
+
+// EnsureIndexes creates the index used to find settings changed since the last sync.
+func (r *TenantSettingsRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "updated_at", Value: 1}},
+	})
+	if err != nil {
+		return fmt.Errorf("create tenant settings index: %w", err)
+	}
+	return nil
+}
+
+// FindByID returns the tenant's settings, or nil if its admins never saved any.
+func (r *TenantSettingsRepository) FindByID(ctx context.Context, tenantID string) (*entity.TenantSettings, error) {
+	var settings entity.TenantSettings
+	err := r.collection.FindOne(ctx, bson.M{"_id": tenantID}).Decode(&settings)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find tenant settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// FindUpdatedSince returns the ids of tenants whose settings changed at or after since.
+func (r *TenantSettingsRepository) FindUpdatedSince(ctx context.Context, since time.Time) ([]string, error) {
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	cursor, err := r.collection.Find(ctx, bson.M{"updated_at": bson.M{"$gte": since}}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find updated tenant settings: %w", err)
+	}
+	defer cursor.Close(ctx)
+	var docs []struct {
+		ID string `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("decode updated tenant settings: %w", err)
+	}
+	ids := make([]string, 0, len(docs))
+	for _, d := range docs {
+		ids = append(ids, d.ID)
+	}
+	return ids, nil
+}
+
+// Upsert replaces the tenant's settings.
+func (r *TenantSettingsRepository) Upsert(ctx context.Context, settings *entity.TenantSettings) error {
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.collection.ReplaceOne(ctx, bson.M{"_id": settings.TenantID}, settings, opts); err != nil {
+		return fmt.Errorf("upsert tenant settings: %w", err)
+	}
+	return nil
+}
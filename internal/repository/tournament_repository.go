@@ -0,0 +1,291 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"brainbash_backend/internal/model/entity"
+)
+
+const (
+	tournamentCollection        = "tournaments"
+	tournamentPlayerCollection  = "tournament_players"
+	tournamentPairingCollection = "tournament_pairings"
+)
+
+// TournamentRepository handles MongoDB operations for the tournaments, tournament_players and
+// tournament_pairings collections.
+type TournamentRepository struct {
+	tournaments *mongo.Collection
+	players     *mongo.Collection
+	pairings    *mongo.Collection
+}
+
+// NewTournamentRepository creates a new TournamentRepository.
+func NewTournamentRepository(db *mongo.Database) *TournamentRepository {
+	return &TournamentRepository{
+		tournaments: db.Collection(tournamentCollection),
+		players:     db.Collection(tournamentPlayerCollection),
+		pairings:    db.Collection(tournamentPairingCollection),
+	}
+}
+
+// EnsureIndexes creates the indexes used to list tournaments, find ones due to advance, and load a
+// tournament's players and pairings, which hold one pairing per player and round.
+func (r *TournamentRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.tournaments.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "status", Value: 1}, {Key: "entry_opens_at", Value: -1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "entry_closes_at", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "round_ends_at", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("create tournament indexes: %w", err)
+	}
+	_, err = r.players.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tournament_id", Value: 1}, {Key: "joined_at", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("create tournament player indexes: %w", err)
+	}
+	// A player has one pairing per round, so pairing a round again cannot add a second one
+	_, err = r.pairings.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tournament_id", Value: 1}, {Key: "round", Value: 1}, {Key: "table", Value: 1}}},
+		{
+			Keys:    bson.D{{Key: "tournament_id", Value: 1}, {Key: "round", Value: 1}, {Key: "player_a", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "tournament_id", Value: 1}, {Key: "round", Value: 1}, {Key: "player_b", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"player_b": bson.M{"$exists": true}}),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("create tournament pairing indexes: %w", err)
+	}
+	return nil
+}
+
+// Insert stores a new tournament of the tenant in ctx.
+func (r *TournamentRepository) Insert(ctx context.Context, t *entity.Tournament) error {
+	t.TenantID = tenantID(ctx)
+	if _, err := r.tournaments.InsertOne(ctx, t); err != nil {
+		return fmt.Errorf("insert tournament: %w", err)
+	}
+	return nil
+}
+
+// FindByID returns the tenant's tournament, or nil if not found.
+func (r *TournamentRepository) FindByID(ctx context.Context, id string) (*entity.Tournament, error) {
+	var t entity.Tournament
+	err := r.tournaments.FindOne(ctx, scoped(ctx, bson.M{"_id": id})).Decode(&t)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find tournament: %w", err)
+	}
+	return &t, nil
+}
+
+// Find returns up to limit of the tenant's tournaments, latest entry window first, optionally restricted
+// to one status.
+func (r *TournamentRepository) Find(ctx context.Context, status string, limit int64) ([]*entity.Tournament, error) {
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "entry_opens_at", Value: -1}}).SetLimit(limit)
+	return r.findTournaments(ctx, scoped(ctx, filter), opts)
+}
+
+// FindDue returns up to limit tournaments whose entry window closed or whose current round ended at or
+// before cutoff.
+func (r *TournamentRepository) FindDue(ctx context.Context, cutoff time.Time, limit int64) ([]*entity.Tournament, error) {
+	filter := bson.M{"$or": bson.A{
+		bson.M{"status": entity.TournamentRegistration, "entry_closes_at": bson.M{"$lte": cutoff}},
+		bson.M{"status": entity.TournamentRunning, "round_ends_at": bson.M{"$lte": cutoff}},
+	}}
+	return r.findTournaments(ctx, scoped(ctx, filter), options.Find().SetLimit(limit))
+}
+
+// Transition applies set to the tournament if it is still in status and round. Returns false when it is
+// missing or already moved on, so concurrent transitions apply at most once.
+func (r *TournamentRepository) Transition(ctx context.Context, id, status string, round int, set bson.M) (bool, error) {
+	res, err := r.tournaments.UpdateOne(ctx, scoped(ctx, bson.M{"_id": id, "status": status, "current_round": round}), bson.M{"$set": set})
+	if err != nil {
+		return false, fmt.Errorf("update tournament: %w", err)
+	}
+	return res.ModifiedCount > 0, nil
+}
+
+// InsertPlayer adds a player to the tournament. Returns false if they already joined.
+func (r *TournamentRepository) InsertPlayer(ctx context.Context, p *entity.TournamentPlayer) (inserted bool, err error) {
+	if _, err := r.players.InsertOne(ctx, p); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("insert tournament player: %w", err)
+	}
+	return true, nil
+}
+
+// DeletePlayer removes a player from the tournament. Returns false if they had not joined.
+func (r *TournamentRepository) DeletePlayer(ctx context.Context, tournamentID, userID string) (bool, error) {
+	res, err := r.players.DeleteOne(ctx, bson.M{"_id": entity.TournamentPlayerID(tournamentID, userID)})
+	if err != nil {
+		return false, fmt.Errorf("delete tournament player: %w", err)
+	}
+	return res.DeletedCount > 0, nil
+}
+
+// FindPlayer returns the user's entry in the tournament, or nil if they did not join.
+func (r *TournamentRepository) FindPlayer(ctx context.Context, tournamentID, userID string) (*entity.TournamentPlayer, error) {
+	var p entity.TournamentPlayer
+	err := r.players.FindOne(ctx, bson.M{"_id": entity.TournamentPlayerID(tournamentID, userID)}).Decode(&p)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find tournament player: %w", err)
+	}
+	return &p, nil
+}
+
+// FindPlayers returns the tournament's players in the order they joined.
+func (r *TournamentRepository) FindPlayers(ctx context.Context, tournamentID string) ([]*entity.TournamentPlayer, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "joined_at", Value: 1}})
+	cursor, err := r.players.Find(ctx, bson.M{"tournament_id": tournamentID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find tournament players: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	out := []*entity.TournamentPlayer{}
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, fmt.Errorf("decode tournament players: %w", err)
+	}
+	return out, nil
+}
+
+// CountPlayers returns how many players joined the tournament.
+func (r *TournamentRepository) CountPlayers(ctx context.Context, tournamentID string) (int64, error) {
+	n, err := r.players.CountDocuments(ctx, bson.M{"tournament_id": tournamentID})
+	if err != nil {
+		return 0, fmt.Errorf("count tournament players: %w", err)
+	}
+	return n, nil
+}
+
+// UpdatePlayer applies inc and set to the user's entry; either may be nil.
+func (r *TournamentRepository) UpdatePlayer(ctx context.Context, tournamentID, userID string, inc, set bson.M) error {
+	update := bson.M{}
+	if len(inc) > 0 {
+		update["$inc"] = inc
+	}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(update) == 0 {
+		return nil
+	}
+	if _, err := r.players.UpdateOne(ctx, bson.M{"_id": entity.TournamentPlayerID(tournamentID, userID)}, update); err != nil {
+		return fmt.Errorf("update tournament player: %w", err)
+	}
+	return nil
+}
+
+// InsertPairings stores a round's pairings. Pairings that already exist are kept.
+func (r *TournamentRepository) InsertPairings(ctx context.Context, pairings []*entity.TournamentPairing) error {
+	if len(pairings) == 0 {
+		return nil
+	}
+	_, err := r.pairings.InsertMany(ctx, pairings, options.InsertMany().SetOrdered(false))
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("insert tournament pairings: %w", err)
+	}
+	return nil
+}
+
+// FindPairings returns the pairings of a round, or of every round when round is 0, by round and table.
+func (r *TournamentRepository) FindPairings(ctx context.Context, tournamentID string, round int) ([]*entity.TournamentPairing, error) {
+	filter := bson.M{"tournament_id": tournamentID}
+	if round > 0 {
+		filter["round"] = round
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "round", Value: 1}, {Key: "table", Value: 1}})
+	cursor, err := r.pairings.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find tournament pairings: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	out := []*entity.TournamentPairing{}
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, fmt.Errorf("decode tournament pairings: %w", err)
+	}
+	return out, nil
+}
+
+// FindPairing returns the user's pairing in a round, or nil when they have none.
+func (r *TournamentRepository) FindPairing(ctx context.Context, tournamentID string, round int, userID string) (*entity.TournamentPairing, error) {
+	filter := bson.M{
+		"tournament_id": tournamentID,
+		"round":         round,
+		"$or":           bson.A{bson.M{"player_a": userID}, bson.M{"player_b": userID}},
+	}
+	var p entity.TournamentPairing
+	if err := r.pairings.FindOne(ctx, filter).Decode(&p); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find tournament pairing: %w", err)
+	}
+	return &p, nil
+}
+
+// SetResult stores side's result if the pairing is pending and that side has not submitted yet.
+// Returns the updated pairing, or nil when the result was not stored.
+func (r *TournamentRepository) SetResult(ctx context.Context, id, side string, result *entity.TournamentResult) (*entity.TournamentPairing, error) {
+	filter := bson.M{"_id": id, "status": entity.PairingPending, side: bson.M{"$exists": false}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var p entity.TournamentPairing
+	err := r.pairings.FindOneAndUpdate(ctx, filter, bson.M{"$set": bson.M{side: result}}, opts).Decode(&p)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("set tournament result: %w", err)
+	}
+	return &p, nil
+}
+
+// CompletePairing applies set to a pending pairing and marks it completed. Returns false when it was
+// already completed, so each pairing is settled once.
+func (r *TournamentRepository) CompletePairing(ctx context.Context, id string, set bson.M) (bool, error) {
+	set["status"] = entity.PairingCompleted
+	res, err := r.pairings.UpdateOne(ctx, bson.M{"_id": id, "status": entity.PairingPending}, bson.M{"$set": set})
+	if err != nil {
+		return false, fmt.Errorf("complete tournament pairing: %w", err)
+	}
+	return res.ModifiedCount > 0, nil
+}
+
+func (r *TournamentRepository) findTournaments(ctx context.Context, filter bson.M, opts *options.FindOptionsBuilder) ([]*entity.Tournament, error) {
+	cursor, err := r.tournaments.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find tournaments: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	out := []*entity.Tournament{}
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, fmt.Errorf("decode tournaments: %w", err)
+	}
+	return out, nil
+}
@@ -13,7 +13,8 @@ import (
 
 const usersCollection = "users"
 
-// UserRepository handles MongoDB operations for the users collection.
+// UserRepository handles MongoDB operations for the users collection. Queries are scoped to the tenant
+// of the context, so the same Google account is a separate user in each tenant.
 type UserRepository struct {
 	collection *mongo.Collection
 }
@@ -25,10 +26,32 @@ func NewUserRepository(db *mongo.Database) *UserRepository {
 	}
 }
 
+// EnsureIndexes creates the unique index on friend_code and the per-tenant unique index on display names
+// (users without a code or display name are not indexed).
+func (r *UserRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "friend_code", Value: 1}},
+			Options: options.Index().SetUnique(true).SetSparse(true),
+		},
+		{
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "display_name_key", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"display_name_key": bson.M{"$exists": true}}),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create user indexes: %w", err)
+	}
+	return nil
+}
+
 // UpsertByGaID inserts a new user or updates an existing one matched by ga_id.
+// Only the fields mirrored from Google are set; profile fields the user customized are left alone.
 // Returns the upserted/found user.
 func (r *UserRepository) UpsertByGaID(ctx context.Context, user *entity.User) (*entity.User, error) {
-	filter := bson.M{"ga_id": user.GaID}
+	// The tenant in the filter is stored on insert
+	filter := scoped(ctx, bson.M{"ga_id": user.GaID})
 	update := bson.M{
 		"$set": bson.M{
 			"email":   user.Email,
@@ -56,7 +79,7 @@ func (r *UserRepository) UpsertByGaID(ctx context.Context, user *entity.User) (*
 // FindByEmail finds a user by email.
 func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
 	var user entity.User
-	err := r.collection.FindOne(ctx, bson.M{"email": email}).Decode(&user)
+	err := r.collection.FindOne(ctx, scoped(ctx, bson.M{"email": email})).Decode(&user)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, nil
@@ -69,7 +92,7 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*entity
 // FindByUserID finds a user by their MongoDB ObjectID.
 func (r *UserRepository) FindByUserID(ctx context.Context, userID bson.ObjectID) (*entity.User, error) {
 	var user entity.User
-	err := r.collection.FindOne(ctx, bson.M{"_id": userID}).Decode(&user)
+	err := r.collection.FindOne(ctx, scoped(ctx, bson.M{"_id": userID})).Decode(&user)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, nil
@@ -78,3 +101,132 @@ func (r *UserRepository) FindByUserID(ctx context.Context, userID bson.ObjectID)
 	}
 	return &user, nil
 }
+
+// FindBirthYears returns birth_year keyed by user_id (hex) for all users who set one.
+func (r *UserRepository) FindBirthYears(ctx context.Context) (map[string]int, error) {
+	opts := options.Find().SetProjection(bson.M{"_id": 1, "birth_year": 1})
+	cursor, err := r.collection.Find(ctx, scoped(ctx, bson.M{"birth_year": bson.M{"$gt": 0}}), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user birth years: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	out := make(map[string]int)
+	for cursor.Next(ctx) {
+		var user entity.User
+		if err := cursor.Decode(&user); err != nil {
+			return nil, fmt.Errorf("failed to decode user: %w", err)
+		}
+		out[user.UserID.Hex()] = user.BirthYear
+	}
+	return out, cursor.Err()
+}
+
+// SetTimezone stores the user's IANA timezone.
+func (r *UserRepository) SetTimezone(ctx context.Context, userID bson.ObjectID, timezone string) error {
+	_, err := r.collection.UpdateOne(ctx, scoped(ctx, bson.M{"_id": userID}), bson.M{"$set": bson.M{"timezone": timezone}})
+	if err != nil {
+		return fmt.Errorf("failed to set user timezone: %w", err)
+	}
+	return nil
+}
+
+// UpdateProfile sets and unsets the user's profile fields and returns the updated user, or nil if there is
+// no such user. Returns taken=true when another user of the tenant holds the display name.
+func (r *UserRepository) UpdateProfile(ctx context.Context, userID bson.ObjectID, set bson.M, unset []string) (user *entity.User, taken bool, err error) {
+	update := bson.M{}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(unset) > 0 {
+		fields := bson.M{}
+		for _, f := range unset {
+			fields[f] = ""
+		}
+		update["$unset"] = fields
+	}
+	if len(update) == 0 {
+		user, err = r.FindByUserID(ctx, userID)
+		return user, false, err
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var result entity.User
+	err = r.collection.FindOneAndUpdate(ctx, scoped(ctx, bson.M{"_id": userID}), update, opts).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, false, nil
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, true, nil
+		}
+		return nil, false, fmt.Errorf("failed to update user profile: %w", err)
+	}
+	return &result, false, nil
+}
+
+// AddXP adds delta to the user's xp and returns the updated user.
+func (r *UserRepository) AddXP(ctx context.Context, userID bson.ObjectID, delta int64) (*entity.User, error) {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var user entity.User
+	err := r.collection.FindOneAndUpdate(ctx, scoped(ctx, bson.M{"_id": userID}), bson.M{"$inc": bson.M{"xp": delta}}, opts).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to add user xp: %w", err)
+	}
+	return &user, nil
+}
+
+// SetLevel stores the level derived from the user's xp.
+func (r *UserRepository) SetLevel(ctx context.Context, userID bson.ObjectID, level int) error {
+	_, err := r.collection.UpdateOne(ctx, scoped(ctx, bson.M{"_id": userID}), bson.M{"$set": bson.M{"level": level}})
+	if err != nil {
+		return fmt.Errorf("failed to set user level: %w", err)
+	}
+	return nil
+}
+
+// FindByUserIDs returns the users with the given ids; unknown ids are skipped.
+func (r *UserRepository) FindByUserIDs(ctx context.Context, userIDs []bson.ObjectID) ([]*entity.User, error) {
+	cursor, err := r.collection.Find(ctx, scoped(ctx, bson.M{"_id": bson.M{"$in": userIDs}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find users: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	out := []*entity.User{}
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, fmt.Errorf("failed to decode users: %w", err)
+	}
+	return out, nil
+}
+
+// FindByFriendCode finds a user by friend code.
+func (r *UserRepository) FindByFriendCode(ctx context.Context, code string) (*entity.User, error) {
+	var user entity.User
+	err := r.collection.FindOne(ctx, scoped(ctx, bson.M{"friend_code": code})).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find user by friend code: %w", err)
+	}
+	return &user, nil
+}
+
+// SetFriendCode stores code as the user's friend code unless they already have one. Returns
+// taken=true when another user holds the code.
+func (r *UserRepository) SetFriendCode(ctx context.Context, userID bson.ObjectID, code string) (taken bool, err error) {
+	_, err = r.collection.UpdateOne(ctx,
+		scoped(ctx, bson.M{"_id": userID, "friend_code": bson.M{"$exists": false}}),
+		bson.M{"$set": bson.M{"friend_code": code}})
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return true, nil
+		}
+		return false, fmt.Errorf("failed to set user friend code: %w", err)
+	}
+	return false, nil
+}
@@ -10,6 +10,8 @@ import (
 	"brainbash_backend/config"
 	controller "brainbash_backend/internal/controller/http"
 	"brainbash_backend/internal/middleware"
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/tenant"
 )
 
 var (
@@ -41,15 +43,28 @@ func initEngine(cfg *config.AppConfig, middlewares ...gin.HandlerFunc) {
 func Init(cfg *config.AppConfig) {
 	initEngine(cfg)
 
-	// CORS must be registered before any routes
-	router.Use(middleware.CORSMiddleware())
-
 	controllers := controller.NewControllers(cfg)
 
+	// Tenant resolution and CORS must be registered before any routes
+	tenantHeader := cfg.StaticConfig.Tenants.Header
+	router.Use(middleware.TenantMiddleware(controllers.Tenants, tenantHeader), middleware.CORSMiddleware(tenantHeader))
+
 	// Public routes (no auth required)
 	router.GET("/health", controllers.HealthController.Health)
-	router.GET("/api/dashboard", controllers.DashboardController.GetDashboard)
+	router.GET("/api/games", controllers.GameController.Catalog)
+	publicLeaderboards := middleware.LeaderboardVisibility(tenant.LeaderboardsPublic)
+	groupLeaderboards := middleware.LeaderboardVisibility(tenant.LeaderboardsGroup)
+	router.GET("/api/dashboard", publicLeaderboards, controllers.DashboardController.GetDashboard)
+	router.GET("/api/dashboard/composite", publicLeaderboards, controllers.DashboardController.GetCompositeLeaderboard)
+	router.GET("/api/dashboard/ratings", publicLeaderboards, controllers.RatingController.Leaderboard)
 	router.POST("/api/game/guest/result", controllers.ScoreController.GameCalculate)
+	router.GET("/api/challenge", controllers.ChallengeController.List)
+	router.GET("/api/challenge/:challenge_id", controllers.ChallengeController.Get)
+	router.GET("/api/challenge/:challenge_id/leaderboard", publicLeaderboards, controllers.ChallengeController.Leaderboard)
+	router.GET("/api/tournaments", controllers.TournamentController.List)
+	router.GET("/api/tournaments/:tournament_id", controllers.TournamentController.Get)
+	router.GET("/api/tournaments/:tournament_id/standings", controllers.TournamentController.Standings)
+	router.GET("/api/tournaments/:tournament_id/pairings", controllers.TournamentController.Pairings)
 	router.DELETE("/api/admin/cleanup", controllers.CleanupController.CleanupByDateRange)
 	router.POST("/auth/google", controllers.AuthController.GoogleLogin)
 
@@ -64,7 +79,97 @@ func Init(cfg *config.AppConfig) {
 	{
 		authorized.GET("/auth/me", controllers.AuthController.Me)
 		authorized.POST("/api/game/result", controllers.ScoreController.GameResult)
+		authorized.POST("/api/game/results/batch", controllers.ScoreController.GameResultBatch)
 		authorized.GET("/api/user/stats", controllers.ScoreController.UserStats)
+		authorized.PATCH("/api/user/profile", controllers.UserController.UpdateProfile)
+		authorized.GET("/api/user/profile/cognitive", controllers.ProfileController.Cognitive)
+		authorized.GET("/api/user/history", controllers.HistoryController.GetHistory)
+		authorized.GET("/api/user/streak", controllers.StreakController.GetStreak)
+		authorized.GET("/api/user/achievements", controllers.AchievementController.List)
+		authorized.GET("/api/user/recommendations", controllers.RecommendationController.GetRecommendations)
+		authorized.PUT("/api/user/timezone", controllers.StreakController.SetTimezone)
+		authorized.GET("/api/user/goals", controllers.GoalController.List)
+		authorized.POST("/api/user/goals", controllers.GoalController.Create)
+		authorized.GET("/api/user/goals/:goal_id", controllers.GoalController.Get)
+		authorized.PATCH("/api/user/goals/:goal_id", controllers.GoalController.Update)
+		authorized.DELETE("/api/user/goals/:goal_id", controllers.GoalController.Delete)
+		authorized.GET("/api/user/digests", controllers.DigestController.List)
+		authorized.GET("/api/user/digests/:week_start", controllers.DigestController.Get)
+		authorized.GET("/api/user/notifications", controllers.NotificationController.List)
+		authorized.GET("/api/user/notifications/preferences", controllers.NotificationController.GetPreferences)
+		authorized.PUT("/api/user/notifications/preferences", controllers.NotificationController.UpdatePreferences)
+		authorized.GET("/api/user/sessions", controllers.SessionController.ListSessions)
+		authorized.GET("/api/user/sessions/:session_id", controllers.SessionController.GetSession)
+		authorized.GET("/api/user/friend-code", controllers.SocialController.FriendCode)
+		authorized.GET("/api/dashboard/friends", groupLeaderboards, controllers.SocialController.FriendsLeaderboard)
+		authorized.GET("/api/friends", controllers.SocialController.Connections)
+		authorized.DELETE("/api/friends/:user_id", controllers.SocialController.Unfriend)
+		authorized.GET("/api/friends/code/:code", controllers.SocialController.LookupFriendCode)
+		authorized.GET("/api/friends/requests", controllers.SocialController.ListRequests)
+		authorized.POST("/api/friends/requests", controllers.SocialController.SendRequest)
+		authorized.POST("/api/friends/requests/:user_id/accept", controllers.SocialController.AcceptRequest)
+		authorized.POST("/api/friends/requests/:user_id/decline", controllers.SocialController.DeclineRequest)
+		authorized.DELETE("/api/friends/requests/:user_id", controllers.SocialController.CancelRequest)
+		authorized.PUT("/api/follows/:user_id", controllers.SocialController.Follow)
+		authorized.DELETE("/api/follows/:user_id", controllers.SocialController.Unfollow)
+		authorized.GET("/api/blocks", controllers.SocialController.ListBlocked)
+		authorized.PUT("/api/blocks/:user_id", controllers.SocialController.Block)
+		authorized.DELETE("/api/blocks/:user_id", controllers.SocialController.Unblock)
+		authorized.GET("/api/user/ratings/history", controllers.RatingController.History)
+		authorized.GET("/api/user/duels/record", controllers.DuelController.Record)
+		authorized.GET("/api/duels", controllers.DuelController.List)
+		authorized.POST("/api/duels", controllers.DuelController.Create)
+		authorized.GET("/api/duels/:duel_id", controllers.DuelController.Get)
+		authorized.POST("/api/duels/:duel_id/accept", controllers.DuelController.Accept)
+		authorized.POST("/api/duels/:duel_id/decline", controllers.DuelController.Decline)
+		authorized.POST("/api/duels/:duel_id/result", controllers.DuelController.Submit)
+		authorized.GET("/api/matches", controllers.MatchController.List)
+		authorized.GET("/api/matches/play", controllers.MatchController.Play)
+		authorized.GET("/api/matches/:match_id", controllers.MatchController.Get)
+		authorized.POST("/api/tournaments/:tournament_id/join", controllers.TournamentController.Join)
+		authorized.DELETE("/api/tournaments/:tournament_id/join", controllers.TournamentController.Leave)
+		authorized.GET("/api/tournaments/:tournament_id/pairing", controllers.TournamentController.Pairing)
+		authorized.POST("/api/tournaments/:tournament_id/result", controllers.TournamentController.Submit)
+		authorized.GET("/api/challenge/today", controllers.ChallengeController.Today)
+		authorized.POST("/api/challenge/today/result", controllers.ChallengeController.Submit)
+
+		// Group routes check the caller's role in :group_id; non-members get 404
+		groups := controllers.GroupController
+		groupMember := groups.RequireRole(entity.GroupRoleMember)
+		groupAdmin := groups.RequireRole(entity.GroupRoleAdmin)
+		groupOwner := groups.RequireRole(entity.GroupRoleOwner)
+		authorized.GET("/api/groups", groups.List)
+		authorized.POST("/api/groups", groups.Create)
+		authorized.POST("/api/groups/join", groups.Join)
+		authorized.GET("/api/groups/:group_id", groupMember, groups.Get)
+		authorized.DELETE("/api/groups/:group_id", groupOwner, groups.Delete)
+		authorized.POST("/api/groups/:group_id/code", groupAdmin, groups.RegenerateCode)
+		authorized.GET("/api/groups/:group_id/members", groupMember, groups.Members)
+		authorized.PUT("/api/groups/:group_id/members/:user_id/role", groupOwner, groups.SetRole)
+		authorized.DELETE("/api/groups/:group_id/members/:user_id", groupAdmin, groups.RemoveMember)
+		authorized.DELETE("/api/groups/:group_id/membership", groupMember, groups.Leave)
+		authorized.GET("/api/groups/:group_id/leaderboard", groupLeaderboards, groupMember, groups.Leaderboard)
+		authorized.GET("/api/groups/:group_id/progress", groupOwner, groups.Progress)
+		authorized.GET("/api/groups/:group_id/report", groupOwner, groups.Report)
+
+		// Settings of the request's tenant, for its admins
+		tenantAdmin := middleware.TenantAdminMiddleware()
+		authorized.GET("/api/tenant/settings", tenantAdmin, controllers.TenantController.GetSettings)
+		authorized.PUT("/api/tenant/settings", tenantAdmin, controllers.TenantController.UpdateSettings)
+	}
+
+	// Admin routes (JWT auth + admin user_id required)
+	admin := router.Group("/api/admin")
+	admin.Use(middleware.AuthMiddleware(cfg.StaticConfig.Auth.JWTSecret), middleware.AdminMiddleware(cfg.StaticConfig.Auth.AdminUserIDs))
+	{
+		admin.GET("/flags", controllers.ReviewController.ListFlags)
+		admin.POST("/flags/:flag_id/approve", controllers.ReviewController.Approve)
+		admin.POST("/flags/:flag_id/reject", controllers.ReviewController.Reject)
+		admin.POST("/sessions/backfill", controllers.SessionController.Backfill)
+		admin.POST("/tournaments", controllers.TournamentController.Create)
+		admin.POST("/tournaments/:tournament_id/cancel", controllers.TournamentController.Cancel)
+		admin.GET("/tenants/:tenant_id/settings", controllers.TenantController.GetSettings)
+		admin.PUT("/tenants/:tenant_id/settings", controllers.TenantController.UpdateSettings)
 	}
 }
 
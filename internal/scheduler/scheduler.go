@@ -0,0 +1,102 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// job is a named function run periodically in the background.
+type job struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context) error
+}
+
+var (
+	mu      sync.Mutex
+	jobs    []job
+	runCtx  context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	started bool
+)
+
+// Every registers a job that runs once when the scheduler starts and then every interval.
+// Jobs registered after Start are started immediately. Non-positive intervals are ignored.
+func Every(name string, interval time.Duration, run func(ctx context.Context) error) {
+	if interval <= 0 {
+		log.Printf("Scheduler: job %s not registered (interval %v)", name, interval)
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+
+	j := job{name: name, interval: interval, run: run}
+	jobs = append(jobs, j)
+	if started {
+		startJob(j)
+	}
+}
+
+// Start runs all registered jobs in background goroutines until Stop is called.
+// Safe to call multiple times; only the first call takes effect.
+func Start() {
+	mu.Lock()
+	defer mu.Unlock()
+	if started {
+		return
+	}
+
+	runCtx, cancel = context.WithCancel(context.Background())
+	started = true
+	for _, j := range jobs {
+		startJob(j)
+	}
+	log.Printf("Scheduler started with %d jobs", len(jobs))
+}
+
+// Stop cancels all jobs and waits for running ones to return (or ctx to expire).
+func Stop(ctx context.Context) {
+	mu.Lock()
+	if !started {
+		mu.Unlock()
+		return
+	}
+	cancel()
+	started = false
+	mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		log.Printf("Scheduler: stop timed out: %v", ctx.Err())
+	}
+}
+
+// startJob runs j in a goroutine until runCtx is cancelled. Caller must hold mu.
+func startJob(j job) {
+	ctx := runCtx
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+		for {
+			if err := j.run(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("Scheduler: job %s failed: %v", j.name, err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
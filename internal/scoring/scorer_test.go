@@ -0,0 +1,90 @@
+package scoring
+
+import (
+	"math"
+	"testing"
+
+	"brainbash_backend/internal/model/request"
+)
+
+func TestScorerCalculate(t *testing.T) {
+	tests := []struct {
+		name      string
+		strategy  string
+		responses []request.QuestionResponse
+		want      ScoreResult
+	}{
+		{
+			name:     "timed outcome without responses",
+			strategy: StrategyTimedOutcome,
+			want:     ScoreResult{},
+		},
+		{
+			name:     "timed outcome all correct",
+			strategy: StrategyTimedOutcome,
+			responses: []request.QuestionResponse{
+				{TimeTaken: 1, Outcome: OutcomeCorrect},
+				{TimeTaken: 3, Outcome: OutcomeCorrect},
+			},
+			want: ScoreResult{Score: 100, Questions: 2, Correct: 2, Accuracy: 1, AvgTime: 2},
+		},
+		{
+			name:     "timed outcome counts only correct answers",
+			strategy: StrategyTimedOutcome,
+			responses: []request.QuestionResponse{
+				{TimeTaken: 2, Outcome: OutcomeCorrect},
+				{TimeTaken: 4, Outcome: OutcomeIncorrect},
+				{TimeTaken: 6, Outcome: OutcomeUnsolved},
+				{TimeTaken: 8, Outcome: OutcomeCorrect},
+			},
+			want: ScoreResult{Score: 50, Questions: 4, Correct: 2, Accuracy: 0.5, AvgTime: 5},
+		},
+		{
+			name:     "timed outcome none correct",
+			strategy: StrategyTimedOutcome,
+			responses: []request.QuestionResponse{
+				{TimeTaken: 1.5, Outcome: OutcomeIncorrect},
+			},
+			want: ScoreResult{Score: 0, Questions: 1, Correct: 0, Accuracy: 0, AvgTime: 1.5},
+		},
+		{
+			name:     "sequential time without responses",
+			strategy: StrategySequentialTime,
+			want:     ScoreResult{},
+		},
+		{
+			name:     "sequential time counts every response as solved",
+			strategy: StrategySequentialTime,
+			responses: []request.QuestionResponse{
+				{TimeTaken: 0.5},
+				{TimeTaken: 1.5},
+				{TimeTaken: 4},
+			},
+			want: ScoreResult{Score: 100, Questions: 3, Correct: 3, Accuracy: 1, AvgTime: 2},
+		},
+	}
+
+	sc := NewScorer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sc.Calculate(tt.strategy, tt.responses)
+			if err != nil {
+				t.Fatalf("Calculate: %v", err)
+			}
+			if got.Questions != tt.want.Questions || got.Correct != tt.want.Correct ||
+				!approx(got.Score, tt.want.Score) || !approx(got.Accuracy, tt.want.Accuracy) || !approx(got.AvgTime, tt.want.AvgTime) {
+				t.Errorf("Calculate = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScorerCalculateUnknownStrategy(t *testing.T) {
+	if _, err := NewScorer().Calculate("no_such_strategy", nil); err == nil {
+		t.Fatal("Calculate with an unknown strategy: want error, got nil")
+	}
+}
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
@@ -20,3 +20,10 @@ const (
 	StrategyTimedOutcome   = "timed_outcome"   // questions have outcome: correct/incorrect/unsolved
 	StrategySequentialTime = "sequential_time" // next question only after previous solved; only time_taken
 )
+
+// Outcomes accepted in question_responses for the timed_outcome strategy.
+const (
+	OutcomeCorrect   = "correct"
+	OutcomeIncorrect = "incorrect"
+	OutcomeUnsolved  = "unsolved"
+)
@@ -2,8 +2,6 @@ package scoring
 
 import "brainbash_backend/internal/model/request"
 
-const outcomeCorrect = "correct"
-
 // TimedOutcomeStrategy scores based on time_taken and outcome (correct/incorrect/unsolved).
 // Score out of 100 is driven by accuracy; avgTime is average time per question.
 type TimedOutcomeStrategy struct{}
@@ -28,7 +26,7 @@ func (s *TimedOutcomeStrategy) Calculate(responses []request.QuestionResponse) *
 	var totalTime float64
 	for _, r := range responses {
 		totalTime += r.TimeTaken
-		if r.Outcome == outcomeCorrect {
+		if r.Outcome == OutcomeCorrect {
 			correct++
 		}
 	}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"log"
+	"slices"
+	"time"
+
+	"brainbash_backend/config"
+	"brainbash_backend/internal/event"
+	"brainbash_backend/internal/game"
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/repository"
+)
+
+// Achievement rule kinds (config achievements.rules[].kind). Session counts and domains played only include
+// leaderboard-eligible sessions, so flagged sessions awaiting review unlock nothing.
+const (
+	AchievementAccuracy         = "accuracy"           // session accuracy >= threshold (0–1)
+	AchievementScore            = "score"              // session score >= threshold
+	AchievementSessionsTotal    = "sessions_total"     // sessions played >= threshold
+	AchievementSessionsInWindow = "sessions_in_window" // sessions within window before the session >= threshold
+	AchievementLeaderboardRank  = "leaderboard_rank"   // dashboard rank <= threshold
+	AchievementDomainsPlayed    = "domains_played"     // distinct game types played >= threshold
+)
+
+// ruleEvents maps each rule kind to the event it is evaluated on.
+var ruleEvents = map[string]event.Type{
+	AchievementAccuracy:         event.SessionStored,
+	AchievementScore:            event.SessionStored,
+	AchievementSessionsTotal:    event.SessionStored,
+	AchievementSessionsInWindow: event.SessionStored,
+	AchievementLeaderboardRank:  event.LeaderboardEntered,
+	AchievementDomainsPlayed:    event.SessionStored,
+}
+
+// AchievementStatus is one configured achievement and whether the user unlocked it.
+type AchievementStatus struct {
+	ID          string
+	Name        string
+	Description string
+	UnlockedAt  *time.Time // nil while locked
+}
+
+// AchievementService evaluates the configured achievement rules against session and leaderboard events
+// and persists unlocks.
+type AchievementService struct {
+	achievementRepo *repository.AchievementRepository
+	sessionRepo     *repository.SessionRepository
+	scoreRepo       *repository.ScoreRepository
+	rules           []config.AchievementRule
+}
+
+// NewAchievementService creates a new AchievementService. Rules with an unknown kind or game type,
+// or without an id, are logged and ignored.
+func NewAchievementService(achievementRepo *repository.AchievementRepository, sessionRepo *repository.SessionRepository, scoreRepo *repository.ScoreRepository, cfg config.AchievementsConfig) *AchievementService {
+	rules := make([]config.AchievementRule, 0, len(cfg.Rules))
+	seen := make(map[string]bool)
+	for _, r := range cfg.Rules {
+		_, known := ruleEvents[r.Kind]
+		switch {
+		case r.ID == "" || seen[r.ID]:
+			log.Printf("Achievements: skipping rule with missing or duplicate id %q", r.ID)
+		case !known:
+			log.Printf("Achievements: skipping rule %s with unknown kind %q", r.ID, r.Kind)
+		case r.GameType != "" && !game.GameType(r.GameType).IsValid():
+			log.Printf("Achievements: skipping rule %s with unknown game_type %q", r.ID, r.GameType)
+		default:
+			seen[r.ID] = true
+			rules = append(rules, r)
+		}
+	}
+	return &AchievementService{
+		achievementRepo: achievementRepo,
+		sessionRepo:     sessionRepo,
+		scoreRepo:       scoreRepo,
+		rules:           rules,
+	}
+}
+
+// Subscribe registers the service for the events its rules are evaluated on.
+func (s *AchievementService) Subscribe() {
+	event.Subscribe(event.SessionStored, "achievements", s.handle)
+	event.Subscribe(event.LeaderboardEntered, "achievements", s.handle)
+}
+
+// List returns every configured achievement with the user's unlock time, in config order.
+func (s *AchievementService) List(ctx context.Context, userID string) ([]AchievementStatus, error) {
+	unlocks, err := s.achievementRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	unlockedAt := make(map[string]time.Time, len(unlocks))
+	for _, u := range unlocks {
+		unlockedAt[u.AchievementID] = u.UnlockedAt
+	}
+
+	out := make([]AchievementStatus, 0, len(s.rules))
+	for _, r := range s.rules {
+		status := AchievementStatus{ID: r.ID, Name: r.Name, Description: r.Description}
+		if t, ok := unlockedAt[r.ID]; ok {
+			status.UnlockedAt = &t
+		}
+		out = append(out, status)
+	}
+	return out, nil
+}
+
+// Rule returns the configured achievement with the given id.
+func (s *AchievementService) Rule(id string) (config.AchievementRule, bool) {
+	for _, r := range s.rules {
+		if r.ID == id {
+			return r, true
+		}
+	}
+	return config.AchievementRule{}, false
+}
+
+// handle unlocks every still-locked achievement whose rule the event satisfies.
+func (s *AchievementService) handle(ctx context.Context, e event.Event) error {
+	unlocks, err := s.achievementRepo.FindByUserID(ctx, e.UserID)
+	if err != nil {
+		return err
+	}
+	unlocked := make(map[string]bool, len(unlocks))
+	for _, u := range unlocks {
+		unlocked[u.AchievementID] = true
+	}
+
+	for _, r := range s.rules {
+		if unlocked[r.ID] || ruleEvents[r.Kind] != e.Type || (r.GameType != "" && r.GameType != e.GameType) {
+			continue
+		}
+		ok, err := s.satisfied(ctx, r, e)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		inserted, err := s.achievementRepo.Insert(ctx, &entity.Achievement{
+			ID:            entity.AchievementDocID(e.UserID, r.ID),
+			UserID:        e.UserID,
+			AchievementID: r.ID,
+			SessionID:     e.SessionID,
+			UnlockedAt:    time.Now().UTC(),
+		})
+		if err != nil {
+			return err
+		}
+		if inserted {
+			log.Printf("Achievements: user %s unlocked %s", e.UserID, r.ID)
+			event.Publish(ctx, event.Event{
+				Type:      event.AchievementUnlocked,
+				UserID:    e.UserID,
+				GameType:  e.GameType,
+				SessionID: e.SessionID,
+				Timestamp: time.Now().UTC(),
+				RefID:     r.ID,
+			})
+		}
+	}
+	return nil
+}
+
+func (s *AchievementService) satisfied(ctx context.Context, r config.AchievementRule, e event.Event) (bool, error) {
+	switch r.Kind {
+	case AchievementAccuracy:
+		return e.SessionScore.Accuracy >= r.Threshold, nil
+	case AchievementScore:
+		return e.SessionScore.Score >= r.Threshold, nil
+	case AchievementLeaderboardRank:
+		return e.Rank > 0 && float64(e.Rank) <= r.Threshold, nil
+	case AchievementSessionsTotal:
+		n, err := s.sessionRepo.CountEligibleInRange(ctx, e.UserID, r.GameType, time.Time{}, e.Timestamp)
+		return float64(n) >= r.Threshold, err
+	case AchievementSessionsInWindow:
+		n, err := s.sessionRepo.CountEligibleInRange(ctx, e.UserID, r.GameType, e.Timestamp.Add(-r.Window), e.Timestamp)
+		return float64(n) >= r.Threshold, err
+	case AchievementDomainsPlayed:
+		score, err := s.scoreRepo.FindByUserID(ctx, e.UserID)
+		if err != nil || score == nil {
+			return false, err
+		}
+		played := 0
+		for _, gt := range game.AllGameTypes {
+			if gts := getGameTypeScore(score, string(gt)); gts != nil && slices.ContainsFunc(gts.Sessions, func(se entity.Session) bool { return se.LeaderboardEligible() }) {
+				played++
+			}
+		}
+		return float64(played) >= r.Threshold, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"brainbash_backend/internal/game"
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/model/request"
+	"brainbash_backend/internal/repository"
+	"brainbash_backend/internal/tenant"
+)
+
+// challengeSubmitGrace is how long after UTC midnight an attempt at yesterday's challenge is still accepted,
+// so a game started just before midnight can be submitted.
+const challengeSubmitGrace = 15 * time.Minute
+
+var (
+	// ErrChallengeNotFound is returned when no challenge exists for the requested date.
+	ErrChallengeNotFound = errors.New("challenge not found")
+	// ErrChallengeClosed is returned when submitting to a challenge that is no longer running.
+	ErrChallengeClosed = errors.New("challenge is closed")
+	// ErrChallengeAttempted is returned when the user already used their ranked attempt.
+	ErrChallengeAttempted = errors.New("challenge already attempted today")
+)
+
+// ChallengeEntry is one row of a challenge leaderboard.
+type ChallengeEntry struct {
+	Attempt *entity.ChallengeAttempt
+	User    *entity.User
+}
+
+// ChallengeService runs the daily challenge: one seed per UTC day, one game type per day and tenant,
+// one ranked attempt per user, and a leaderboard per challenge and tenant.
+type ChallengeService struct {
+	challengeRepo *repository.ChallengeRepository
+	attemptRepo   *repository.ChallengeAttemptRepository
+	scoreService  *ScoreService
+	userService   *UserService
+	tenants       *TenantService
+}
+
+// NewChallengeService creates a new ChallengeService.
+func NewChallengeService(challengeRepo *repository.ChallengeRepository, attemptRepo *repository.ChallengeAttemptRepository, scoreService *ScoreService, userService *UserService, tenants *TenantService) *ChallengeService {
+	return &ChallengeService{
+		challengeRepo: challengeRepo,
+		attemptRepo:   attemptRepo,
+		scoreService:  scoreService,
+		userService:   userService,
+		tenants:       tenants,
+	}
+}
+
+// Today returns the current UTC day's challenge, picking its seed and the default tenant's game type on
+// first access, and the game type of the tenant in ctx on that tenant's first access.
+func (s *ChallengeService) Today(ctx context.Context) (*entity.Challenge, error) {
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	challenge, err := s.challengeRepo.GetOrCreate(ctx, &entity.Challenge{
+		ID:        entity.ChallengeID(start),
+		GameType:  pickGameType(s.tenants.Get(ctx, tenant.DefaultID)),
+		Seed:      rand.Int64(),
+		StartsAt:  start,
+		EndsAt:    start.AddDate(0, 0, 1),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	t := tenant.FromContext(ctx)
+	if t != nil && !t.IsDefault() {
+		if _, ok := challenge.TenantGameTypes[t.ID]; !ok {
+			if err := s.challengeRepo.SetTenantGameType(ctx, challenge.ID, t.ID, pickGameType(t)); err != nil {
+				return nil, err
+			}
+			// Re-read: a concurrent first access may have stored its pick first
+			if challenge, err = s.challengeRepo.FindByID(ctx, challenge.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return forTenant(ctx, challenge), nil
+}
+
+// Get returns the challenge with the given ID (YYYY-MM-DD, or "today"). Future challenges are not revealed.
+func (s *ChallengeService) Get(ctx context.Context, challengeID string) (*entity.Challenge, error) {
+	if challengeID == "today" || challengeID == entity.ChallengeID(time.Now()) {
+		return s.Today(ctx)
+	}
+	challenge, err := s.challengeRepo.FindByID(ctx, challengeID)
+	if err != nil {
+		return nil, err
+	}
+	if challenge == nil || challenge.StartsAt.After(time.Now()) {
+		return nil, ErrChallengeNotFound
+	}
+	return forTenant(ctx, challenge), nil
+}
+
+// ListPast returns up to limit challenges before the given date (YYYY-MM-DD), newest first.
+// before "" lists from yesterday backwards.
+func (s *ChallengeService) ListPast(ctx context.Context, before string, limit int64) ([]*entity.Challenge, error) {
+	today := entity.ChallengeID(time.Now())
+	if before == "" || before > today {
+		before = today
+	}
+	challenges, err := s.challengeRepo.FindBefore(ctx, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	for i, challenge := range challenges {
+		challenges[i] = forTenant(ctx, challenge)
+	}
+	return challenges, nil
+}
+
+// GetAttempt returns the user's attempt at the challenge and its leaderboard rank (0 while flagged),
+// or nil when the user has not attempted it.
+func (s *ChallengeService) GetAttempt(ctx context.Context, challengeID, userID string) (*entity.ChallengeAttempt, int64, error) {
+	attempt, err := s.attemptRepo.FindByID(ctx, entity.ChallengeAttemptID(challengeID, userID))
+	if err != nil || attempt == nil {
+		return nil, 0, err
+	}
+	if attempt.Flagged {
+		return attempt, 0, nil
+	}
+	rank, err := s.attemptRepo.Rank(ctx, attempt)
+	if err != nil {
+		return nil, 0, err
+	}
+	return attempt, rank, nil
+}
+
+// Submit stores the user's ranked attempt at a challenge (today's when req.ChallengeID is empty).
+// The session is scored like a regular game of the challenge's game type and tagged with the challenge.
+// Retrying the same submission replays it (replayed=true); any other second attempt is ErrChallengeAttempted.
+func (s *ChallengeService) Submit(ctx context.Context, userID string, req request.ChallengeResultRequest) (*entity.ChallengeAttempt, *entity.GameResult, bool, error) {
+	challenge, err := s.openChallenge(ctx, req.ChallengeID)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	attemptID := entity.ChallengeAttemptID(challenge.ID, userID)
+	existing, err := s.attemptRepo.FindByID(ctx, attemptID)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if existing != nil && (req.SessionID == "" || existing.SessionID != req.SessionID) {
+		return nil, nil, false, ErrChallengeAttempted
+	}
+
+	// One idempotency key per user and challenge serialises concurrent attempts and makes retries replay
+	result, replayed, err := s.scoreService.SubmitTaggedResult(ctx, userID, "challenge:"+challenge.ID, request.GameResultRequest{
+		GameType:          challenge.GameType,
+		QuestionResponses: req.QuestionResponses,
+		SessionID:         req.SessionID,
+	}, entity.SessionTags{ChallengeID: challenge.ID})
+	if err != nil {
+		if errors.Is(err, ErrIdempotencyKeyReused) {
+			return nil, nil, false, ErrChallengeAttempted
+		}
+		return nil, nil, false, err
+	}
+
+	attempt := &entity.ChallengeAttempt{
+		ID:           attemptID,
+		ChallengeID:  challenge.ID,
+		UserID:       userID,
+		SessionID:    result.SessionID,
+		SessionScore: result.SessionScore,
+		Flagged:      result.Flagged,
+		SubmittedAt:  time.Now().UTC(),
+	}
+	inserted, err := s.attemptRepo.Insert(ctx, attempt)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if !inserted {
+		// Stored by the original request this one replays
+		if attempt, err = s.attemptRepo.FindByID(ctx, attemptID); err != nil {
+			return nil, nil, false, err
+		}
+		replayed = true
+	}
+	return attempt, result, replayed, nil
+}
+
+// Leaderboard returns the challenge and its limit best unflagged attempts. Users that no longer exist are skipped.
+func (s *ChallengeService) Leaderboard(ctx context.Context, challengeID string, limit int64) (*entity.Challenge, []ChallengeEntry, error) {
+	challenge, err := s.Get(ctx, challengeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	attempts, err := s.attemptRepo.FindTop(ctx, challenge.ID, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	out := make([]ChallengeEntry, 0, len(attempts))
+	for _, attempt := range attempts {
+		user, err := s.userService.FindByUserID(ctx, attempt.UserID)
+		if err != nil || user == nil {
+			continue
+		}
+		out = append(out, ChallengeEntry{Attempt: attempt, User: user})
+	}
+	return challenge, out, nil
+}
+
+// openChallenge returns the challenge accepting attempts under challengeID: today's, or yesterday's
+// during the grace period after midnight.
+func (s *ChallengeService) openChallenge(ctx context.Context, challengeID string) (*entity.Challenge, error) {
+	now := time.Now().UTC()
+	if challengeID == "" || challengeID == entity.ChallengeID(now) {
+		return s.Today(ctx)
+	}
+	if challengeID != entity.ChallengeID(now.Add(-challengeSubmitGrace)) {
+		return nil, ErrChallengeClosed
+	}
+	challenge, err := s.challengeRepo.FindByID(ctx, challengeID)
+	if err != nil {
+		return nil, err
+	}
+	if challenge == nil {
+		return nil, ErrChallengeNotFound
+	}
+	return forTenant(ctx, challenge), nil
+}
+
+// forTenant returns challenge with GameType set to what the tenant in ctx plays in it.
+func forTenant(ctx context.Context, challenge *entity.Challenge) *entity.Challenge {
+	t := tenant.FromContext(ctx)
+	if t == nil {
+		return challenge
+	}
+	out := *challenge
+	out.GameType = challenge.GameTypeFor(t.ID)
+	return &out
+}
+
+// pickGameType returns a random game type from the tenant's catalog (from every game type when t is nil).
+func pickGameType(t *tenant.Tenant) string {
+	catalog := game.AllGameTypes
+	if t != nil {
+		catalog = t.Catalog()
+	}
+	return string(catalog[rand.IntN(len(catalog))])
+}
@@ -2,30 +2,39 @@ package service
 
 import (
 	"context"
+	"log"
 	"time"
 
 	"brainbash_backend/internal/model/entity"
 	"brainbash_backend/internal/repository"
+	"brainbash_backend/internal/tenant"
 )
 
-// CleanupService removes sessions and dashboard entries within a date range.
+// CleanupService removes sessions and dashboard entries within a date range, and applies each tenant's
+// retention period.
 type CleanupService struct {
-	scoreRepo     *repository.ScoreRepository
-	dashboardRepo *repository.DashboardRepository
+	scoreRepo      *repository.ScoreRepository
+	sessionRepo    *repository.SessionRepository
+	dashboardRepo  *repository.DashboardRepository
+	profileService *ProfileService
+	tenants        *TenantService
 }
 
 // NewCleanupService creates a new CleanupService.
-func NewCleanupService(scoreRepo *repository.ScoreRepository, dashboardRepo *repository.DashboardRepository) *CleanupService {
+func NewCleanupService(scoreRepo *repository.ScoreRepository, sessionRepo *repository.SessionRepository, dashboardRepo *repository.DashboardRepository, profileService *ProfileService, tenants *TenantService) *CleanupService {
 	return &CleanupService{
-		scoreRepo:     scoreRepo,
-		dashboardRepo: dashboardRepo,
+		scoreRepo:      scoreRepo,
+		sessionRepo:    sessionRepo,
+		dashboardRepo:  dashboardRepo,
+		profileService: profileService,
+		tenants:        tenants,
 	}
 }
 
-// CleanupByDateRange removes from scores (sessions) and dashboard (entries) all data
+// CleanupByDateRange removes from scores (sessions), sessions and dashboard (entries) all data
 // whose timestamp falls within [start, end] (inclusive). For each affected user score,
 // per-game avg_score and high_score and overall_score are recomputed from remaining
-// sessions and the updated document is persisted.
+// sessions and the updated document is persisted. With a tenant in ctx only that tenant's data is removed.
 func (s *CleanupService) CleanupByDateRange(ctx context.Context, start, end time.Time) (scoresUpdated int, err error) {
 	scores, err := s.scoreRepo.FindAll(ctx)
 	if err != nil {
@@ -33,7 +42,7 @@ func (s *CleanupService) CleanupByDateRange(ctx context.Context, start, end time
 	}
 
 	for _, score := range scores {
-		changed := s.removeSessionsInDateRange(score, start, end)
+		changed := s.removeSessionsInDateRange(ctx, score, start, end)
 		if changed {
 			// Persist score with recomputed avg_score, high_score (per game) and overall_score
 			if err := s.scoreRepo.Upsert(ctx, score); err != nil {
@@ -43,6 +52,10 @@ func (s *CleanupService) CleanupByDateRange(ctx context.Context, start, end time
 		}
 	}
 
+	if err := s.sessionRepo.DeleteInDateRange(ctx, start, end); err != nil {
+		return scoresUpdated, err
+	}
+
 	if err := s.dashboardRepo.DeleteEntriesInDateRange(ctx, start, end); err != nil {
 		return scoresUpdated, err
 	}
@@ -50,16 +63,32 @@ func (s *CleanupService) CleanupByDateRange(ctx context.Context, start, end time
 	return scoresUpdated, nil
 }
 
+// ApplyRetention removes, for every tenant with a retention period, the data older than it. Run periodically.
+func (s *CleanupService) ApplyRetention(ctx context.Context) error {
+	now := time.Now().UTC()
+	for _, t := range s.tenants.All(ctx) {
+		if t.Retention <= 0 {
+			continue
+		}
+		updated, err := s.CleanupByDateRange(tenant.NewContext(ctx, t), time.Time{}, now.Add(-t.Retention))
+		if err != nil {
+			log.Printf("Cleanup: apply retention of tenant %s: %v", t.ID, err)
+			continue
+		}
+		if updated > 0 {
+			log.Printf("Cleanup: retention of tenant %s removed sessions of %d users", t.ID, updated)
+		}
+	}
+	return nil
+}
+
 // removeSessionsInDateRange filters out sessions in [start, end] from each game type,
 // then recomputes avg_score and high_score for each game type and overall_score from
 // the remaining sessions. The score struct is updated in place; caller must Upsert to persist.
 // Returns true if any session was removed.
-func (s *CleanupService) removeSessionsInDateRange(score *entity.Score, start, end time.Time) bool {
+func (s *CleanupService) removeSessionsInDateRange(ctx context.Context, score *entity.Score, start, end time.Time) bool {
 	anyChanged := false
-	for _, gt := range []*entity.GameTypeScore{
-		score.ProcessingSpeed, score.WorkingMemory, score.LogicalReasoning,
-		score.MathReasoning, score.ReflexTime, score.AttentionControl,
-	} {
+	for _, gt := range allGameTypeScores(score) {
 		if gt == nil {
 			continue
 		}
@@ -68,44 +97,10 @@ func (s *CleanupService) removeSessionsInDateRange(score *entity.Score, start, e
 			anyChanged = true
 		}
 		gt.Sessions = kept
-		// Recompute avg_score and high_score from remaining sessions
-		var sum float64
-		high := 0.0
-		for _, se := range gt.Sessions {
-			sum += se.SessionScore.Score
-			if se.SessionScore.Score > high {
-				high = se.SessionScore.Score
-			}
-		}
-		if len(gt.Sessions) > 0 {
-			gt.AvgScore = sum / float64(len(gt.Sessions))
-			gt.HighScore = high
-		} else {
-			gt.AvgScore = 0
-			gt.HighScore = 0
-		}
 	}
 
-	// Recompute overall_score (average of all remaining session scores across all game types)
-	var totalSum float64
-	var totalCount int
-	for _, gt := range []*entity.GameTypeScore{
-		score.ProcessingSpeed, score.WorkingMemory, score.LogicalReasoning,
-		score.MathReasoning, score.ReflexTime, score.AttentionControl,
-	} {
-		if gt == nil {
-			continue
-		}
-		for _, se := range gt.Sessions {
-			totalSum += se.SessionScore.Score
-			totalCount++
-		}
-	}
-	if totalCount > 0 {
-		score.OverallScore = totalSum / float64(totalCount)
-	} else {
-		score.OverallScore = 0
-	}
+	// Recompute avg_score, high_score and overall_score from remaining sessions
+	recomputeAggregates(ctx, score, s.profileService)
 
 	return anyChanged
 }
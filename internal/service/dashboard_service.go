@@ -5,6 +5,7 @@ import (
 	"sort"
 	"time"
 
+	"brainbash_backend/internal/event"
 	"brainbash_backend/internal/model/entity"
 	"brainbash_backend/internal/repository"
 )
@@ -12,17 +13,43 @@ import (
 // DashboardService provides dashboard (top-10 leaderboard) read and update.
 type DashboardService struct {
 	dashboardRepo *repository.DashboardRepository
+	scoreRepo     *repository.ScoreRepository
 	userService   *UserService
 }
 
 // NewDashboardService creates a new DashboardService.
-func NewDashboardService(dashboardRepo *repository.DashboardRepository, userService *UserService) *DashboardService {
+func NewDashboardService(dashboardRepo *repository.DashboardRepository, scoreRepo *repository.ScoreRepository, userService *UserService) *DashboardService {
 	return &DashboardService{
 		dashboardRepo: dashboardRepo,
+		scoreRepo:     scoreRepo,
 		userService:   userService,
 	}
 }
 
+// CompositeEntry is one user on the composite (overall_score) leaderboard.
+type CompositeEntry struct {
+	User         *entity.User
+	OverallScore float64
+}
+
+// GetCompositeLeaderboard returns the limit users with the highest overall_score (cognitive profile composite).
+// Users that no longer exist are skipped.
+func (s *DashboardService) GetCompositeLeaderboard(ctx context.Context, limit int64) ([]CompositeEntry, error) {
+	scores, err := s.scoreRepo.FindTopByOverall(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]CompositeEntry, 0, len(scores))
+	for _, score := range scores {
+		user, err := s.userService.FindByUserID(ctx, score.UserID)
+		if err != nil || user == nil {
+			continue
+		}
+		out = append(out, CompositeEntry{User: user, OverallScore: score.OverallScore})
+	}
+	return out, nil
+}
+
 // GetDashboard returns the full dashboard (top 10 per game type). Returns empty dashboard if not found.
 func (s *DashboardService) GetDashboard(ctx context.Context) (*entity.Dashboard, error) {
 	d, err := s.dashboardRepo.FindByID(ctx, entity.DashboardDocID)
@@ -48,9 +75,9 @@ func (s *DashboardService) MaybeUpdateTop10(ctx context.Context, gameType, userI
 		User: entity.DashboardUserSummary{
 			ID:    user.UserID.Hex(),
 			GaID:  user.GaID,
-			Name:  user.Name,
+			Name:  user.PublicName(),
 			Email: user.Email,
-			Photo: user.Picture,
+			Photo: user.PublicPicture(),
 		},
 		SessionScore: sessionScore,
 		Timestamp:    timestamp,
@@ -65,6 +92,7 @@ func (s *DashboardService) MaybeUpdateTop10(ctx context.Context, gameType, userI
 	}
 
 	list := getDashboardEntriesForGameType(d, gameType)
+	before := bestRanks(list)
 	list = append(list, entry)
 	sort.Slice(list, func(i, j int) bool {
 		return list[i].SessionScore.Score > list[j].SessionScore.Score
@@ -74,7 +102,56 @@ func (s *DashboardService) MaybeUpdateTop10(ctx context.Context, gameType, userI
 	}
 	setDashboardEntriesForGameType(d, gameType, list)
 
-	return s.dashboardRepo.Upsert(ctx, d)
+	if err := s.dashboardRepo.Upsert(ctx, d); err != nil {
+		return err
+	}
+	entered := false
+	for i := range list {
+		if list[i].SessionID == sessionID && list[i].User.ID == userID {
+			entered = true
+			event.Publish(ctx, event.Event{
+				Type:         event.LeaderboardEntered,
+				UserID:       userID,
+				GameType:     gameType,
+				SessionID:    sessionID,
+				SessionScore: sessionScore,
+				Timestamp:    timestamp,
+				Rank:         i + 1,
+			})
+			break
+		}
+	}
+	if !entered {
+		return nil
+	}
+	after := bestRanks(list)
+	for otherID, oldRank := range before {
+		if otherID == userID {
+			continue
+		}
+		if newRank := after[otherID]; newRank == 0 || newRank > oldRank {
+			event.Publish(ctx, event.Event{
+				Type:      event.RankLost,
+				UserID:    otherID,
+				GameType:  gameType,
+				SessionID: sessionID,
+				Timestamp: timestamp,
+				Rank:      newRank,
+			})
+		}
+	}
+	return nil
+}
+
+// bestRanks maps each user on a sorted top-N list to their best (1-based) position.
+func bestRanks(list []entity.DashboardEntry) map[string]int {
+	ranks := make(map[string]int, len(list))
+	for i, e := range list {
+		if _, ok := ranks[e.User.ID]; !ok {
+			ranks[e.User.ID] = i + 1
+		}
+	}
+	return ranks
 }
 
 func getDashboardEntriesForGameType(d *entity.Dashboard, gameType string) []entity.DashboardEntry {
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	"brainbash_backend/internal/event"
+	"brainbash_backend/internal/game"
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/repository"
+	"brainbash_backend/internal/tenant"
+)
+
+// ErrDigestNotFound is returned when the user has no digest for the requested week.
+var ErrDigestNotFound = errors.New("digest not found")
+
+// DigestService generates weekly summaries: sessions played, per-game improvements against the week
+// before, the change in composite leaderboard rank, and goals met. Weeks run Monday to Monday in UTC.
+type DigestService struct {
+	digestRepo  *repository.DigestRepository
+	sessionRepo *repository.SessionRepository
+	scoreRepo   *repository.ScoreRepository
+	goalRepo    *repository.GoalRepository
+	tenants     *TenantService
+	lastWeek    time.Time // week whose digests are all generated; only touched by the scheduled job
+}
+
+// NewDigestService creates a new DigestService.
+func NewDigestService(digestRepo *repository.DigestRepository, sessionRepo *repository.SessionRepository, scoreRepo *repository.ScoreRepository, goalRepo *repository.GoalRepository, tenants *TenantService) *DigestService {
+	return &DigestService{
+		digestRepo:  digestRepo,
+		sessionRepo: sessionRepo,
+		scoreRepo:   scoreRepo,
+		goalRepo:    goalRepo,
+		tenants:     tenants,
+	}
+}
+
+// GenerateWeekly generates digests for the last finished week for every user who played in it or the week
+// before, one tenant at a time so ranks are within the user's tenant. Users who already have that week's
+// digest are skipped, so the job can run as often as needed.
+func (s *DigestService) GenerateWeekly(ctx context.Context) error {
+	weekStart := digestWeekStart(time.Now()).AddDate(0, 0, -7)
+	if s.lastWeek.Equal(weekStart) {
+		return nil
+	}
+	failed := false
+	for _, t := range s.tenants.All(ctx) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := s.generateWeek(tenant.NewContext(ctx, t), weekStart); err != nil {
+			log.Printf("Digest: generate digests of tenant %s: %v", t.ID, err)
+			failed = true
+		}
+	}
+	if !failed {
+		s.lastWeek = weekStart
+	}
+	return nil
+}
+
+// generateWeek generates the missing digests of the week starting at weekStart for the tenant in ctx.
+func (s *DigestService) generateWeek(ctx context.Context, weekStart time.Time) error {
+	userIDs, err := s.sessionRepo.DistinctUserIDs(ctx, weekStart.AddDate(0, 0, -7), weekStart.AddDate(0, 0, 7))
+	if err != nil {
+		return err
+	}
+
+	generated := 0
+	for _, userID := range userIDs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		existing, err := s.digestRepo.FindByID(ctx, entity.DigestID(userID, weekStart))
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			continue
+		}
+		digest, err := s.Build(ctx, userID, weekStart)
+		if err != nil {
+			return err
+		}
+		inserted, err := s.digestRepo.Insert(ctx, digest)
+		if err != nil {
+			return err
+		}
+		if inserted {
+			generated++
+			event.Publish(ctx, event.Event{
+				Type:      event.DigestReady,
+				UserID:    userID,
+				Timestamp: digest.GeneratedAt,
+				RefID:     digest.ID,
+			})
+		}
+	}
+	log.Printf("Digest: generated %d digests of tenant %s for week of %s", generated, tenant.FromContext(ctx).ID, weekStart.Format(entity.StreakDayLayout))
+	return nil
+}
+
+// Build computes the user's digest for the week starting at weekStart without storing it.
+// The rank is the user's composite leaderboard position at the time of the call.
+func (s *DigestService) Build(ctx context.Context, userID string, weekStart time.Time) (*entity.Digest, error) {
+	weekEnd := weekStart.AddDate(0, 0, 7)
+	prevStart := weekStart.AddDate(0, 0, -7)
+	digest := &entity.Digest{
+		ID:           entity.DigestID(userID, weekStart),
+		UserID:       userID,
+		WeekStart:    weekStart,
+		WeekEnd:      weekEnd,
+		Improvements: []entity.DigestImprovement{},
+		GoalsHit:     []entity.DigestGoal{},
+		GeneratedAt:  time.Now().UTC(),
+	}
+
+	score, err := s.scoreRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if score == nil {
+		score = &entity.Score{UserID: userID}
+	}
+	for _, gt := range game.AllGameTypes {
+		gts := getGameTypeScore(score, string(gt))
+		if gts == nil {
+			continue
+		}
+		imp := entity.DigestImprovement{GameType: string(gt)}
+		var sum, prevSum float64
+		prevCount := 0
+		for i := range gts.Sessions {
+			se := &gts.Sessions[i]
+			thisWeek := !se.Timestamp.Before(weekStart) && se.Timestamp.Before(weekEnd)
+			lastWeek := !se.Timestamp.Before(prevStart) && se.Timestamp.Before(weekStart)
+			if thisWeek {
+				digest.SessionsPlayed++
+			} else if lastWeek {
+				digest.PreviousSessions++
+			}
+			// Flagged sessions count as played but not towards averages
+			if !se.LeaderboardEligible() {
+				continue
+			}
+			switch {
+			case thisWeek:
+				imp.Sessions++
+				sum += se.SessionScore.Score
+				if se.SessionScore.Score > imp.WeekBest {
+					imp.WeekBest = se.SessionScore.Score
+				}
+			case lastWeek:
+				prevCount++
+				prevSum += se.SessionScore.Score
+			}
+		}
+		if imp.Sessions == 0 {
+			continue
+		}
+		imp.AvgScore = sum / float64(imp.Sessions)
+		if prevCount > 0 {
+			imp.PreviousAvg = prevSum / float64(prevCount)
+			imp.Change = imp.AvgScore - imp.PreviousAvg
+		}
+		digest.Improvements = append(digest.Improvements, imp)
+	}
+	sort.SliceStable(digest.Improvements, func(i, j int) bool {
+		return digest.Improvements[i].Change > digest.Improvements[j].Change
+	})
+
+	if score.OverallScore > 0 {
+		above, err := s.scoreRepo.CountAboveOverall(ctx, score.OverallScore)
+		if err != nil {
+			return nil, err
+		}
+		digest.OverallRank = above + 1
+		prev, err := s.digestRepo.FindByID(ctx, entity.DigestID(userID, prevStart))
+		if err != nil {
+			return nil, err
+		}
+		if prev != nil && prev.OverallRank > 0 {
+			digest.RankChange = prev.OverallRank - digest.OverallRank
+		}
+	}
+
+	goals, err := s.goalRepo.FindByUserID(ctx, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	// Hit days are dates in the user's timezone; compare them with the week's UTC dates
+	from, to := weekStart.Format(entity.StreakDayLayout), weekEnd.Format(entity.StreakDayLayout)
+	for _, g := range goals {
+		times := 0
+		switch g.Kind {
+		case entity.GoalDailySessions:
+			for _, day := range g.HitDays {
+				if day >= from && day < to {
+					times++
+				}
+			}
+		case entity.GoalTargetScore:
+			if g.AchievedAt != nil && !g.AchievedAt.Before(weekStart) && g.AchievedAt.Before(weekEnd) {
+				times = 1
+			}
+		}
+		if times > 0 {
+			digest.GoalsHit = append(digest.GoalsHit, entity.DigestGoal{GoalID: g.ID, Kind: g.Kind, Target: g.Target, Times: times})
+		}
+	}
+	return digest, nil
+}
+
+// List returns up to limit of the user's digests, newest first.
+func (s *DigestService) List(ctx context.Context, userID string, limit int64) ([]*entity.Digest, error) {
+	return s.digestRepo.FindByUserID(ctx, userID, limit)
+}
+
+// Get returns the user's digest for the week starting on weekStart (YYYY-MM-DD, a Monday).
+func (s *DigestService) Get(ctx context.Context, userID, weekStart string) (*entity.Digest, error) {
+	t, err := time.Parse(entity.StreakDayLayout, weekStart)
+	if err != nil {
+		return nil, ErrDigestNotFound
+	}
+	digest, err := s.digestRepo.FindByID(ctx, entity.DigestID(userID, t))
+	if err != nil {
+		return nil, err
+	}
+	if digest == nil {
+		return nil, ErrDigestNotFound
+	}
+	return digest, nil
+}
+
+// digestWeekStart returns midnight UTC on the Monday of t's week.
+func digestWeekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
@@ -0,0 +1,368 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"log"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"brainbash_backend/config"
+	"brainbash_backend/internal/event"
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/model/request"
+	"brainbash_backend/internal/repository"
+)
+
+const (
+	defaultDuelTTL = 48 * time.Hour
+	// duelSubmitGrace is how long after expires_at a result is still accepted, so a game started just
+	// before the deadline can be submitted. The expiry job waits for it before settling a duel.
+	duelSubmitGrace  = 15 * time.Minute
+	duelExpiryBatch  = 500
+	duelRecordWins   = "wins"
+	duelRecordLosses = "losses"
+	duelRecordDraws  = "draws"
+)
+
+var (
+	// ErrDuelNotFound is returned when the duel does not exist or the user is not in it.
+	ErrDuelNotFound = errors.New("duel not found")
+	// ErrDuelClosed is returned when the duel no longer accepts the action (declined, finished or expired).
+	ErrDuelClosed = errors.New("duel is closed")
+	// ErrDuelNotAccepted is returned when the opponent submits before accepting.
+	ErrDuelNotAccepted = errors.New("duel has not been accepted")
+	// ErrDuelForbidden is returned when the wrong player accepts or declines.
+	ErrDuelForbidden = errors.New("only the challenged player can do this")
+	// ErrDuelPlayed is returned when the user already submitted a different session to the duel.
+	ErrDuelPlayed = errors.New("duel already played")
+)
+
+// DuelService runs asynchronous head-to-head duels between friends: both players get the same game type
+// and seed, play whenever they like before the duel expires, and the better SessionScoreDetail wins.
+type DuelService struct {
+	duelRepo      *repository.DuelRepository
+	socialService *SocialService
+	scoreService  *ScoreService
+	ratingService *RatingService
+	ttl           time.Duration
+}
+
+// NewDuelService creates a new DuelService.
+func NewDuelService(duelRepo *repository.DuelRepository, socialService *SocialService, scoreService *ScoreService, ratingService *RatingService, cfg config.DuelsConfig) *DuelService {
+	ttl := cfg.TTL
+	if ttl <= 0 {
+		ttl = defaultDuelTTL
+	}
+	return &DuelService{
+		duelRepo:      duelRepo,
+		socialService: socialService,
+		scoreService:  scoreService,
+		ratingService: ratingService,
+		ttl:           ttl,
+	}
+}
+
+// Create challenges a friend to a duel on gameType. The challenger may play straight away; the opponent
+// has to accept first.
+func (s *DuelService) Create(ctx context.Context, userID, opponentID, gameType string) (*entity.Duel, error) {
+	if err := ValidateGameType(ctx, gameType); err != nil {
+		return nil, err
+	}
+	if err := s.socialService.CheckFriend(ctx, userID, opponentID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	duel := &entity.Duel{
+		ID:           bson.NewObjectID().Hex(),
+		ChallengerID: userID,
+		OpponentID:   opponentID,
+		GameType:     gameType,
+		Seed:         rand.Int64(),
+		Status:       entity.DuelPending,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(s.ttl),
+	}
+	if err := s.duelRepo.Insert(ctx, duel); err != nil {
+		return nil, err
+	}
+	event.Publish(ctx, event.Event{Type: event.DuelReceived, UserID: opponentID, GameType: gameType, Timestamp: now, RefID: duel.ID})
+	return duel, nil
+}
+
+// Get returns the duel if the user plays in it.
+func (s *DuelService) Get(ctx context.Context, userID, duelID string) (*entity.Duel, error) {
+	duel, err := s.duelRepo.FindByID(ctx, duelID)
+	if err != nil {
+		return nil, err
+	}
+	if duel == nil || duel.Side(userID) == "" {
+		return nil, ErrDuelNotFound
+	}
+	return duel, nil
+}
+
+// List returns up to limit of the user's duels, newest first. status "" lists all.
+func (s *DuelService) List(ctx context.Context, userID, status string, limit int64) ([]*entity.Duel, error) {
+	return s.duelRepo.FindByUserID(ctx, userID, status, limit)
+}
+
+// Record returns the user's wins, losses and draws.
+func (s *DuelService) Record(ctx context.Context, userID string) (*entity.DuelRecord, error) {
+	record, err := s.duelRepo.FindRecord(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		record = &entity.DuelRecord{UserID: userID}
+	}
+	return record, nil
+}
+
+// Players loads the users playing in duels, keyed by hex id. Users that no longer exist are missing.
+func (s *DuelService) Players(ctx context.Context, duels ...*entity.Duel) (map[string]*entity.User, error) {
+	ids := make([]string, 0, 2*len(duels))
+	for _, d := range duels {
+		ids = append(ids, d.ChallengerID, d.OpponentID)
+	}
+	return s.socialService.usersByID(ctx, ids)
+}
+
+// Accept starts a pending duel. Only the opponent can accept, and only before it expires.
+func (s *DuelService) Accept(ctx context.Context, userID, duelID string) (*entity.Duel, error) {
+	duel, err := s.Get(ctx, userID, duelID)
+	if err != nil {
+		return nil, err
+	}
+	if duel.Side(userID) != entity.DuelSideOpponent {
+		return nil, ErrDuelForbidden
+	}
+	now := time.Now().UTC()
+	if now.After(duel.ExpiresAt) {
+		return nil, ErrDuelClosed
+	}
+	ok, err := s.duelRepo.Transition(ctx, duel.ID, []string{entity.DuelPending}, bson.M{"status": entity.DuelActive, "accepted_at": now})
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrDuelClosed
+	}
+	return s.duelRepo.FindByID(ctx, duel.ID)
+}
+
+// Decline turns down a pending duel. Only the opponent can decline; nobody's record changes.
+func (s *DuelService) Decline(ctx context.Context, userID, duelID string) (*entity.Duel, error) {
+	duel, err := s.Get(ctx, userID, duelID)
+	if err != nil {
+		return nil, err
+	}
+	if duel.Side(userID) != entity.DuelSideOpponent {
+		return nil, ErrDuelForbidden
+	}
+	ok, err := s.duelRepo.Transition(ctx, duel.ID, []string{entity.DuelPending}, bson.M{"status": entity.DuelDeclined, "completed_at": time.Now().UTC()})
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrDuelClosed
+	}
+	return s.duelRepo.FindByID(ctx, duel.ID)
+}
+
+// Submit stores the user's session for the duel, scored like a regular game of the duel's game type and
+// tagged with the duel. When both players have played the duel is decided and records are updated.
+// Retrying the same submission replays it (replayed=true); any other second session is ErrDuelPlayed.
+// An entry's flagged state is the one at submission: approving it later does not change the result.
+func (s *DuelService) Submit(ctx context.Context, userID, duelID string, req request.DuelResultRequest) (*entity.Duel, *entity.GameResult, bool, error) {
+	duel, err := s.Get(ctx, userID, duelID)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	side := duel.Side(userID)
+	if entry := duel.Entry(side); entry != nil {
+		if req.SessionID == "" || entry.SessionID != req.SessionID {
+			return nil, nil, false, ErrDuelPlayed
+		}
+	} else if err := s.checkOpen(duel, side); err != nil {
+		return nil, nil, false, err
+	}
+
+	// One idempotency key per user and duel serialises concurrent submissions and makes retries replay
+	result, replayed, err := s.scoreService.SubmitTaggedResult(ctx, userID, "duel:"+duel.ID, request.GameResultRequest{
+		GameType:          duel.GameType,
+		QuestionResponses: req.QuestionResponses,
+		SessionID:         req.SessionID,
+	}, entity.SessionTags{DuelID: duel.ID})
+	if err != nil {
+		if errors.Is(err, ErrIdempotencyKeyReused) {
+			return nil, nil, false, ErrDuelPlayed
+		}
+		return nil, nil, false, err
+	}
+
+	updated, err := s.duelRepo.SetEntry(ctx, duel.ID, side, openStatuses(side), &entity.DuelEntry{
+		SessionID:    result.SessionID,
+		SessionScore: result.SessionScore,
+		Flagged:      result.Flagged,
+		SubmittedAt:  time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if updated == nil {
+		// Stored by the original request this one replays, or the duel closed in the meantime
+		if updated, err = s.duelRepo.FindByID(ctx, duel.ID); err != nil {
+			return nil, nil, false, err
+		}
+		entry := updated.Entry(side)
+		if entry == nil || entry.SessionID != result.SessionID {
+			return nil, nil, false, ErrDuelClosed
+		}
+		replayed = true
+	}
+	if updated.Status == entity.DuelActive && updated.Challenger != nil && updated.Opponent != nil {
+		if err := s.complete(ctx, updated, false); err != nil {
+			return nil, nil, false, err
+		}
+		if updated, err = s.duelRepo.FindByID(ctx, duel.ID); err != nil {
+			return nil, nil, false, err
+		}
+	}
+	return updated, result, replayed, nil
+}
+
+// ExpireDue settles duels whose time ran out (plus the submit grace). An accepted duel that only one
+// player finished is won by forfeit; any other unfinished duel expires without a result.
+func (s *DuelService) ExpireDue(ctx context.Context) error {
+	now := time.Now().UTC()
+	duels, err := s.duelRepo.FindExpired(ctx, now.Add(-duelSubmitGrace), duelExpiryBatch)
+	if err != nil {
+		return err
+	}
+	var forfeits, expired int
+	for _, duel := range duels {
+		if duel.Status == entity.DuelActive && (duel.Challenger != nil || duel.Opponent != nil) {
+			if err := s.complete(ctx, duel, duel.Challenger == nil || duel.Opponent == nil); err != nil {
+				log.Printf("Duels: complete %s: %v", duel.ID, err)
+				continue
+			}
+			forfeits++
+			continue
+		}
+		ok, err := s.duelRepo.Transition(ctx, duel.ID, []string{entity.DuelPending, entity.DuelActive}, bson.M{"status": entity.DuelExpired, "completed_at": now})
+		if err != nil {
+			log.Printf("Duels: expire %s: %v", duel.ID, err)
+			continue
+		}
+		if ok {
+			expired++
+		}
+	}
+	if forfeits > 0 || expired > 0 {
+		log.Printf("Duels: settled %d and expired %d duels", forfeits, expired)
+	}
+	return nil
+}
+
+// complete decides an active duel, updates both records and notifies both players. With forfeit the
+// player who submitted wins. A duel completed concurrently is left alone.
+func (s *DuelService) complete(ctx context.Context, duel *entity.Duel, forfeit bool) error {
+	var winnerID string
+	switch {
+	case forfeit && duel.Challenger != nil:
+		winnerID = duel.ChallengerID
+	case forfeit:
+		winnerID = duel.OpponentID
+	default:
+		switch compareDuelEntries(duel.Challenger, duel.Opponent) {
+		case 1:
+			winnerID = duel.ChallengerID
+		case -1:
+			winnerID = duel.OpponentID
+		}
+	}
+
+	now := time.Now().UTC()
+	set := bson.M{"status": entity.DuelCompleted, "completed_at": now, "forfeit": forfeit}
+	if winnerID != "" {
+		set["winner_id"] = winnerID
+	}
+	ok, err := s.duelRepo.Transition(ctx, duel.ID, []string{entity.DuelActive}, set)
+	if err != nil || !ok {
+		return err
+	}
+	duel.Status, duel.WinnerID, duel.Forfeit, duel.CompletedAt = entity.DuelCompleted, winnerID, forfeit, &now
+
+	if winnerID == "" {
+		if err := s.duelRepo.IncRecord(ctx, duel.ChallengerID, duelRecordDraws, now); err != nil {
+			return err
+		}
+		if err := s.duelRepo.IncRecord(ctx, duel.OpponentID, duelRecordDraws, now); err != nil {
+			return err
+		}
+	} else {
+		if err := s.duelRepo.IncRecord(ctx, winnerID, duelRecordWins, now); err != nil {
+			return err
+		}
+		if err := s.duelRepo.IncRecord(ctx, duel.OtherID(winnerID), duelRecordLosses, now); err != nil {
+			return err
+		}
+	}
+	if err := s.ratingService.RateDuel(ctx, duel); err != nil {
+		// The duel is settled either way; its rating update is lost
+		log.Printf("Duels: rate duel %s: %v", duel.ID, err)
+	}
+	for _, userID := range []string{duel.ChallengerID, duel.OpponentID} {
+		event.Publish(ctx, event.Event{Type: event.DuelCompleted, UserID: userID, GameType: duel.GameType, Timestamp: now, RefID: duel.ID})
+	}
+	return nil
+}
+
+// checkOpen returns why side cannot submit to the duel now, or nil.
+func (s *DuelService) checkOpen(duel *entity.Duel, side string) error {
+	if side == entity.DuelSideOpponent && duel.Status == entity.DuelPending {
+		return ErrDuelNotAccepted
+	}
+	if !slices.Contains(openStatuses(side), duel.Status) || time.Now().After(duel.ExpiresAt.Add(duelSubmitGrace)) {
+		return ErrDuelClosed
+	}
+	return nil
+}
+
+// openStatuses are the duel statuses in which side may submit. The challenger may play before the
+// opponent accepts.
+func openStatuses(side string) []string {
+	if side == entity.DuelSideChallenger {
+		return []string{entity.DuelPending, entity.DuelActive}
+	}
+	return []string{entity.DuelActive}
+}
+
+// compareDuelEntries returns 1 when a beats b, -1 when b beats a and 0 for a draw.
+func compareDuelEntries(a, b *entity.DuelEntry) int {
+	return compareSessions(a.SessionScore, a.Flagged, b.SessionScore, b.Flagged)
+}
+
+// compareSessions returns 1 when session a beats b, -1 when b beats a and 0 for a draw. A session held for
+// review loses to one that is not; otherwise higher score, then higher accuracy, then lower avgTime wins.
+func compareSessions(a entity.SessionScoreDetail, aFlagged bool, b entity.SessionScoreDetail, bFlagged bool) int {
+	if aFlagged != bFlagged {
+		if bFlagged {
+			return 1
+		}
+		return -1
+	}
+	if c := cmp.Compare(a.Score, b.Score); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.Accuracy, b.Accuracy); c != 0 {
+		return c
+	}
+	return cmp.Compare(b.AvgTime, a.AvgTime)
+}
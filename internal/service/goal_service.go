@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"brainbash_backend/config"
+	"brainbash_backend/internal/event"
+	"brainbash_backend/internal/game"
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/repository"
+	"brainbash_backend/internal/validation"
+)
+
+const (
+	defaultMaxGoalsPerUser = 10
+	maxDailySessionsTarget = 50
+)
+
+var (
+	// ErrGoalNotFound is returned when the user has no goal with the given id.
+	ErrGoalNotFound = errors.New("goal not found")
+	// ErrTooManyGoals is returned when the user already has goals.max_per_user goals.
+	ErrTooManyGoals = errors.New("too many goals")
+)
+
+// GoalInput is a new goal, or the fields of an existing goal to change (nil leaves a field unchanged).
+type GoalInput struct {
+	Kind     string
+	GameType string
+	Target   *float64
+	Active   *bool
+}
+
+// GoalStatus is a goal with the user's progress towards it: today's session count for daily goals,
+// the best session score for target scores.
+type GoalStatus struct {
+	Goal     *entity.Goal
+	Progress float64
+	Met      bool // met today (daily goals) or ever (one-off goals)
+}
+
+// GoalService manages per-user goals and evaluates them on every stored session.
+type GoalService struct {
+	goalRepo    *repository.GoalRepository
+	sessionRepo *repository.SessionRepository
+	scoreRepo   *repository.ScoreRepository
+	userService *UserService
+	maxPerUser  int
+}
+
+// NewGoalService creates a new GoalService.
+func NewGoalService(goalRepo *repository.GoalRepository, sessionRepo *repository.SessionRepository, scoreRepo *repository.ScoreRepository, userService *UserService, cfg config.GoalsConfig) *GoalService {
+	maxPerUser := cfg.MaxPerUser
+	if maxPerUser <= 0 {
+		maxPerUser = defaultMaxGoalsPerUser
+	}
+	return &GoalService{
+		goalRepo:    goalRepo,
+		sessionRepo: sessionRepo,
+		scoreRepo:   scoreRepo,
+		userService: userService,
+		maxPerUser:  maxPerUser,
+	}
+}
+
+// Subscribe registers the service for stored sessions.
+func (s *GoalService) Subscribe() {
+	event.Subscribe(event.SessionStored, "goals", s.handle)
+}
+
+// Create adds an active goal for the user.
+func (s *GoalService) Create(ctx context.Context, userID string, in GoalInput) (*GoalStatus, error) {
+	if in.Target == nil {
+		return nil, &validation.Error{Field: "target", Message: "required"}
+	}
+	goal := &entity.Goal{
+		ID:       bson.NewObjectID().Hex(),
+		UserID:   userID,
+		Kind:     in.Kind,
+		GameType: in.GameType,
+		Target:   *in.Target,
+		Active:   in.Active == nil || *in.Active,
+	}
+	if err := validateGoal(goal); err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	goal.CreatedAt, goal.UpdatedAt = now, now
+	// Each goal takes one of the user's maxPerUser slots, which are unique per user: of concurrent creates
+	// taking the same slot only one is stored, and the others look for another free slot
+	for range s.maxPerUser {
+		goals, err := s.goalRepo.FindByUserID(ctx, userID, false)
+		if err != nil {
+			return nil, err
+		}
+		slot, ok := freeGoalSlot(goals, s.maxPerUser)
+		if !ok {
+			return nil, ErrTooManyGoals
+		}
+		goal.Slot = &slot
+		inserted, err := s.goalRepo.Insert(ctx, goal)
+		if err != nil {
+			return nil, err
+		}
+		if inserted {
+			return s.status(ctx, goal)
+		}
+	}
+	return nil, ErrTooManyGoals
+}
+
+// List returns the user's goals with progress, oldest first.
+func (s *GoalService) List(ctx context.Context, userID string) ([]GoalStatus, error) {
+	goals, err := s.goalRepo.FindByUserID(ctx, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]GoalStatus, 0, len(goals))
+	for _, g := range goals {
+		status, err := s.status(ctx, g)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *status)
+	}
+	return out, nil
+}
+
+// Get returns one of the user's goals with progress.
+func (s *GoalService) Get(ctx context.Context, userID, goalID string) (*GoalStatus, error) {
+	goal, err := s.goalRepo.FindByID(ctx, userID, goalID)
+	if err != nil {
+		return nil, err
+	}
+	if goal == nil {
+		return nil, ErrGoalNotFound
+	}
+	return s.status(ctx, goal)
+}
+
+// Update changes the target or active flag of the user's goal. Kind and game type are fixed;
+// a different goal is a new goal. Days already met stay recorded.
+func (s *GoalService) Update(ctx context.Context, userID, goalID string, in GoalInput) (*GoalStatus, error) {
+	goal, err := s.goalRepo.FindByID(ctx, userID, goalID)
+	if err != nil {
+		return nil, err
+	}
+	if goal == nil {
+		return nil, ErrGoalNotFound
+	}
+	if in.Target != nil {
+		goal.Target = *in.Target
+	}
+	if in.Active != nil {
+		goal.Active = *in.Active
+	}
+	if err := validateGoal(goal); err != nil {
+		return nil, err
+	}
+	goal.UpdatedAt = time.Now().UTC()
+	ok, err := s.goalRepo.Update(ctx, goal)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrGoalNotFound
+	}
+	return s.status(ctx, goal)
+}
+
+// Delete removes the user's goal.
+func (s *GoalService) Delete(ctx context.Context, userID, goalID string) error {
+	ok, err := s.goalRepo.Delete(ctx, userID, goalID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrGoalNotFound
+	}
+	return nil
+}
+
+// handle evaluates the user's active goals against a stored session. Daily goals are counted on the
+// day the session was played in the user's timezone, so offline syncs credit the right day.
+func (s *GoalService) handle(ctx context.Context, e event.Event) error {
+	goals, err := s.goalRepo.FindByUserID(ctx, e.UserID, true)
+	if err != nil {
+		return err
+	}
+	if len(goals) == 0 {
+		return nil
+	}
+	loc := s.userService.Location(ctx, e.UserID)
+	for _, g := range goals {
+		if g.GameType != "" && g.GameType != e.GameType {
+			continue
+		}
+		met := false
+		switch g.Kind {
+		case entity.GoalDailySessions:
+			from, to := dayBounds(e.Timestamp, loc)
+			day := e.Timestamp.In(loc).Format(entity.StreakDayLayout)
+			if slices.Contains(g.HitDays, day) {
+				continue
+			}
+			n, err := s.sessionRepo.CountInRange(ctx, e.UserID, g.GameType, from, to)
+			if err != nil {
+				return err
+			}
+			if float64(n) >= g.Target {
+				if met, err = s.goalRepo.AddHitDay(ctx, g.ID, day); err != nil {
+					return err
+				}
+			}
+		case entity.GoalTargetScore:
+			if g.AchievedAt == nil && e.SessionScore.Score >= g.Target {
+				if met, err = s.goalRepo.SetAchieved(ctx, g.ID, time.Now().UTC()); err != nil {
+					return err
+				}
+			}
+		}
+		if met {
+			log.Printf("Goals: user %s met goal %s (%s)", e.UserID, g.ID, g.Kind)
+			event.Publish(ctx, event.Event{
+				Type:      event.GoalMet,
+				UserID:    e.UserID,
+				GameType:  g.GameType,
+				SessionID: e.SessionID,
+				Timestamp: time.Now().UTC(),
+				RefID:     g.ID,
+			})
+		}
+	}
+	return nil
+}
+
+// status computes the user's current progress towards goal.
+func (s *GoalService) status(ctx context.Context, goal *entity.Goal) (*GoalStatus, error) {
+	status := &GoalStatus{Goal: goal}
+	switch goal.Kind {
+	case entity.GoalDailySessions:
+		loc := s.userService.Location(ctx, goal.UserID)
+		now := time.Now()
+		from, to := dayBounds(now, loc)
+		n, err := s.sessionRepo.CountInRange(ctx, goal.UserID, goal.GameType, from, to)
+		if err != nil {
+			return nil, err
+		}
+		status.Progress = float64(n)
+		status.Met = slices.Contains(goal.HitDays, now.In(loc).Format(entity.StreakDayLayout))
+	case entity.GoalTargetScore:
+		score, err := s.scoreRepo.FindByUserID(ctx, goal.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if score != nil {
+			if gts := getGameTypeScore(score, goal.GameType); gts != nil {
+				status.Progress = gts.HighScore
+			}
+		}
+		status.Met = goal.AchievedAt != nil
+	}
+	return status, nil
+}
+
+// freeGoalSlot returns the lowest slot none of goals holds, or false when there are already maxPerUser goals.
+func freeGoalSlot(goals []*entity.Goal, maxPerUser int) (int, bool) {
+	if len(goals) >= maxPerUser {
+		return 0, false
+	}
+	used := make(map[int]bool, len(goals))
+	for _, g := range goals {
+		if g.Slot != nil {
+			used[*g.Slot] = true
+		}
+	}
+	for slot := range maxPerUser {
+		if !used[slot] {
+			return slot, true
+		}
+	}
+	return 0, false
+}
+
+// validateGoal checks the kind, game type and target of goal.
+func validateGoal(goal *entity.Goal) error {
+	if goal.GameType != "" && !game.GameType(goal.GameType).IsValid() {
+		return &validation.Error{Field: "game_type", Message: "unknown game type"}
+	}
+	switch goal.Kind {
+	case entity.GoalDailySessions:
+		if goal.Target < 1 || goal.Target > maxDailySessionsTarget || goal.Target != float64(int(goal.Target)) {
+			return &validation.Error{Field: "target", Message: "must be a whole number of sessions between 1 and 50"}
+		}
+	case entity.GoalTargetScore:
+		if goal.GameType == "" {
+			return &validation.Error{Field: "game_type", Message: "required for target_score goals"}
+		}
+		if goal.Target <= 0 || goal.Target > 100 {
+			return &validation.Error{Field: "target", Message: "must be a score above 0 and at most 100"}
+		}
+	default:
+		return &validation.Error{Field: "kind", Message: "must be daily_sessions or target_score"}
+	}
+	return nil
+}
+
+// dayBounds returns the first and last instant of the calendar day containing t in loc.
+func dayBounds(t time.Time, loc *time.Location) (time.Time, time.Time) {
+	local := t.In(loc)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	return start.UTC(), start.AddDate(0, 0, 1).Add(-time.Millisecond).UTC()
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"testing"
+
+	"brainbash_backend/internal/model/entity"
+)
+
+func TestFreeGoalSlot(t *testing.T) {
+	slotted := func(slots ...int) []*entity.Goal {
+		goals := make([]*entity.Goal, 0, len(slots))
+		for _, slot := range slots {
+			if slot < 0 {
+				goals = append(goals, &entity.Goal{})
+				continue
+			}
+			goals = append(goals, &entity.Goal{Slot: &slot})
+		}
+		return goals
+	}
+	tests := []struct {
+		name   string
+		goals  []*entity.Goal
+		want   int
+		wantOK bool
+	}{
+		{name: "no goals", goals: nil, want: 0, wantOK: true},
+		{name: "next slot", goals: slotted(0, 1), want: 2, wantOK: true},
+		{name: "gap left by a delete", goals: slotted(0, 2), want: 1, wantOK: true},
+		{name: "goals without a slot", goals: slotted(-1, 0), want: 1, wantOK: true},
+		{name: "full", goals: slotted(0, 1, 2), wantOK: false},
+		{name: "full with goals without a slot", goals: slotted(-1, 1, -1), wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := freeGoalSlot(tt.goals, 3)
+			if ok != tt.wantOK || (ok && got != tt.want) {
+				t.Errorf("freeGoalSlot = %d, %v, want %d, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -1,11 +1,15 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
+
+	"brainbash_backend/internal/tenant"
 )
 
 const (
@@ -50,8 +54,8 @@ func NewGoogleAuthService(clientIDs []string) *GoogleAuthService {
 
 // VerifyIDToken verifies a Google ID token and returns the user info.
 // It calls Google's tokeninfo endpoint and checks that the audience (aud)
-// matches the configured client ID.
-func (s *GoogleAuthService) VerifyIDToken(idToken string) (*GoogleUserInfo, error) {
+// matches the client IDs of the tenant in ctx, or the configured ones outside a tenant.
+func (s *GoogleAuthService) VerifyIDToken(ctx context.Context, idToken string) (*GoogleUserInfo, error) {
 	resp, err := s.httpClient.Get(fmt.Sprintf(googleTokenInfoURL, idToken))
 	if err != nil {
 		return nil, fmt.Errorf("failed to verify token with Google: %w", err)
@@ -71,13 +75,22 @@ func (s *GoogleAuthService) VerifyIDToken(idToken string) (*GoogleUserInfo, erro
 		return nil, fmt.Errorf("failed to decode Google token response: %w", err)
 	}
 
-	if _, allowed := s.allowedClientIDs[payload.Aud]; !allowed {
+	if !s.clientIDAllowed(ctx, payload.Aud) {
 		return nil, fmt.Errorf("token audience mismatch: token aud %q is not in allowed client IDs", payload.Aud)
 	}
 
 	return &payload.GoogleUserInfo, nil
 }
 
+// clientIDAllowed reports whether aud is one of the tenant's Google client IDs.
+func (s *GoogleAuthService) clientIDAllowed(ctx context.Context, aud string) bool {
+	if t := tenant.FromContext(ctx); t != nil {
+		return slices.Contains(t.GoogleClientIDs, aud)
+	}
+	_, allowed := s.allowedClientIDs[aud]
+	return allowed
+}
+
 // VerifyAccessToken verifies a Google access token by calling the userinfo endpoint.
 // This is used for web clients where the Google Sign-In SDK provides an access_token
 // instead of an id_token.
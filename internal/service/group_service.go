@@ -0,0 +1,522 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"brainbash_backend/config"
+	"brainbash_backend/internal/game"
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/repository"
+	"brainbash_backend/internal/validation"
+)
+
+const (
+	defaultMaxGroupMembers  = 500
+	defaultMaxGroupsPerUser = 20
+	maxGroupNameLength      = 60
+	maxGroupReportDays      = 366
+)
+
+var (
+	// ErrGroupNotFound is returned for an unknown group or join code.
+	ErrGroupNotFound = errors.New("group not found")
+	// ErrGroupMemberNotFound is returned when the target user is not a member of the group.
+	ErrGroupMemberNotFound = errors.New("group member not found")
+	// ErrGroupJoined is returned when joining a group the user is already in.
+	ErrGroupJoined = errors.New("already a member of this group")
+	// ErrGroupFull is returned when the group has groups.max_members members.
+	ErrGroupFull = errors.New("group is full")
+	// ErrGroupLimit is returned when the user is in groups.max_groups_per_user groups.
+	ErrGroupLimit = errors.New("group limit reached")
+	// ErrGroupOwner is returned when an action would leave the group without an owner.
+	ErrGroupOwner = errors.New("the owner cannot leave or be removed; transfer ownership or delete the group")
+	// ErrGroupForbidden is returned when the caller's role does not allow the action on the target member.
+	ErrGroupForbidden = errors.New("not allowed for your group role")
+)
+
+// GroupMembership is a group the user belongs to and their role in it.
+type GroupMembership struct {
+	Group   *entity.Group
+	Role    string
+	Members int64
+}
+
+// GroupMemberView is a group member with their user.
+type GroupMemberView struct {
+	Member *entity.GroupMember
+	User   *entity.User
+}
+
+// MemberProgress is one member on the owner's progress dashboard.
+type MemberProgress struct {
+	Member       *entity.GroupMember
+	User         *entity.User
+	OverallScore float64
+	GameTypes    []GameTypeProgress
+	LastActiveAt *time.Time
+}
+
+// GameTypeProgress is a member's aggregates on one game type they have played.
+type GameTypeProgress struct {
+	GameType  string
+	AvgScore  float64
+	HighScore float64
+	Sessions  int
+}
+
+// MemberReport is one member's row in a group report.
+type MemberReport struct {
+	Member       *entity.GroupMember
+	User         *entity.User
+	Sessions     int
+	LastActiveAt *time.Time
+	Domains      []entity.DomainActivity // game types played in the period, in game.AllGameTypes order
+}
+
+// GroupService manages groups such as classrooms and teams: join codes, membership, roles, and the
+// private leaderboard and progress dashboard that only the group's members can see.
+type GroupService struct {
+	groupRepo   *repository.GroupRepository
+	scoreRepo   *repository.ScoreRepository
+	sessionRepo *repository.SessionRepository
+	userService *UserService
+	cfg         config.GroupsConfig
+}
+
+// NewGroupService creates a new GroupService.
+func NewGroupService(groupRepo *repository.GroupRepository, scoreRepo *repository.ScoreRepository, sessionRepo *repository.SessionRepository, userService *UserService, cfg config.GroupsConfig) *GroupService {
+	if cfg.MaxMembers <= 0 {
+		cfg.MaxMembers = defaultMaxGroupMembers
+	}
+	if cfg.MaxGroupsPerUser <= 0 {
+		cfg.MaxGroupsPerUser = defaultMaxGroupsPerUser
+	}
+	return &GroupService{
+		groupRepo:   groupRepo,
+		scoreRepo:   scoreRepo,
+		sessionRepo: sessionRepo,
+		userService: userService,
+		cfg:         cfg,
+	}
+}
+
+// Create creates a group owned by userID with a fresh join code.
+func (s *GroupService) Create(ctx context.Context, userID, name string) (*entity.Group, error) {
+	name, err := validateGroupName(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkGroupLimit(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	group := &entity.Group{
+		ID:        bson.NewObjectID().Hex(),
+		Name:      name,
+		OwnerID:   userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	for i := 0; ; i++ {
+		if i == friendCodeAttempts {
+			return nil, fmt.Errorf("could not allocate a join code after %d attempts", friendCodeAttempts)
+		}
+		if group.JoinCode, err = newFriendCode(); err != nil {
+			return nil, err
+		}
+		inserted, err := s.groupRepo.Insert(ctx, group)
+		if err != nil {
+			return nil, err
+		}
+		if inserted {
+			break
+		}
+	}
+	_, err = s.groupRepo.InsertMember(ctx, &entity.GroupMember{
+		ID:       entity.GroupMemberID(group.ID, userID),
+		GroupID:  group.ID,
+		UserID:   userID,
+		Role:     entity.GroupRoleOwner,
+		JoinedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// Get returns the group and how many members it has.
+func (s *GroupService) Get(ctx context.Context, groupID string) (*entity.Group, int64, error) {
+	group, err := s.groupRepo.FindByID(ctx, groupID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if group == nil {
+		return nil, 0, ErrGroupNotFound
+	}
+	members, err := s.groupRepo.CountMembers(ctx, groupID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return group, members, nil
+}
+
+// ForUser returns the groups the user belongs to, most recently joined first.
+func (s *GroupService) ForUser(ctx context.Context, userID string) ([]GroupMembership, error) {
+	memberships, err := s.groupRepo.FindMemberships(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(memberships))
+	for _, m := range memberships {
+		ids = append(ids, m.GroupID)
+	}
+	groups, err := s.groupRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*entity.Group, len(groups))
+	for _, g := range groups {
+		byID[g.ID] = g
+	}
+
+	out := make([]GroupMembership, 0, len(memberships))
+	for _, m := range memberships {
+		group := byID[m.GroupID]
+		if group == nil {
+			continue
+		}
+		count, err := s.groupRepo.CountMembers(ctx, group.ID)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, GroupMembership{Group: group, Role: m.Role, Members: count})
+	}
+	return out, nil
+}
+
+// MemberRole returns the user's role in the group, or "" if they are not a member.
+func (s *GroupService) MemberRole(ctx context.Context, groupID, userID string) (string, error) {
+	if groupID == "" || userID == "" {
+		return "", nil
+	}
+	m, err := s.groupRepo.FindMember(ctx, groupID, userID)
+	if err != nil || m == nil {
+		return "", err
+	}
+	return m.Role, nil
+}
+
+// Join adds the user to the group with the join code as a member.
+func (s *GroupService) Join(ctx context.Context, userID, code string) (*entity.Group, error) {
+	group, err := s.groupRepo.FindByJoinCode(ctx, normalizeFriendCode(code))
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		return nil, ErrGroupNotFound
+	}
+	if m, err := s.groupRepo.FindMember(ctx, group.ID, userID); err != nil {
+		return nil, err
+	} else if m != nil {
+		return nil, ErrGroupJoined
+	}
+	if err := s.checkGroupLimit(ctx, userID); err != nil {
+		return nil, err
+	}
+	// Best effort: concurrent joins may overshoot the limit slightly
+	count, err := s.groupRepo.CountMembers(ctx, group.ID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= int64(s.cfg.MaxMembers) {
+		return nil, ErrGroupFull
+	}
+
+	inserted, err := s.groupRepo.InsertMember(ctx, &entity.GroupMember{
+		ID:       entity.GroupMemberID(group.ID, userID),
+		GroupID:  group.ID,
+		UserID:   userID,
+		Role:     entity.GroupRoleMember,
+		JoinedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !inserted {
+		return nil, ErrGroupJoined
+	}
+	return group, nil
+}
+
+// Leave removes the user from the group. The owner has to transfer ownership or delete the group instead.
+func (s *GroupService) Leave(ctx context.Context, groupID, userID string) error {
+	m, err := s.groupRepo.FindMember(ctx, groupID, userID)
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		return ErrGroupNotFound
+	}
+	if m.Role == entity.GroupRoleOwner {
+		return ErrGroupOwner
+	}
+	_, err = s.groupRepo.DeleteMember(ctx, groupID, userID)
+	return err
+}
+
+// Delete removes the group and all its memberships.
+func (s *GroupService) Delete(ctx context.Context, groupID string) error {
+	return s.groupRepo.Delete(ctx, groupID)
+}
+
+// RegenerateCode replaces the group's join code, so the old one stops working.
+func (s *GroupService) RegenerateCode(ctx context.Context, groupID string) (*entity.Group, error) {
+	for i := 0; i < friendCodeAttempts; i++ {
+		code, err := newFriendCode()
+		if err != nil {
+			return nil, err
+		}
+		updated, err := s.groupRepo.Update(ctx, groupID, bson.M{"join_code": code, "updated_at": time.Now().UTC()})
+		if err != nil {
+			return nil, err
+		}
+		if updated {
+			group, _, err := s.Get(ctx, groupID)
+			return group, err
+		}
+	}
+	return nil, fmt.Errorf("could not allocate a join code after %d attempts", friendCodeAttempts)
+}
+
+// Members returns the group's members with their users, in the order they joined.
+func (s *GroupService) Members(ctx context.Context, groupID string) ([]GroupMemberView, error) {
+	members, users, err := s.members(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]GroupMemberView, 0, len(members))
+	for _, m := range members {
+		if user := users[m.UserID]; user != nil {
+			out = append(out, GroupMemberView{Member: m, User: user})
+		}
+	}
+	return out, nil
+}
+
+// SetRole changes a member's role; only the owner may call it. Making a member the owner transfers
+// ownership and demotes the current owner to admin.
+func (s *GroupService) SetRole(ctx context.Context, groupID, ownerID, userID, role string) error {
+	if role != entity.GroupRoleOwner && role != entity.GroupRoleAdmin && role != entity.GroupRoleMember {
+		return &validation.Error{Field: "role", Message: "role must be owner, admin or member"}
+	}
+	if userID == ownerID {
+		return ErrGroupOwner
+	}
+	m, err := s.groupRepo.FindMember(ctx, groupID, userID)
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		return ErrGroupMemberNotFound
+	}
+	if role != entity.GroupRoleOwner {
+		_, err := s.groupRepo.SetRole(ctx, groupID, userID, role)
+		return err
+	}
+
+	// Promote first so the group always has an owner
+	if _, err := s.groupRepo.SetRole(ctx, groupID, userID, entity.GroupRoleOwner); err != nil {
+		return err
+	}
+	if _, err := s.groupRepo.Update(ctx, groupID, bson.M{"owner_id": userID, "updated_at": time.Now().UTC()}); err != nil {
+		return err
+	}
+	_, err = s.groupRepo.SetRole(ctx, groupID, ownerID, entity.GroupRoleAdmin)
+	return err
+}
+
+// RemoveMember removes userID from the group. Callers can only remove members ranked below their role,
+// so admins remove members and the owner removes anyone but themselves.
+func (s *GroupService) RemoveMember(ctx context.Context, groupID, callerRole, userID string) error {
+	m, err := s.groupRepo.FindMember(ctx, groupID, userID)
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		return ErrGroupMemberNotFound
+	}
+	if m.Role == entity.GroupRoleOwner {
+		return ErrGroupOwner
+	}
+	if entity.GroupRoleRank(m.Role) >= entity.GroupRoleRank(callerRole) {
+		return ErrGroupForbidden
+	}
+	_, err = s.groupRepo.DeleteMember(ctx, groupID, userID)
+	return err
+}
+
+// Leaderboard ranks the group's members on gameType ("" for overall) within window, the same way as the
+// friends leaderboard. Only members are ranked, so scores never leak outside the group.
+func (s *GroupService) Leaderboard(ctx context.Context, groupID, userID, gameType, window string) ([]LeaderboardEntry, error) {
+	since, windowed, err := leaderboardWindow(window)
+	if err != nil {
+		return nil, err
+	}
+	if gameType != "" {
+		if err := game.GameType(gameType).Validate(); err != nil {
+			return nil, &validation.Error{Field: "gametype", Message: err.Error()}
+		}
+	}
+	members, users, err := s.members(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	scores, err := s.scoreRepo.FindByUserIDs(ctx, memberIDs(members), windowed)
+	if err != nil {
+		return nil, err
+	}
+	return rankLeaderboard(scores, users, userID, gameType, windowed, since), nil
+}
+
+// Progress returns every member's overall score, per game type aggregates and when they last played,
+// for the owner's dashboard. Members who have not played have no game types.
+func (s *GroupService) Progress(ctx context.Context, groupID string) ([]MemberProgress, error) {
+	members, users, err := s.members(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	scores, err := s.scoreRepo.FindByUserIDs(ctx, memberIDs(members), true)
+	if err != nil {
+		return nil, err
+	}
+	byUser := make(map[string]*entity.Score, len(scores))
+	for _, score := range scores {
+		byUser[score.UserID] = score
+	}
+
+	out := make([]MemberProgress, 0, len(members))
+	for _, m := range members {
+		user := users[m.UserID]
+		if user == nil {
+			continue
+		}
+		p := MemberProgress{Member: m, User: user, GameTypes: []GameTypeProgress{}}
+		if score := byUser[m.UserID]; score != nil {
+			p.OverallScore = score.OverallScore
+			for _, gt := range game.AllGameTypes {
+				gts := getGameTypeScore(score, string(gt))
+				if gts == nil || len(gts.Sessions) == 0 {
+					continue
+				}
+				p.GameTypes = append(p.GameTypes, GameTypeProgress{
+					GameType:  string(gt),
+					AvgScore:  gts.AvgScore,
+					HighScore: gts.HighScore,
+					Sessions:  len(gts.Sessions),
+				})
+				for i := range gts.Sessions {
+					if ts := gts.Sessions[i].Timestamp; p.LastActiveAt == nil || ts.After(*p.LastActiveAt) {
+						p.LastActiveAt = &ts
+					}
+				}
+			}
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// Report returns every member's sessions, per game type averages and improvement over [from, to), and
+// when they were last active, for the owner's report. Members who did not play are listed with no sessions.
+func (s *GroupService) Report(ctx context.Context, groupID string, from, to time.Time) ([]MemberReport, error) {
+	if !to.After(from) {
+		return nil, &validation.Error{Field: "to", Message: "to must be >= from"}
+	}
+	if to.Sub(from) > maxGroupReportDays*24*time.Hour {
+		return nil, &validation.Error{Field: "from", Message: fmt.Sprintf("range must be at most %d days", maxGroupReportDays)}
+	}
+	members, users, err := s.members(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	activity, err := s.sessionRepo.AggregateActivity(ctx, memberIDs(members), from, to)
+	if err != nil {
+		return nil, err
+	}
+	byUser := make(map[string]*entity.MemberActivity, len(activity))
+	for _, a := range activity {
+		byUser[a.UserID] = a
+	}
+
+	out := make([]MemberReport, 0, len(members))
+	for _, m := range members {
+		user := users[m.UserID]
+		if user == nil {
+			continue
+		}
+		row := MemberReport{Member: m, User: user, Domains: []entity.DomainActivity{}}
+		if a := byUser[m.UserID]; a != nil {
+			row.Sessions, row.LastActiveAt = a.Sessions, a.LastActiveAt
+			for _, gt := range game.AllGameTypes {
+				for _, d := range a.Domains {
+					if d.GameType == string(gt) && d.Sessions > 0 {
+						row.Domains = append(row.Domains, d)
+					}
+				}
+			}
+		}
+		out = append(out, row)
+	}
+	return out, nil
+}
+
+// members returns the group's memberships and their users keyed by user id.
+func (s *GroupService) members(ctx context.Context, groupID string) ([]*entity.GroupMember, map[string]*entity.User, error) {
+	members, err := s.groupRepo.FindMembers(ctx, groupID)
+	if err != nil {
+		return nil, nil, err
+	}
+	users, err := s.userService.FindByUserIDs(ctx, memberIDs(members))
+	if err != nil {
+		return nil, nil, err
+	}
+	return members, users, nil
+}
+
+// checkGroupLimit returns ErrGroupLimit when the user is already in groups.max_groups_per_user groups.
+func (s *GroupService) checkGroupLimit(ctx context.Context, userID string) error {
+	n, err := s.groupRepo.CountMemberships(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if n >= int64(s.cfg.MaxGroupsPerUser) {
+		return ErrGroupLimit
+	}
+	return nil
+}
+
+func memberIDs(members []*entity.GroupMember) []string {
+	ids := make([]string, 0, len(members))
+	for _, m := range members {
+		ids = append(ids, m.UserID)
+	}
+	return ids
+}
+
+// validateGroupName trims name and checks its length.
+func validateGroupName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxGroupNameLength {
+		return "", &validation.Error{Field: "name", Message: fmt.Sprintf("name must be 1 to %d characters", maxGroupNameLength)}
+	}
+	return name, nil
+}
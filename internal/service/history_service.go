@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"brainbash_backend/internal/repository"
+	"brainbash_backend/internal/validation"
+)
+
+// Bucket sizes accepted by GetHistory.
+const (
+	BucketDay   = "day"
+	BucketWeek  = "week"
+	BucketMonth = "month"
+)
+
+// maxHistoryBuckets bounds the buckets of one history, whatever their size.
+const maxHistoryBuckets = 400
+
+// HistoryBucket aggregates the sessions that started in [Start, next bucket).
+type HistoryBucket struct {
+	Start    time.Time
+	Count    int
+	Mean     float64
+	Best     float64
+	Accuracy float64 // mean accuracy (0–1)
+	AvgTime  float64 // mean avgTime in seconds
+}
+
+// History is a user's bucketed progress in one game type with a least-squares trend.
+type History struct {
+	GameType string
+	Bucket   string
+	From     time.Time
+	To       time.Time
+	Buckets  []HistoryBucket
+	// SlopePerDay is the fitted change in session score per day over all sessions in range.
+	SlopePerDay float64
+	// SlopePerBucket is the fitted change in bucket mean per bucket (over non-empty buckets).
+	SlopePerBucket float64
+}
+
+// HistoryService builds progress charts from the per-session timestamps in the sessions collection.
+type HistoryService struct {
+	sessionRepo *repository.SessionRepository
+}
+
+// NewHistoryService creates a new HistoryService.
+func NewHistoryService(sessionRepo *repository.SessionRepository) *HistoryService {
+	return &HistoryService{sessionRepo: sessionRepo}
+}
+
+// GetHistory returns per-bucket aggregates of the user's sessions of gameType within [from, to] (UTC).
+// Every bucket in range is returned, empty ones with Count 0, so charts have a continuous x-axis.
+// Invalid input, including ranges of more than maxHistoryBuckets buckets, is a *validation.Error.
+func (s *HistoryService) GetHistory(ctx context.Context, userID, gameType, bucket string, from, to time.Time) (*History, error) {
+	if err := ValidateGameType(ctx, gameType); err != nil {
+		return nil, err
+	}
+	if bucket != BucketDay && bucket != BucketWeek && bucket != BucketMonth {
+		return nil, &validation.Error{Field: "bucket", Message: fmt.Sprintf("invalid bucket %q (allowed: day, week, month)", bucket)}
+	}
+	if n := bucketCount(from, to, bucket); n > maxHistoryBuckets {
+		return nil, &validation.Error{Field: "from", Message: fmt.Sprintf("range too large for bucket=%s: %d buckets (max %d)", bucket, n, maxHistoryBuckets)}
+	}
+
+	sessions, err := s.sessionRepo.FindInRange(ctx, userID, gameType, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &History{GameType: gameType, Bucket: bucket, From: from, To: to}
+	index := make(map[time.Time]int)
+	for start := bucketStart(from, bucket); !start.After(to); start = nextBucket(start, bucket) {
+		index[start] = len(history.Buckets)
+		history.Buckets = append(history.Buckets, HistoryBucket{Start: start})
+	}
+
+	var xs, ys []float64
+	for _, se := range sessions {
+		i, ok := index[bucketStart(se.Timestamp, bucket)]
+		if !ok {
+			continue
+		}
+		b := &history.Buckets[i]
+		b.Count++
+		b.Mean += se.SessionScore.Score
+		b.Accuracy += se.SessionScore.Accuracy
+		b.AvgTime += se.SessionScore.AvgTime
+		if se.SessionScore.Score > b.Best {
+			b.Best = se.SessionScore.Score
+		}
+		xs = append(xs, se.Timestamp.Sub(from).Hours()/24)
+		ys = append(ys, se.SessionScore.Score)
+	}
+
+	var bx, by []float64
+	for i := range history.Buckets {
+		b := &history.Buckets[i]
+		if b.Count == 0 {
+			continue
+		}
+		n := float64(b.Count)
+		b.Mean /= n
+		b.Accuracy /= n
+		b.AvgTime /= n
+		bx = append(bx, float64(i))
+		by = append(by, b.Mean)
+	}
+	history.SlopePerDay = linearSlope(xs, ys)
+	history.SlopePerBucket = linearSlope(bx, by)
+	return history, nil
+}
+
+// bucketStart truncates t (UTC) to the start of its day, ISO week (Monday) or month.
+func bucketStart(t time.Time, bucket string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch bucket {
+	case BucketWeek:
+		offset := (int(day.Weekday()) + 6) % 7 // days since Monday
+		return day.AddDate(0, 0, -offset)
+	case BucketMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// bucketCount returns how many buckets [from, to] spans (0 when to is before from), without building them.
+func bucketCount(from, to time.Time, bucket string) int {
+	first, last := bucketStart(from, bucket), bucketStart(to, bucket)
+	if last.Before(first) {
+		return 0
+	}
+	switch bucket {
+	case BucketWeek:
+		return int(last.Sub(first).Hours()/24)/7 + 1
+	case BucketMonth:
+		return (last.Year()-first.Year())*12 + int(last.Month()-first.Month()) + 1
+	default:
+		return int(last.Sub(first).Hours()/24) + 1
+	}
+}
+
+func nextBucket(start time.Time, bucket string) time.Time {
+	switch bucket {
+	case BucketWeek:
+		return start.AddDate(0, 0, 7)
+	case BucketMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// linearSlope returns the least-squares slope of ys over xs (0 with fewer than two distinct xs).
+func linearSlope(xs, ys []float64) float64 {
+	n := float64(len(xs))
+	if n < 2 {
+		return 0
+	}
+	var sx, sy, sxx, sxy float64
+	for i := range xs {
+		sx += xs[i]
+		sy += ys[i]
+		sxx += xs[i] * xs[i]
+		sxy += xs[i] * ys[i]
+	}
+	denom := n*sxx - sx*sx
+	if denom == 0 {
+		return 0
+	}
+	return (n*sxy - sx*sy) / denom
+}
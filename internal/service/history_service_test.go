@@ -0,0 +1,123 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func utcDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestBucketStart(t *testing.T) {
+	tests := []struct {
+		name   string
+		t      time.Time
+		bucket string
+		want   time.Time
+	}{
+		{name: "day truncates the time", t: time.Date(2024, 3, 14, 15, 9, 26, 0, time.UTC), bucket: BucketDay, want: utcDate(2024, 3, 14)},
+		{name: "day of another zone uses its UTC day", t: time.Date(2024, 3, 14, 1, 0, 0, 0, time.FixedZone("UTC+2", 2*3600)), bucket: BucketDay, want: utcDate(2024, 3, 13)},
+		{name: "week on a Monday", t: utcDate(2024, 3, 11), bucket: BucketWeek, want: utcDate(2024, 3, 11)},
+		{name: "week on a Sunday", t: time.Date(2024, 3, 17, 23, 59, 0, 0, time.UTC), bucket: BucketWeek, want: utcDate(2024, 3, 11)},
+		{name: "week across a month", t: utcDate(2024, 3, 2), bucket: BucketWeek, want: utcDate(2024, 2, 26)},
+		{name: "week across a year", t: utcDate(2025, 1, 1), bucket: BucketWeek, want: utcDate(2024, 12, 30)},
+		{name: "month", t: time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC), bucket: BucketMonth, want: utcDate(2024, 2, 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bucketStart(tt.t, tt.bucket); !got.Equal(tt.want) {
+				t.Errorf("bucketStart(%v, %s) = %v, want %v", tt.t, tt.bucket, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextBucket(t *testing.T) {
+	tests := []struct {
+		name   string
+		start  time.Time
+		bucket string
+		want   time.Time
+	}{
+		{name: "day", start: utcDate(2024, 2, 28), bucket: BucketDay, want: utcDate(2024, 2, 29)},
+		{name: "day across a year", start: utcDate(2024, 12, 31), bucket: BucketDay, want: utcDate(2025, 1, 1)},
+		{name: "week", start: utcDate(2024, 2, 26), bucket: BucketWeek, want: utcDate(2024, 3, 4)},
+		{name: "month", start: utcDate(2024, 1, 1), bucket: BucketMonth, want: utcDate(2024, 2, 1)},
+		{name: "month across a year", start: utcDate(2024, 12, 1), bucket: BucketMonth, want: utcDate(2025, 1, 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextBucket(tt.start, tt.bucket); !got.Equal(tt.want) {
+				t.Errorf("nextBucket(%v, %s) = %v, want %v", tt.start, tt.bucket, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBucketCount(t *testing.T) {
+	endOfDay := 24*time.Hour - time.Nanosecond
+	tests := []struct {
+		name     string
+		from, to time.Time
+		bucket   string
+		want     int
+	}{
+		{name: "to before from", from: utcDate(2024, 3, 2), to: utcDate(2024, 3, 1), bucket: BucketDay, want: 0},
+		{name: "one day", from: utcDate(2024, 3, 1), to: utcDate(2024, 3, 1).Add(endOfDay), bucket: BucketDay, want: 1},
+		{name: "leap February", from: utcDate(2024, 2, 1), to: utcDate(2024, 2, 29).Add(endOfDay), bucket: BucketDay, want: 29},
+		{name: "a year of days", from: utcDate(2023, 1, 1), to: utcDate(2023, 12, 31).Add(endOfDay), bucket: BucketDay, want: 365},
+		{name: "weeks of a partial first and last week", from: utcDate(2024, 3, 6), to: utcDate(2024, 3, 18), bucket: BucketWeek, want: 3},
+		{name: "one week", from: utcDate(2024, 3, 11), to: utcDate(2024, 3, 17).Add(endOfDay), bucket: BucketWeek, want: 1},
+		{name: "months across a year", from: utcDate(2023, 11, 15), to: utcDate(2024, 2, 3), bucket: BucketMonth, want: 4},
+		{name: "a century of months", from: utcDate(1924, 1, 1), to: utcDate(2023, 12, 31), bucket: BucketMonth, want: 1200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bucketCount(tt.from, tt.to, tt.bucket); got != tt.want {
+				t.Errorf("bucketCount(%v, %v, %s) = %d, want %d", tt.from, tt.to, tt.bucket, got, tt.want)
+			}
+		})
+	}
+}
+
+// bucketCount must agree with the loop GetHistory builds its buckets with.
+func TestBucketCountMatchesBuckets(t *testing.T) {
+	from := time.Date(2023, 12, 20, 8, 0, 0, 0, time.UTC)
+	for _, bucket := range []string{BucketDay, BucketWeek, BucketMonth} {
+		for days := 0; days < 120; days++ {
+			to := from.AddDate(0, 0, days)
+			n := 0
+			for start := bucketStart(from, bucket); !start.After(to); start = nextBucket(start, bucket) {
+				n++
+			}
+			if got := bucketCount(from, to, bucket); got != n {
+				t.Fatalf("bucketCount(%v, %v, %s) = %d, GetHistory builds %d", from, to, bucket, got, n)
+			}
+		}
+	}
+}
+
+func TestLinearSlope(t *testing.T) {
+	tests := []struct {
+		name   string
+		xs, ys []float64
+		want   float64
+	}{
+		{name: "no points", want: 0},
+		{name: "one point", xs: []float64{1}, ys: []float64{5}, want: 0},
+		{name: "same x", xs: []float64{2, 2, 2}, ys: []float64{1, 5, 9}, want: 0},
+		{name: "rising line", xs: []float64{0, 1, 2, 3}, ys: []float64{10, 12, 14, 16}, want: 2},
+		{name: "falling line", xs: []float64{0, 2, 4}, ys: []float64{9, 6, 3}, want: -1.5},
+		{name: "flat", xs: []float64{0, 1, 2}, ys: []float64{7, 7, 7}, want: 0},
+		{name: "noisy", xs: []float64{0, 1, 2, 3}, ys: []float64{1, 3, 2, 4}, want: 0.8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := linearSlope(tt.xs, tt.ys); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("linearSlope = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
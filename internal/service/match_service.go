@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"brainbash_backend/config"
+	"brainbash_backend/internal/match"
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/model/request"
+	"brainbash_backend/internal/repository"
+	"brainbash_backend/internal/scoring"
+	"brainbash_backend/internal/tenant"
+	"brainbash_backend/internal/validation"
+)
+
+const (
+	defaultMatchQuestions    = 10
+	defaultMatchQuestionTime = 10 * time.Second
+	defaultMatchStartDelay   = 3 * time.Second
+	defaultMatchMaxPlayers   = 4
+	defaultMatchMaxQueueWait = 2 * time.Minute
+	defaultMatchSkillWindow  = 100 // rating points
+	defaultMatchSkillWiden   = 5
+	minMatchPlayers          = 2
+)
+
+// ErrMatchNotFound is returned when the match does not exist or the user did not play in it.
+var ErrMatchNotFound = errors.New("match not found")
+
+// MatchService runs real-time matches: it queues connected players by rating, lets the match hub play
+// the questions in lockstep, and stores each player's session tagged with the match.
+type MatchService struct {
+	matchRepo     *repository.MatchRepository
+	scoreService  *ScoreService
+	userService   *UserService
+	ratingService *RatingService
+	hub           *match.Hub
+	maxPlayers    int
+}
+
+// NewMatchService creates a new MatchService.
+func NewMatchService(matchRepo *repository.MatchRepository, scoreService *ScoreService, userService *UserService, ratingService *RatingService, scorer *scoring.Scorer, cfg config.MatchesConfig) *MatchService {
+	if cfg.Questions <= 0 {
+		cfg.Questions = defaultMatchQuestions
+	}
+	if cfg.QuestionTime <= 0 {
+		cfg.QuestionTime = defaultMatchQuestionTime
+	}
+	if cfg.StartDelay <= 0 {
+		cfg.StartDelay = defaultMatchStartDelay
+	}
+	if cfg.MaxPlayers < minMatchPlayers {
+		cfg.MaxPlayers = defaultMatchMaxPlayers
+	}
+	if cfg.MaxQueueWait <= 0 {
+		cfg.MaxQueueWait = defaultMatchMaxQueueWait
+	}
+	if cfg.SkillWindow <= 0 {
+		cfg.SkillWindow = defaultMatchSkillWindow
+	}
+	if cfg.SkillWidening <= 0 {
+		cfg.SkillWidening = defaultMatchSkillWiden
+	}
+	s := &MatchService{
+		matchRepo:     matchRepo,
+		scoreService:  scoreService,
+		userService:   userService,
+		ratingService: ratingService,
+		maxPlayers:    cfg.MaxPlayers,
+	}
+	s.hub = match.NewHub(match.Config{
+		Questions:     cfg.Questions,
+		QuestionTime:  cfg.QuestionTime,
+		StartDelay:    cfg.StartDelay,
+		MaxQueueWait:  cfg.MaxQueueWait,
+		SkillWindow:   cfg.SkillWindow,
+		SkillWidening: cfg.SkillWidening,
+	}, scorer, s.record)
+	return s
+}
+
+// CheckQueue validates a queue request before the connection is upgraded.
+func (s *MatchService) CheckQueue(ctx context.Context, gameType string, players int) error {
+	if err := ValidateGameType(ctx, gameType); err != nil {
+		return err
+	}
+	if players < minMatchPlayers || players > s.maxPlayers {
+		return &validation.Error{Field: "players", Message: fmt.Sprintf("must be between %d and %d", minMatchPlayers, s.maxPlayers)}
+	}
+	return nil
+}
+
+// Play queues the user for a match of players on gameType and blocks until their match is over or they
+// leave. The caller owns conn and closes it afterwards. Returns match.ErrAlreadyPlaying when the user
+// is already queued or playing on another connection.
+func (s *MatchService) Play(ctx context.Context, userID, gameType string, players int, conn match.Conn) error {
+	if err := s.CheckQueue(ctx, gameType, players); err != nil {
+		return err
+	}
+	name := ""
+	if user, err := s.userService.FindByUserID(ctx, userID); err == nil && user != nil {
+		name = user.PublicName()
+	}
+	rating, err := s.ratingService.Get(ctx, userID, gameType)
+	if err != nil {
+		return err
+	}
+
+	p := match.NewPlayer(tenant.FromContext(ctx), userID, name, gameType, players, rating.Rating, conn)
+	if err := s.hub.Join(p); err != nil {
+		return err
+	}
+	<-p.Done()
+	return nil
+}
+
+// Matchmake re-checks the queues so waiting players get wider skill windows or time out. Run periodically.
+func (s *MatchService) Matchmake(ctx context.Context) error {
+	return s.hub.Matchmake(ctx)
+}
+
+// Get returns a match the user played in.
+func (s *MatchService) Get(ctx context.Context, userID, matchID string) (*entity.Match, error) {
+	m, err := s.matchRepo.FindByID(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, ErrMatchNotFound
+	}
+	for _, p := range m.Players {
+		if p.UserID == userID {
+			return m, nil
+		}
+	}
+	return nil, ErrMatchNotFound
+}
+
+// List returns up to limit of the user's matches, most recent first.
+func (s *MatchService) List(ctx context.Context, userID string, limit int64) ([]*entity.Match, error) {
+	return s.matchRepo.FindByUserID(ctx, userID, limit)
+}
+
+// Players loads the users who played in matches, keyed by hex id. Users that no longer exist are missing.
+func (s *MatchService) Players(ctx context.Context, matches ...*entity.Match) (map[string]*entity.User, error) {
+	var ids []string
+	for _, m := range matches {
+		for _, p := range m.Players {
+			ids = append(ids, p.UserID)
+		}
+	}
+	return s.userService.FindByUserIDs(ctx, ids)
+}
+
+// record stores every remaining player's session, scored and validated like a regular submission and
+// tagged with the match, then the match itself, and rates it. Returns the stored session id per user.
+func (s *MatchService) record(ctx context.Context, r *match.Result) map[string]string {
+	sessionIDs := make(map[string]string, len(r.Players))
+	m := &entity.Match{
+		ID:        r.MatchID,
+		GameType:  r.GameType,
+		Seed:      r.Seed,
+		Questions: r.Questions,
+		Players:   make([]entity.MatchPlayer, 0, len(r.Players)),
+		StartedAt: r.StartedAt,
+		EndedAt:   r.EndedAt,
+	}
+	for _, p := range r.Players {
+		mp := entity.MatchPlayer{UserID: p.UserID, Place: p.Place, Left: p.Left}
+		if !p.Left && len(p.Responses) > 0 {
+			// The key makes a retried record a replay instead of a second session
+			result, _, err := s.scoreService.SubmitTaggedResult(ctx, p.UserID, "match:"+r.MatchID, request.GameResultRequest{
+				GameType:          r.GameType,
+				QuestionResponses: p.Responses,
+			}, entity.SessionTags{MatchID: r.MatchID})
+			if err != nil {
+				log.Printf("Matches: store session of %s in match %s: %v", p.UserID, r.MatchID, err)
+			} else {
+				mp.SessionID, mp.SessionScore, mp.Flagged = result.SessionID, result.SessionScore, result.Flagged
+				sessionIDs[p.UserID] = result.SessionID
+			}
+		}
+		m.Players = append(m.Players, mp)
+	}
+	if err := s.matchRepo.Insert(ctx, m); err != nil {
+		log.Printf("Matches: store match %s: %v", r.MatchID, err)
+		return sessionIDs
+	}
+	if err := s.ratingService.RateMatch(ctx, m); err != nil {
+		log.Printf("Matches: rate match %s: %v", r.MatchID, err)
+	}
+	return sessionIDs
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"brainbash_backend/config"
+	"brainbash_backend/internal/game"
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/repository"
+)
+
+const defaultNormMinSamples = 50
+
+// NormsService builds per-game-type score distributions from stored sessions and
+// answers percentile lookups against them.
+type NormsService struct {
+	normRepo    *repository.NormRepository
+	scoreRepo   *repository.ScoreRepository
+	userService *UserService
+	minSamples  int
+	ageBands    []int
+
+	mu     sync.RWMutex
+	norms  map[string]*entity.Norm // keyed by entity.NormID
+	loaded bool
+}
+
+// NewNormsService creates a new NormsService.
+func NewNormsService(normRepo *repository.NormRepository, scoreRepo *repository.ScoreRepository, userService *UserService, cfg config.NormsConfig) *NormsService {
+	minSamples := cfg.MinSamples
+	if minSamples <= 0 {
+		minSamples = defaultNormMinSamples
+	}
+	bands := append([]int(nil), cfg.AgeBands...)
+	sort.Ints(bands)
+	return &NormsService{
+		normRepo:    normRepo,
+		scoreRepo:   scoreRepo,
+		userService: userService,
+		minSamples:  minSamples,
+		ageBands:    bands,
+		norms:       make(map[string]*entity.Norm),
+	}
+}
+
+// Rebuild recomputes every percentile table from all leaderboard-eligible sessions and persists them.
+// Age-band tables are only stored when they have at least min_samples sessions.
+func (s *NormsService) Rebuild(ctx context.Context) error {
+	scores, err := s.scoreRepo.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	birthYears, err := s.userService.FindBirthYears(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	samples := make(map[string][]float64) // keyed by entity.NormID
+	for _, score := range scores {
+		band := s.ageBand(birthYears[score.UserID], now)
+		for _, gt := range game.AllGameTypes {
+			gts := getGameTypeScore(score, string(gt))
+			if gts == nil {
+				continue
+			}
+			for _, se := range gts.Sessions {
+				if !se.LeaderboardEligible() {
+					continue
+				}
+				all := entity.NormID(string(gt), entity.AgeBandAll)
+				samples[all] = append(samples[all], se.SessionScore.Score)
+				if band != entity.AgeBandAll {
+					key := entity.NormID(string(gt), band)
+					samples[key] = append(samples[key], se.SessionScore.Score)
+				}
+			}
+		}
+	}
+
+	built := make(map[string]*entity.Norm)
+	for _, gt := range game.AllGameTypes {
+		for _, band := range s.bandLabels() {
+			values := samples[entity.NormID(string(gt), band)]
+			if len(values) == 0 || (band != entity.AgeBandAll && len(values) < s.minSamples) {
+				continue
+			}
+			norm := &entity.Norm{
+				GameType:    string(gt),
+				AgeBand:     band,
+				SampleSize:  len(values),
+				Percentiles: percentileTable(values),
+				BuiltAt:     now,
+			}
+			if err := s.normRepo.Upsert(ctx, norm); err != nil {
+				return err
+			}
+			built[norm.ID] = norm
+		}
+	}
+
+	s.mu.Lock()
+	s.norms = built
+	s.loaded = true
+	s.mu.Unlock()
+	log.Printf("Norms rebuilt: %d tables from %d score documents", len(built), len(scores))
+	return nil
+}
+
+// Percentile returns the percentile (0–100) of score among all players of the game type, using the
+// user's age band when a table exists for it. Returns nil when no table has been built yet.
+func (s *NormsService) Percentile(ctx context.Context, gameType, ageBand string, score float64) *float64 {
+	if err := s.ensureLoaded(ctx); err != nil {
+		log.Printf("Norms: load failed: %v", err)
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	norm, ok := s.norms[entity.NormID(gameType, ageBand)]
+	if !ok {
+		norm, ok = s.norms[entity.NormID(gameType, entity.AgeBandAll)]
+	}
+	if !ok {
+		return nil
+	}
+	p := percentileOf(norm.Percentiles, score)
+	return &p
+}
+
+// AgeBandFor returns the age band label for the user ("all" when the birth year is unknown).
+func (s *NormsService) AgeBandFor(ctx context.Context, userID string) string {
+	user, err := s.userService.FindByUserID(ctx, userID)
+	if err != nil || user == nil {
+		return entity.AgeBandAll
+	}
+	return s.ageBand(user.BirthYear, time.Now().UTC())
+}
+
+func (s *NormsService) ensureLoaded(ctx context.Context) error {
+	s.mu.RLock()
+	loaded := s.loaded
+	s.mu.RUnlock()
+	if loaded {
+		return nil
+	}
+
+	norms, err := s.normRepo.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, n := range norms {
+		s.norms[n.ID] = n
+	}
+	s.loaded = true
+	return nil
+}
+
+// ageBand maps a birth year to a band label such as "25-34", "65+" or "under_18".
+func (s *NormsService) ageBand(birthYear int, now time.Time) string {
+	if birthYear <= 0 || len(s.ageBands) == 0 {
+		return entity.AgeBandAll
+	}
+	age := now.Year() - birthYear
+	if age < s.ageBands[0] {
+		return fmt.Sprintf("under_%d", s.ageBands[0])
+	}
+	for i := len(s.ageBands) - 1; i >= 0; i-- {
+		if age >= s.ageBands[i] {
+			if i == len(s.ageBands)-1 {
+				return fmt.Sprintf("%d+", s.ageBands[i])
+			}
+			return fmt.Sprintf("%d-%d", s.ageBands[i], s.ageBands[i+1]-1)
+		}
+	}
+	return entity.AgeBandAll
+}
+
+// bandLabels returns "all" followed by every configured age band label.
+func (s *NormsService) bandLabels() []string {
+	labels := []string{entity.AgeBandAll}
+	if len(s.ageBands) == 0 {
+		return labels
+	}
+	labels = append(labels, fmt.Sprintf("under_%d", s.ageBands[0]))
+	for i, lo := range s.ageBands {
+		if i == len(s.ageBands)-1 {
+			labels = append(labels, fmt.Sprintf("%d+", lo))
+		} else {
+			labels = append(labels, fmt.Sprintf("%d-%d", lo, s.ageBands[i+1]-1))
+		}
+	}
+	return labels
+}
+
+// percentileTable returns the score at each percentile 0..100 (nearest-rank with linear interpolation).
+func percentileTable(values []float64) []float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	table := make([]float64, 101)
+	last := float64(len(sorted) - 1)
+	for p := 0; p <= 100; p++ {
+		pos := last * float64(p) / 100
+		lo := int(pos)
+		if lo >= len(sorted)-1 {
+			table[p] = sorted[len(sorted)-1]
+			continue
+		}
+		frac := pos - float64(lo)
+		table[p] = sorted[lo] + frac*(sorted[lo+1]-sorted[lo])
+	}
+	return table
+}
+
+// percentileOf returns the percentile rank (0–100) of score in a table built by percentileTable.
+// Ties resolve to the highest percentile sharing the score.
+func percentileOf(table []float64, score float64) float64 {
+	if len(table) == 0 {
+		return 0
+	}
+	last := len(table) - 1
+	if score < table[0] {
+		return 0
+	}
+	if score >= table[last] {
+		return float64(last)
+	}
+	// First index whose value exceeds score; the rank lies between i-1 and i.
+	i := sort.Search(len(table), func(i int) bool { return table[i] > score })
+	lo, hi := table[i-1], table[i]
+	return float64(i-1) + (score-lo)/(hi-lo)
+}
@@ -0,0 +1,486 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"brainbash_backend/config"
+	"brainbash_backend/internal/event"
+	"brainbash_backend/internal/game"
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/notify"
+	"brainbash_backend/internal/repository"
+	"brainbash_backend/internal/tenant"
+	"brainbash_backend/internal/validation"
+)
+
+const (
+	defaultNotifyBatchSize    = 100
+	defaultNotifyMaxAttempts  = 5
+	defaultNotifyRetryBackoff = time.Minute
+	maxNotifyRetryBackoff     = 24 * time.Hour
+	defaultNotifySendTimeout  = 10 * time.Second
+	defaultStreakRiskHour     = 18
+)
+
+// NotificationPreferences are a user's saved or default preferences and the channels they may choose from.
+type NotificationPreferences struct {
+	Channels          []string
+	DisabledKinds     []string
+	QuietStart        string
+	QuietEnd          string
+	AvailableChannels []string
+}
+
+// NotificationService turns domain events and the streak reminder into outbox notifications and
+// delivers them through the configured channels, honouring preferences and quiet hours.
+type NotificationService struct {
+	notificationRepo   *repository.NotificationRepository
+	prefsRepo          *repository.NotificationPreferencesRepository
+	streakRepo         *repository.StreakRepository
+	goalRepo           *repository.GoalRepository
+	digestRepo         *repository.DigestRepository
+	duelRepo           *repository.DuelRepository
+	userService        *UserService
+	achievementService *AchievementService
+	tenants            *TenantService
+	channels           *notify.Registry
+	renderer           *notify.Renderer
+	cfg                config.NotificationsConfig
+}
+
+// NewNotificationService creates a new NotificationService. Default channels that are not configured
+// are dropped.
+func NewNotificationService(notificationRepo *repository.NotificationRepository, prefsRepo *repository.NotificationPreferencesRepository, streakRepo *repository.StreakRepository, goalRepo *repository.GoalRepository, digestRepo *repository.DigestRepository, duelRepo *repository.DuelRepository, userService *UserService, achievementService *AchievementService, tenants *TenantService, channels *notify.Registry, renderer *notify.Renderer, cfg config.NotificationsConfig) *NotificationService {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultNotifyBatchSize
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultNotifyMaxAttempts
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = defaultNotifyRetryBackoff
+	}
+	if cfg.SendTimeout <= 0 {
+		cfg.SendTimeout = defaultNotifySendTimeout
+	}
+	if cfg.StreakRisk.Hour <= 0 || cfg.StreakRisk.Hour > 23 {
+		cfg.StreakRisk.Hour = defaultStreakRiskHour
+	}
+	defaults := make([]string, 0, len(cfg.DefaultChannels))
+	for _, name := range cfg.DefaultChannels {
+		if channels.Get(name) == nil {
+			log.Printf("Notifications: default channel %q is not configured", name)
+			continue
+		}
+		defaults = append(defaults, name)
+	}
+	cfg.DefaultChannels = defaults
+	return &NotificationService{
+		notificationRepo:   notificationRepo,
+		prefsRepo:          prefsRepo,
+		streakRepo:         streakRepo,
+		goalRepo:           goalRepo,
+		digestRepo:         digestRepo,
+		duelRepo:           duelRepo,
+		userService:        userService,
+		achievementService: achievementService,
+		tenants:            tenants,
+		channels:           channels,
+		renderer:           renderer,
+		cfg:                cfg,
+	}
+}
+
+// Subscribe registers the service for the events users are notified about.
+func (s *NotificationService) Subscribe() {
+	event.Subscribe(event.AchievementUnlocked, "notifications", s.onAchievementUnlocked)
+	event.Subscribe(event.GoalMet, "notifications", s.onGoalMet)
+	event.Subscribe(event.DigestReady, "notifications", s.onDigestReady)
+	event.Subscribe(event.RankLost, "notifications", s.onRankLost)
+	event.Subscribe(event.DuelReceived, "notifications", s.onDuelReceived)
+	event.Subscribe(event.DuelCompleted, "notifications", s.onDuelCompleted)
+}
+
+// Enqueue renders a notification of kind and adds it to the outbox once per channel the user receives
+// it on. dedupKey identifies the notice: enqueuing the same key again is a no-op.
+func (s *NotificationService) Enqueue(ctx context.Context, userID, kind, dedupKey string, data map[string]string) error {
+	prefs, err := s.preferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(prefs.DisabledKinds, kind) {
+		return nil
+	}
+	subject, body, err := s.renderer.Render(kind, data)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for _, channel := range prefs.Channels {
+		if s.channels.Get(channel) == nil {
+			continue
+		}
+		_, err := s.notificationRepo.Insert(ctx, &entity.Notification{
+			ID:            bson.NewObjectID().Hex(),
+			UserID:        userID,
+			Kind:          kind,
+			Channel:       channel,
+			DedupKey:      dedupKey,
+			Subject:       subject,
+			Body:          body,
+			Data:          data,
+			Status:        entity.NotificationPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Dispatch delivers up to batch_size due notifications. Failed deliveries are retried with exponential
+// backoff until max_attempts; notifications due during the user's quiet hours wait until they end.
+func (s *NotificationService) Dispatch(ctx context.Context) error {
+	prefsCache := make(map[string]*entity.NotificationPreferences)
+	for i := 0; i < s.cfg.BatchSize; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		n, err := s.notificationRepo.ClaimDue(ctx, now, now.Add(2*s.cfg.SendTimeout))
+		if err != nil {
+			return err
+		}
+		if n == nil {
+			return nil
+		}
+		if err := s.deliver(ctx, n, prefsCache); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deliver sends one claimed notification and records the outcome. Only storage errors are returned.
+func (s *NotificationService) deliver(ctx context.Context, n *entity.Notification, prefsCache map[string]*entity.NotificationPreferences) error {
+	prefs, ok := prefsCache[n.UserID]
+	if !ok {
+		var err error
+		if prefs, err = s.preferences(ctx, n.UserID); err != nil {
+			return err
+		}
+		prefsCache[n.UserID] = prefs
+	}
+	if until, quiet := quietUntil(time.Now().In(s.userService.Location(ctx, n.UserID)), prefs.QuietStart, prefs.QuietEnd); quiet {
+		return s.notificationRepo.Reschedule(ctx, n.ID, n.Attempts, until.UTC(), "")
+	}
+
+	channel := s.channels.Get(n.Channel)
+	if channel == nil {
+		return s.notificationRepo.MarkFailed(ctx, n.ID, n.Attempts, "channel not configured")
+	}
+	user, err := s.userService.FindByUserID(ctx, n.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return s.notificationRepo.MarkFailed(ctx, n.ID, n.Attempts, "user not found")
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, s.cfg.SendTimeout)
+	defer cancel()
+	attempts := n.Attempts + 1
+	err = channel.Send(sendCtx, notify.Message{
+		ID:      n.ID,
+		Kind:    n.Kind,
+		To:      notify.Recipient{UserID: n.UserID, Name: user.PublicName(), Email: user.Email},
+		Subject: n.Subject,
+		Body:    n.Body,
+		Data:    n.Data,
+	})
+	if err == nil {
+		return s.notificationRepo.MarkSent(ctx, n.ID, attempts, time.Now().UTC())
+	}
+	log.Printf("Notifications: %s via %s attempt %d failed: %v", n.ID, n.Channel, attempts, err)
+	if errors.Is(err, notify.ErrNoAddress) || attempts >= s.cfg.MaxAttempts {
+		return s.notificationRepo.MarkFailed(ctx, n.ID, attempts, err.Error())
+	}
+	return s.notificationRepo.Reschedule(ctx, n.ID, attempts, time.Now().UTC().Add(retryBackoff(s.cfg.RetryBackoff, attempts)), err.Error())
+}
+
+// retryBackoff returns the delay before retrying after the given number of attempts: base doubled per
+// attempt after the first, capped at maxNotifyRetryBackoff.
+func retryBackoff(base time.Duration, attempts int) time.Duration {
+	backoff := base
+	for i := 1; i < attempts && backoff < maxNotifyRetryBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxNotifyRetryBackoff)
+}
+
+// CheckStreakRisk reminds users whose streak ends at midnight: they played yesterday but not yet today,
+// and it is past streak_risk.hour in their timezone. Each user is reminded at most once a day.
+func (s *NotificationService) CheckStreakRisk(ctx context.Context) error {
+	// Local "yesterday" is within a day of UTC yesterday for every timezone
+	now := time.Now().UTC()
+	days := []string{
+		now.AddDate(0, 0, -2).Format(entity.StreakDayLayout),
+		now.AddDate(0, 0, -1).Format(entity.StreakDayLayout),
+		now.Format(entity.StreakDayLayout),
+	}
+	streaks, err := s.streakRepo.FindByLastPlayedDays(ctx, days, max(s.cfg.StreakRisk.MinStreak, 1))
+	if err != nil {
+		return err
+	}
+	for _, streak := range streaks {
+		local := now.In(s.userService.Location(ctx, streak.UserID))
+		if local.Hour() < s.cfg.StreakRisk.Hour || streak.LastPlayedDay != local.AddDate(0, 0, -1).Format(entity.StreakDayLayout) {
+			continue
+		}
+		today := local.Format(entity.StreakDayLayout)
+		err := s.Enqueue(ctx, streak.UserID, notify.KindStreakRisk, "streak_risk:"+streak.UserID+":"+today, map[string]string{
+			"streak": strconv.Itoa(streak.Current),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetPreferences returns the user's notification preferences.
+func (s *NotificationService) GetPreferences(ctx context.Context, userID string) (*NotificationPreferences, error) {
+	prefs, err := s.preferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.view(prefs), nil
+}
+
+// UpdatePreferences replaces the user's notification preferences. Channels must be configured and kinds
+// known; quiet hours are both set (HH:MM) or both empty.
+func (s *NotificationService) UpdatePreferences(ctx context.Context, userID string, in NotificationPreferences) (*NotificationPreferences, error) {
+	for _, c := range in.Channels {
+		if s.channels.Get(c) == nil {
+			return nil, &validation.Error{Field: "channels", Message: fmt.Sprintf("unknown channel %q", c)}
+		}
+	}
+	for _, k := range in.DisabledKinds {
+		if !slices.Contains(notify.Kinds, k) {
+			return nil, &validation.Error{Field: "disabled_kinds", Message: fmt.Sprintf("unknown kind %q", k)}
+		}
+	}
+	if (in.QuietStart == "") != (in.QuietEnd == "") {
+		return nil, &validation.Error{Field: "quiet_hours", Message: "quiet_start and quiet_end must both be set or both be empty"}
+	}
+	for _, t := range []string{in.QuietStart, in.QuietEnd} {
+		if _, err := time.Parse(entity.QuietHoursLayout, t); t != "" && err != nil {
+			return nil, &validation.Error{Field: "quiet_hours", Message: "must be HH:MM"}
+		}
+	}
+
+	prefs := &entity.NotificationPreferences{
+		UserID:        userID,
+		Channels:      slices.Compact(slices.Sorted(slices.Values(in.Channels))),
+		DisabledKinds: slices.Compact(slices.Sorted(slices.Values(in.DisabledKinds))),
+		QuietStart:    in.QuietStart,
+		QuietEnd:      in.QuietEnd,
+		UpdatedAt:     time.Now().UTC(),
+	}
+	if prefs.Channels == nil {
+		prefs.Channels = []string{}
+	}
+	if err := s.prefsRepo.Upsert(ctx, prefs); err != nil {
+		return nil, err
+	}
+	return s.view(prefs), nil
+}
+
+// List returns up to limit of the user's notifications, newest first.
+func (s *NotificationService) List(ctx context.Context, userID string, limit int64) ([]*entity.Notification, error) {
+	return s.notificationRepo.FindByUserID(ctx, userID, limit)
+}
+
+// preferences returns the user's saved preferences, or the defaults.
+func (s *NotificationService) preferences(ctx context.Context, userID string) (*entity.NotificationPreferences, error) {
+	prefs, err := s.prefsRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if prefs == nil {
+		prefs = &entity.NotificationPreferences{UserID: userID, Channels: s.cfg.DefaultChannels}
+	}
+	return prefs, nil
+}
+
+func (s *NotificationService) view(prefs *entity.NotificationPreferences) *NotificationPreferences {
+	return &NotificationPreferences{
+		Channels:          prefs.Channels,
+		DisabledKinds:     prefs.DisabledKinds,
+		QuietStart:        prefs.QuietStart,
+		QuietEnd:          prefs.QuietEnd,
+		AvailableChannels: s.channels.Names(),
+	}
+}
+
+func (s *NotificationService) onAchievementUnlocked(ctx context.Context, e event.Event) error {
+	data := map[string]string{"id": e.RefID, "name": e.RefID}
+	if rule, ok := s.achievementService.Rule(e.RefID); ok {
+		data["name"], data["description"] = rule.Name, rule.Description
+	}
+	return s.Enqueue(ctx, e.UserID, notify.KindAchievementUnlocked, "achievement:"+e.UserID+":"+e.RefID, data)
+}
+
+func (s *NotificationService) onGoalMet(ctx context.Context, e event.Event) error {
+	goal, err := s.goalRepo.FindByID(ctx, e.UserID, e.RefID)
+	if err != nil || goal == nil {
+		return err
+	}
+	data := map[string]string{"goal_id": goal.ID, "kind": goal.Kind, "goal": describeGoal(s.tenantOf(ctx, e.UserID), goal)}
+	return s.Enqueue(ctx, e.UserID, notify.KindGoalMet, "goal:"+goal.ID+":"+e.Timestamp.Format(entity.StreakDayLayout), data)
+}
+
+func (s *NotificationService) onDigestReady(ctx context.Context, e event.Event) error {
+	digest, err := s.digestRepo.FindByID(ctx, e.RefID)
+	if err != nil || digest == nil {
+		return err
+	}
+	data := map[string]string{
+		"week_start": digest.WeekStart.Format(entity.StreakDayLayout),
+		"sessions":   strconv.Itoa(digest.SessionsPlayed),
+	}
+	if len(digest.GoalsHit) > 0 {
+		data["goals_hit"] = strconv.Itoa(len(digest.GoalsHit))
+	}
+	if digest.OverallRank > 0 {
+		data["rank"] = strconv.FormatInt(digest.OverallRank, 10)
+		data["rank_change"] = strconv.FormatInt(digest.RankChange, 10)
+	}
+	return s.Enqueue(ctx, e.UserID, notify.KindWeeklyDigest, "digest:"+digest.ID, data)
+}
+
+// onRankLost notifies at most once per user, game type and day, however often the user is overtaken.
+func (s *NotificationService) onRankLost(ctx context.Context, e event.Event) error {
+	data := map[string]string{
+		"game_type": e.GameType,
+		"game":      s.tenantOf(ctx, e.UserID).Label(game.GameType(e.GameType)),
+		"rank":      strconv.Itoa(e.Rank),
+	}
+	key := "rank_lost:" + e.UserID + ":" + e.GameType + ":" + e.Timestamp.UTC().Format(entity.StreakDayLayout)
+	return s.Enqueue(ctx, e.UserID, notify.KindRankLost, key, data)
+}
+
+func (s *NotificationService) onDuelReceived(ctx context.Context, e event.Event) error {
+	duel, err := s.duelRepo.FindByID(ctx, e.RefID)
+	if err != nil || duel == nil {
+		return err
+	}
+	data := map[string]string{
+		"duel_id":    duel.ID,
+		"game_type":  duel.GameType,
+		"game":       s.tenantOf(ctx, e.UserID).Label(game.GameType(duel.GameType)),
+		"challenger": s.userName(ctx, duel.ChallengerID),
+		"expires_at": duel.ExpiresAt.Format(time.RFC1123),
+	}
+	return s.Enqueue(ctx, e.UserID, notify.KindDuelReceived, "duel_received:"+duel.ID, data)
+}
+
+func (s *NotificationService) onDuelCompleted(ctx context.Context, e event.Event) error {
+	duel, err := s.duelRepo.FindByID(ctx, e.RefID)
+	if err != nil || duel == nil {
+		return err
+	}
+	data := map[string]string{
+		"duel_id":   duel.ID,
+		"game_type": duel.GameType,
+		"game":      s.tenantOf(ctx, e.UserID).Label(game.GameType(duel.GameType)),
+		"opponent":  s.userName(ctx, duel.OtherID(e.UserID)),
+		"outcome":   "draw",
+	}
+	switch duel.WinnerID {
+	case "":
+	case e.UserID:
+		data["outcome"] = "won"
+	default:
+		data["outcome"] = "lost"
+	}
+	if duel.Forfeit {
+		data["forfeit"] = "true"
+	}
+	return s.Enqueue(ctx, e.UserID, notify.KindDuelResult, "duel_result:"+duel.ID+":"+e.UserID, data)
+}
+
+// userName returns the user's display name, or "A friend" when unknown.
+func (s *NotificationService) userName(ctx context.Context, userID string) string {
+	user, err := s.userService.FindByUserID(ctx, userID)
+	if err != nil || user == nil || user.PublicName() == "" {
+		return "A friend"
+	}
+	return user.PublicName()
+}
+
+// tenantOf returns the tenant of the user being notified, so game types carry the names it gives them: the
+// tenant in ctx, otherwise the user's. Falls back to the default tenant.
+func (s *NotificationService) tenantOf(ctx context.Context, userID string) *tenant.Tenant {
+	if t := tenant.FromContext(ctx); t != nil {
+		return t
+	}
+	id := tenant.DefaultID
+	if user, err := s.userService.FindByUserID(ctx, userID); err == nil && user != nil && user.TenantID != "" {
+		id = user.TenantID
+	}
+	if t := s.tenants.Get(ctx, id); t != nil {
+		return t
+	}
+	return s.tenants.Get(ctx, tenant.DefaultID)
+}
+
+// describeGoal returns a short human description of goal in tenant t, e.g. "play 3 sessions a day".
+func describeGoal(t *tenant.Tenant, goal *entity.Goal) string {
+	target := strconv.FormatFloat(goal.Target, 'f', -1, 64)
+	switch goal.Kind {
+	case entity.GoalDailySessions:
+		if goal.GameType != "" {
+			return "play " + target + " " + t.Label(game.GameType(goal.GameType)) + " sessions a day"
+		}
+		return "play " + target + " sessions a day"
+	case entity.GoalTargetScore:
+		return "reach " + target + " in " + t.Label(game.GameType(goal.GameType))
+	}
+	return goal.Kind
+}
+
+// quietUntil reports whether local falls within the quiet hours [start, end) (HH:MM, possibly overnight)
+// and, if so, when they end. Empty or equal bounds disable quiet hours.
+func quietUntil(local time.Time, start, end string) (time.Time, bool) {
+	s, err1 := time.Parse(entity.QuietHoursLayout, start)
+	e, err2 := time.Parse(entity.QuietHoursLayout, end)
+	if err1 != nil || err2 != nil || start == end {
+		return time.Time{}, false
+	}
+	startMin, endMin := s.Hour()*60+s.Minute(), e.Hour()*60+e.Minute()
+	cur := local.Hour()*60 + local.Minute()
+	endToday := time.Date(local.Year(), local.Month(), local.Day(), e.Hour(), e.Minute(), 0, 0, local.Location())
+	if startMin < endMin {
+		return endToday, cur >= startMin && cur < endMin
+	}
+	// Overnight, e.g. 22:00–07:00
+	switch {
+	case cur >= startMin:
+		return endToday.AddDate(0, 0, 1), true
+	case cur < endMin:
+		return endToday, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     time.Duration
+		attempts int
+		want     time.Duration
+	}{
+		{name: "first attempt", base: time.Minute, attempts: 1, want: time.Minute},
+		{name: "doubles per attempt", base: time.Minute, attempts: 4, want: 8 * time.Minute},
+		{name: "capped", base: time.Minute, attempts: 20, want: maxNotifyRetryBackoff},
+		{name: "no overflow with many attempts", base: time.Minute, attempts: 1000, want: maxNotifyRetryBackoff},
+		{name: "base above the cap", base: 48 * time.Hour, attempts: 3, want: maxNotifyRetryBackoff},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryBackoff(tt.base, tt.attempts); got != tt.want {
+				t.Errorf("retryBackoff(%v, %d) = %v, want %v", tt.base, tt.attempts, got, tt.want)
+			}
+		})
+	}
+}
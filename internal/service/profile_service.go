@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"brainbash_backend/config"
+	"brainbash_backend/internal/game"
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/repository"
+	"brainbash_backend/internal/tenant"
+)
+
+const defaultProfileHalfLife = 30 * 24 * time.Hour
+
+// DomainProfile is the decayed performance in one cognitive domain (game type).
+type DomainProfile struct {
+	GameType     string
+	Label        string
+	Score        float64 // decay-weighted mean session score, 0–100 (0 when never played)
+	Weight       float64 // weight of the domain in the composite
+	Sessions     int
+	LastPlayedAt *time.Time
+}
+
+// CognitiveProfile is the per-domain profile and the composite built from it.
+type CognitiveProfile struct {
+	Composite float64
+	Domains   []DomainProfile
+	HalfLife  time.Duration
+}
+
+// ProfileService computes cognitive profiles: each domain scored from recent sessions with exponential
+// decay, combined into a composite with configurable weights so no single game dominates. Tenants may
+// override the weights and half-life in their settings.
+type ProfileService struct {
+	scoreRepo *repository.ScoreRepository
+	tenants   *TenantService
+	halfLife  time.Duration
+	weights   map[string]float64
+}
+
+// NewProfileService creates a new ProfileService. Game types without a configured weight weigh 1.
+func NewProfileService(scoreRepo *repository.ScoreRepository, tenants *TenantService, cfg config.ProfileConfig) *ProfileService {
+	halfLife := cfg.HalfLife
+	if halfLife <= 0 {
+		halfLife = defaultProfileHalfLife
+	}
+	weights := make(map[string]float64, len(game.AllGameTypes))
+	for _, gt := range game.AllGameTypes {
+		weights[string(gt)] = 1
+		if w, ok := cfg.Weights[string(gt)]; ok && w >= 0 {
+			weights[string(gt)] = w
+		}
+	}
+	return &ProfileService{
+		scoreRepo: scoreRepo,
+		tenants:   tenants,
+		halfLife:  halfLife,
+		weights:   weights,
+	}
+}
+
+// GetProfile returns the user's cognitive profile (all domains 0 when the user has no scores).
+func (s *ProfileService) GetProfile(ctx context.Context, userID string) (*CognitiveProfile, error) {
+	score, err := s.scoreRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if score == nil {
+		score = &entity.Score{UserID: userID}
+	}
+	return s.Compute(ctx, score, time.Now().UTC()), nil
+}
+
+// Compute builds the profile from the score document as of now. Only leaderboard-eligible sessions count;
+// a session's weight halves every half_life. Unplayed domains score 0 but keep their weight, so the
+// composite rewards breadth rather than repeating one favourite game. The scoring parameters are those of
+// the tenant in ctx, or of the score's tenant when ctx has none.
+func (s *ProfileService) Compute(ctx context.Context, score *entity.Score, now time.Time) *CognitiveProfile {
+	halfLife, weights := s.params(ctx, score)
+	profile := &CognitiveProfile{HalfLife: halfLife}
+	var weighted, totalWeight float64
+	for _, gt := range game.AllGameTypes {
+		domain := DomainProfile{
+			GameType: string(gt),
+			Label:    gt.Label(),
+			Weight:   weights[string(gt)],
+		}
+		if gts := getGameTypeScore(score, string(gt)); gts != nil {
+			var sum, decaySum float64
+			for i := range gts.Sessions {
+				se := &gts.Sessions[i]
+				if !se.LeaderboardEligible() {
+					continue
+				}
+				age := now.Sub(se.Timestamp)
+				if age < 0 {
+					age = 0
+				}
+				decay := math.Pow(0.5, float64(age)/float64(halfLife))
+				sum += decay * se.SessionScore.Score
+				decaySum += decay
+				domain.Sessions++
+				if domain.LastPlayedAt == nil || se.Timestamp.After(*domain.LastPlayedAt) {
+					ts := se.Timestamp
+					domain.LastPlayedAt = &ts
+				}
+			}
+			if decaySum > 0 {
+				domain.Score = sum / decaySum
+			}
+		}
+		weighted += domain.Weight * domain.Score
+		totalWeight += domain.Weight
+		profile.Domains = append(profile.Domains, domain)
+	}
+	if totalWeight > 0 {
+		profile.Composite = weighted / totalWeight
+	}
+	return profile
+}
+
+// RefreshAll recomputes and stores overall_score for every user so the composite leaderboard
+// reflects decay for players who stopped playing.
+func (s *ProfileService) RefreshAll(ctx context.Context) error {
+	scores, err := s.scoreRepo.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	for _, score := range scores {
+		overall := s.Compute(ctx, score, now).Composite
+		if overall == score.OverallScore {
+			continue
+		}
+		if err := s.scoreRepo.SetOverallScore(ctx, score.UserID, overall); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// params returns the half-life and weights of the tenant in ctx, falling back to the score's tenant for
+// background jobs. Parameters the tenant leaves unset use the profile config.
+func (s *ProfileService) params(ctx context.Context, score *entity.Score) (time.Duration, map[string]float64) {
+	t := tenant.FromContext(ctx)
+	if t == nil && s.tenants != nil {
+		id := score.TenantID
+		if id == "" {
+			id = tenant.DefaultID
+		}
+		t = s.tenants.Get(ctx, id)
+	}
+	if t == nil {
+		return s.halfLife, s.weights
+	}
+	halfLife := s.halfLife
+	if t.Scoring.HalfLife > 0 {
+		halfLife = t.Scoring.HalfLife
+	}
+	if len(t.Scoring.Weights) == 0 {
+		return halfLife, s.weights
+	}
+	weights := make(map[string]float64, len(s.weights))
+	for gt, w := range s.weights {
+		weights[gt] = w
+		if tw, ok := t.Scoring.Weights[gt]; ok && tw >= 0 {
+			weights[gt] = tw
+		}
+	}
+	return halfLife, weights
+}
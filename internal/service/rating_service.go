@@ -0,0 +1,341 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"brainbash_backend/config"
+	"brainbash_backend/internal/game"
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/rating"
+	"brainbash_backend/internal/repository"
+	"brainbash_backend/internal/tenant"
+	"brainbash_backend/internal/validation"
+)
+
+const (
+	defaultRatingPeriod          = 7 * 24 * time.Hour
+	defaultProvisionalDeviation  = 110
+	defaultChallengeNeighbors    = 5
+	defaultChallengeRatedPlayers = 1000
+	challengeRateBatch           = 10
+)
+
+// RatingEntry is one row of a rating leaderboard.
+type RatingEntry struct {
+	Rating *entity.Rating
+	User   *entity.User
+}
+
+// ratedGame is one head-to-head outcome: Score is a's result against b (rating.Win, Draw or Loss).
+type ratedGame struct {
+	a, b  string
+	score float64
+}
+
+// RatingService keeps a Glicko-2 rating per user and game type, updated from duels, matches and
+// optionally daily-challenge placements. Each update is one rating period holding all of its games.
+type RatingService struct {
+	ratingRepo    *repository.RatingRepository
+	challengeRepo *repository.ChallengeRepository
+	attemptRepo   *repository.ChallengeAttemptRepository
+	userService   *UserService
+	tenants       *TenantService
+	calc          *rating.Calculator
+
+	period               time.Duration
+	provisionalDeviation float64
+	challengePlacements  bool
+	challengeNeighbors   int
+	challengeMaxPlayers  int64
+
+	// mu serialises read-modify-write of ratings so concurrent games of a player are not lost
+	mu sync.Mutex
+}
+
+// NewRatingService creates a new RatingService.
+func NewRatingService(ratingRepo *repository.RatingRepository, challengeRepo *repository.ChallengeRepository, attemptRepo *repository.ChallengeAttemptRepository, userService *UserService, tenants *TenantService, cfg config.RatingsConfig) *RatingService {
+	if cfg.Period <= 0 {
+		cfg.Period = defaultRatingPeriod
+	}
+	if cfg.ProvisionalDeviation <= 0 {
+		cfg.ProvisionalDeviation = defaultProvisionalDeviation
+	}
+	if cfg.ChallengeNeighbors <= 0 {
+		cfg.ChallengeNeighbors = defaultChallengeNeighbors
+	}
+	if cfg.ChallengeMaxPlayers <= 0 {
+		cfg.ChallengeMaxPlayers = defaultChallengeRatedPlayers
+	}
+	return &RatingService{
+		ratingRepo:           ratingRepo,
+		challengeRepo:        challengeRepo,
+		attemptRepo:          attemptRepo,
+		userService:          userService,
+		tenants:              tenants,
+		calc:                 rating.NewCalculator(cfg.Tau),
+		period:               cfg.Period,
+		provisionalDeviation: cfg.ProvisionalDeviation,
+		challengePlacements:  cfg.ChallengePlacements,
+		challengeNeighbors:   cfg.ChallengeNeighbors,
+		challengeMaxPlayers:  cfg.ChallengeMaxPlayers,
+	}
+}
+
+// Get returns the user's current rating on gameType; players who have not been rated get the default.
+func (s *RatingService) Get(ctx context.Context, userID, gameType string) (*entity.Rating, error) {
+	ratings, err := s.current(ctx, gameType, []string{userID}, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	return ratings[userID], nil
+}
+
+// Ratings returns the users' current ratings on gameType keyed by user id, defaults included.
+func (s *RatingService) Ratings(ctx context.Context, gameType string, userIDs []string) (map[string]*entity.Rating, error) {
+	return s.current(ctx, gameType, userIDs, time.Now().UTC())
+}
+
+// ForUser returns the user's current ratings keyed by game type. Unrated game types are missing.
+func (s *RatingService) ForUser(ctx context.Context, userID string) (map[string]*entity.Rating, error) {
+	ratings, err := s.ratingRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	out := make(map[string]*entity.Rating, len(ratings))
+	for _, r := range ratings {
+		out[r.GameType] = s.decay(r, now)
+	}
+	return out, nil
+}
+
+// Provisional reports whether the rating is still too uncertain to rank.
+func (s *RatingService) Provisional(r *entity.Rating) bool {
+	return r.Games == 0 || r.Deviation > s.provisionalDeviation
+}
+
+// History returns up to limit of the user's rating changes on gameType, newest first.
+func (s *RatingService) History(ctx context.Context, userID, gameType string, limit int64) ([]*entity.RatingChange, error) {
+	if err := game.GameType(gameType).Validate(); err != nil {
+		return nil, &validation.Error{Field: "gametype", Message: err.Error()}
+	}
+	return s.ratingRepo.FindHistory(ctx, userID, gameType, limit)
+}
+
+// Leaderboard returns the limit highest established ratings on gameType. Provisional ratings are left out.
+func (s *RatingService) Leaderboard(ctx context.Context, gameType string, limit int64) ([]RatingEntry, error) {
+	if err := game.GameType(gameType).Validate(); err != nil {
+		return nil, &validation.Error{Field: "gametype", Message: err.Error()}
+	}
+	ratings, err := s.ratingRepo.FindTop(ctx, gameType, s.provisionalDeviation, limit)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(ratings))
+	for _, r := range ratings {
+		ids = append(ids, r.UserID)
+	}
+	users, err := s.userService.FindByUserIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]RatingEntry, 0, len(ratings))
+	for _, r := range ratings {
+		if user := users[r.UserID]; user != nil {
+			out = append(out, RatingEntry{Rating: r, User: user})
+		}
+	}
+	return out, nil
+}
+
+// RateDuel rates a completed duel. Forfeits and duels with a flagged session are not rated.
+func (s *RatingService) RateDuel(ctx context.Context, duel *entity.Duel) error {
+	if duel.Forfeit || duel.Challenger == nil || duel.Opponent == nil || duel.Challenger.Flagged || duel.Opponent.Flagged {
+		return nil
+	}
+	score := rating.Draw
+	switch duel.WinnerID {
+	case duel.ChallengerID:
+		score = rating.Win
+	case duel.OpponentID:
+		score = rating.Loss
+	}
+	return s.apply(ctx, duel.GameType, entity.RatingSourceDuel, duel.ID, []ratedGame{{a: duel.ChallengerID, b: duel.OpponentID, score: score}})
+}
+
+// RateMatch rates a finished match as games between every pair of players: the better place wins.
+// Players who left lose to everyone who stayed; flagged players are not rated.
+func (s *RatingService) RateMatch(ctx context.Context, m *entity.Match) error {
+	var games []ratedGame
+	for i, a := range m.Players {
+		for _, b := range m.Players[i+1:] {
+			if a.Flagged || b.Flagged || (a.Left && b.Left) {
+				continue
+			}
+			// Players are stored by place, and those who left are placed last
+			games = append(games, ratedGame{a: a.UserID, b: b.UserID, score: rating.Win})
+		}
+	}
+	return s.apply(ctx, m.GameType, entity.RatingSourceMatch, m.ID, games)
+}
+
+// RateChallenges rates finished daily challenges as multi-player matches, each player against the
+// placements just above and below theirs. Does nothing unless challenge placements are enabled. Run periodically.
+func (s *RatingService) RateChallenges(ctx context.Context) error {
+	if !s.challengePlacements {
+		return nil
+	}
+	// Yesterday's challenge still takes attempts during the grace period after midnight
+	before := entity.ChallengeID(time.Now().Add(-challengeSubmitGrace))
+	challenges, err := s.challengeRepo.FindUnrated(ctx, before, challengeRateBatch)
+	if err != nil {
+		return err
+	}
+	for _, challenge := range challenges {
+		if err := s.rateChallenge(ctx, challenge); err != nil {
+			log.Printf("Ratings: rate challenge %s: %v", challenge.ID, err)
+		}
+	}
+	return nil
+}
+
+func (s *RatingService) rateChallenge(ctx context.Context, challenge *entity.Challenge) error {
+	// Claim the challenge first so only one instance rates it
+	claimed, err := s.challengeRepo.MarkRated(ctx, challenge.ID, time.Now().UTC())
+	if err != nil || !claimed {
+		return err
+	}
+	// Every tenant has its own leaderboard, so players are only rated against their tenant's
+	for _, t := range s.tenants.All(ctx) {
+		tctx := tenant.NewContext(ctx, t)
+		attempts, err := s.attemptRepo.FindTop(tctx, challenge.ID, s.challengeMaxPlayers)
+		if err != nil {
+			return err
+		}
+		var games []ratedGame
+		for i, a := range attempts {
+			for _, b := range attempts[i+1 : min(len(attempts), i+1+s.challengeNeighbors)] {
+				score := rating.Win
+				if a.SessionScore.Score == b.SessionScore.Score {
+					score = rating.Draw
+				}
+				games = append(games, ratedGame{a: a.UserID, b: b.UserID, score: score})
+			}
+		}
+		if err := s.apply(tctx, challenge.GameTypeFor(t.ID), entity.RatingSourceChallenge, challenge.ID, games); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apply rates games on gameType as one rating period: every player is rated against the ratings their
+// opponents had before it. Stores the new ratings and one history entry per player.
+func (s *RatingService) apply(ctx context.Context, gameType, source, refID string, games []ratedGame) error {
+	if len(games) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var userIDs []string
+	results := make(map[string][]ratedGame)
+	for _, g := range games {
+		for _, id := range []string{g.a, g.b} {
+			if _, ok := results[id]; !ok {
+				userIDs = append(userIDs, id)
+			}
+			results[id] = append(results[id], g)
+		}
+	}
+	now := time.Now().UTC()
+	before, err := s.current(ctx, gameType, userIDs, now)
+	if err != nil {
+		return err
+	}
+
+	changes := make([]*entity.RatingChange, 0, len(userIDs))
+	for _, userID := range userIDs {
+		old := before[userID]
+		period := make([]rating.Result, 0, len(results[userID]))
+		for _, g := range results[userID] {
+			if g.a == userID {
+				period = append(period, rating.Result{Opponent: toGlicko(before[g.b]), Score: g.score})
+			} else {
+				period = append(period, rating.Result{Opponent: toGlicko(before[g.a]), Score: 1 - g.score})
+			}
+		}
+		next := s.calc.Update(toGlicko(old), period)
+		r := &entity.Rating{
+			ID:         entity.RatingID(userID, gameType),
+			UserID:     userID,
+			TenantID:   old.TenantID,
+			GameType:   gameType,
+			Rating:     next.Rating,
+			Deviation:  next.Deviation,
+			Volatility: next.Volatility,
+			Games:      old.Games + len(period),
+			UpdatedAt:  now,
+		}
+		if err := s.ratingRepo.Upsert(ctx, r); err != nil {
+			return err
+		}
+		changes = append(changes, &entity.RatingChange{
+			ID:        bson.NewObjectID().Hex(),
+			UserID:    userID,
+			GameType:  gameType,
+			Source:    source,
+			RefID:     refID,
+			Games:     len(period),
+			Rating:    r.Rating,
+			Deviation: r.Deviation,
+			Change:    r.Rating - old.Rating,
+			CreatedAt: now,
+		})
+	}
+	return s.ratingRepo.InsertHistory(ctx, changes)
+}
+
+// current returns the users' ratings on gameType as of now, defaults included.
+func (s *RatingService) current(ctx context.Context, gameType string, userIDs []string, now time.Time) (map[string]*entity.Rating, error) {
+	stored, err := s.ratingRepo.FindByUserIDs(ctx, gameType, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]*entity.Rating, len(userIDs))
+	for _, r := range stored {
+		out[r.UserID] = s.decay(r, now)
+	}
+	for _, userID := range userIDs {
+		if out[userID] == nil {
+			d := rating.Default()
+			out[userID] = &entity.Rating{
+				ID:         entity.RatingID(userID, gameType),
+				UserID:     userID,
+				GameType:   gameType,
+				Rating:     d.Rating,
+				Deviation:  d.Deviation,
+				Volatility: d.Volatility,
+			}
+		}
+	}
+	return out, nil
+}
+
+// decay grows the deviation of a rating that has not been updated for a while, one step per period.
+func (s *RatingService) decay(r *entity.Rating, now time.Time) *entity.Rating {
+	periods := float64(now.Sub(r.UpdatedAt)) / float64(s.period)
+	next := s.calc.Decay(toGlicko(r), periods)
+	out := *r
+	out.Deviation = next.Deviation
+	return &out
+}
+
+func toGlicko(r *entity.Rating) rating.Rating {
+	return rating.Rating{Rating: r.Rating, Deviation: r.Deviation, Volatility: r.Volatility}
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"hash/fnv"
+	"log"
+	"sort"
+	"time"
+
+	"brainbash_backend/config"
+	"brainbash_backend/internal/game"
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/recommend"
+	"brainbash_backend/internal/repository"
+	"brainbash_backend/internal/tenant"
+)
+
+const (
+	defaultPlanSize        = 3
+	recommendRecentWindow  = 5
+	defaultRecommenderName = recommend.RecommenderWeakestFirst
+)
+
+// TrainingPlan is a user's suggested games for today.
+type TrainingPlan struct {
+	Date        string // YYYY-MM-DD in the user's timezone
+	Recommender string
+	Experiment  string // "" when the user is not in an experiment
+	Suggestions []recommend.Suggestion
+}
+
+// recommenderVariant is one arm of the recommender experiment.
+type recommenderVariant struct {
+	name   string
+	weight int
+}
+
+// RecommendationService builds daily training plans from a user's per-domain history. The recommender is
+// pluggable; with an experiment configured, each user is assigned a recommender by a stable hash.
+type RecommendationService struct {
+	scoreRepo   *repository.ScoreRepository
+	userService *UserService
+	engine      *recommend.Engine
+	planSize    int
+	fallback    string
+	experiment  string
+	variants    []recommenderVariant
+	totalWeight int
+}
+
+// NewRecommendationService creates a new RecommendationService. Unknown recommender names are logged
+// and replaced by weakest_first (or dropped from the experiment).
+func NewRecommendationService(scoreRepo *repository.ScoreRepository, userService *UserService, engine *recommend.Engine, cfg config.RecommendationsConfig) *RecommendationService {
+	s := &RecommendationService{
+		scoreRepo:   scoreRepo,
+		userService: userService,
+		engine:      engine,
+		planSize:    cfg.PlanSize,
+		fallback:    cfg.Recommender,
+		experiment:  cfg.Experiment.Name,
+	}
+	if s.planSize <= 0 {
+		s.planSize = defaultPlanSize
+	}
+	if !engine.Has(s.fallback) {
+		if s.fallback != "" {
+			log.Printf("Recommendations: unknown recommender %q, using %s", s.fallback, defaultRecommenderName)
+		}
+		s.fallback = defaultRecommenderName
+	}
+	for name, weight := range cfg.Experiment.Variants {
+		if !engine.Has(name) || weight <= 0 {
+			log.Printf("Recommendations: skipping experiment variant %q (weight %d)", name, weight)
+			continue
+		}
+		s.variants = append(s.variants, recommenderVariant{name: name, weight: weight})
+		s.totalWeight += weight
+	}
+	// Map order is random; sort so the same user always lands in the same variant
+	sort.Slice(s.variants, func(i, j int) bool { return s.variants[i].name < s.variants[j].name })
+	return s
+}
+
+// GetPlan returns today's plan for the user. Only leaderboard-eligible sessions are considered.
+func (s *RecommendationService) GetPlan(ctx context.Context, userID string) (*TrainingPlan, error) {
+	score, err := s.scoreRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if score == nil {
+		score = &entity.Score{UserID: userID}
+	}
+
+	now := time.Now().UTC()
+	in := recommend.Input{Now: now, Domains: domainStats(tenant.FromContext(ctx), score)}
+	name, experiment := s.recommenderFor(userID)
+	suggestions, err := s.engine.Recommend(name, in, s.planSize)
+	if err != nil {
+		return nil, err
+	}
+	return &TrainingPlan{
+		Date:        now.In(s.userService.Location(ctx, userID)).Format(entity.StreakDayLayout),
+		Recommender: name,
+		Experiment:  experiment,
+		Suggestions: suggestions,
+	}, nil
+}
+
+// recommenderFor returns the user's recommender and experiment name ("" outside an experiment).
+func (s *RecommendationService) recommenderFor(userID string) (string, string) {
+	if s.experiment == "" || s.totalWeight == 0 {
+		return s.fallback, ""
+	}
+	h := fnv.New32a()
+	h.Write([]byte(s.experiment + ":" + userID))
+	bucket := int(h.Sum32() % uint32(s.totalWeight))
+	for _, v := range s.variants {
+		if bucket < v.weight {
+			return v.name, s.experiment
+		}
+		bucket -= v.weight
+	}
+	return s.fallback, ""
+}
+
+// domainStats summarises the score document per game type in the catalog of t (every game type when t is
+// nil) for the recommenders, so only games the tenant offers are suggested.
+func domainStats(t *tenant.Tenant, score *entity.Score) []recommend.DomainStats {
+	catalog := game.AllGameTypes
+	if t != nil {
+		catalog = t.Catalog()
+	}
+	out := make([]recommend.DomainStats, 0, len(catalog))
+	for _, gt := range catalog {
+		d := recommend.DomainStats{GameType: string(gt), Label: gt.Label()}
+		if t != nil {
+			d.Label = t.Label(gt)
+		}
+		if gts := getGameTypeScore(score, string(gt)); gts != nil {
+			d.AvgScore, d.HighScore = gts.AvgScore, gts.HighScore
+			for i := range gts.Sessions {
+				se := &gts.Sessions[i]
+				if !se.LeaderboardEligible() {
+					continue
+				}
+				d.Sessions++
+				if d.LastPlayedAt == nil || se.Timestamp.After(*d.LastPlayedAt) {
+					ts := se.Timestamp
+					d.LastPlayedAt = &ts
+				}
+				d.RecentScores = append(d.RecentScores, se.SessionScore.Score)
+				d.RecentAcc = append(d.RecentAcc, se.SessionScore.Accuracy)
+			}
+			if n := len(d.RecentScores); n > recommendRecentWindow {
+				d.RecentScores = d.RecentScores[n-recommendRecentWindow:]
+				d.RecentAcc = d.RecentAcc[n-recommendRecentWindow:]
+			}
+		}
+		out = append(out, d)
+	}
+	return out
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/repository"
+)
+
+var (
+	// ErrFlagNotFound is returned when no flag has the id.
+	ErrFlagNotFound = errors.New("flagged session not found")
+	// ErrFlagReviewed is returned when the flag was already approved or rejected.
+	ErrFlagReviewed = errors.New("flagged session already reviewed")
+)
+
+// ReviewService lets admins approve or reject flagged sessions.
+type ReviewService struct {
+	flagRepo         *repository.FlagRepository
+	scoreRepo        *repository.ScoreRepository
+	sessionRepo      *repository.SessionRepository
+	attemptRepo      *repository.ChallengeAttemptRepository
+	dashboardService *DashboardService
+	profileService   *ProfileService
+	tenantService    *TenantService
+}
+
+// NewReviewService creates a new ReviewService.
+func NewReviewService(flagRepo *repository.FlagRepository, scoreRepo *repository.ScoreRepository, sessionRepo *repository.SessionRepository, attemptRepo *repository.ChallengeAttemptRepository, dashboardService *DashboardService, profileService *ProfileService, tenantService *TenantService) *ReviewService {
+	return &ReviewService{
+		flagRepo:         flagRepo,
+		scoreRepo:        scoreRepo,
+		sessionRepo:      sessionRepo,
+		attemptRepo:      attemptRepo,
+		dashboardService: dashboardService,
+		profileService:   profileService,
+		tenantService:    tenantService,
+	}
+}
+
+// ListFlags returns up to limit flags with the given status (oldest first).
+func (s *ReviewService) ListFlags(ctx context.Context, status string, limit int64) ([]*entity.SessionFlag, error) {
+	return s.flagRepo.FindByStatus(ctx, status, limit)
+}
+
+// Approve marks the flagged session as reviewed and restores its leaderboard eligibility.
+func (s *ReviewService) Approve(ctx context.Context, flagID, reviewerID string) (*entity.SessionFlag, error) {
+	flag, err := s.pendingFlag(ctx, flagID)
+	if err != nil {
+		return nil, err
+	}
+	// Run in the flagged user's tenant rather than the reviewing admin's, so the score, scoring parameters
+	// and leaderboard updated are that tenant's
+	ctx = s.tenantService.NewContext(ctx, flag.TenantID)
+
+	score, err := s.scoreRepo.FindByUserID(ctx, flag.UserID)
+	if err != nil {
+		return nil, err
+	}
+	// The flag is stored before the score document, so a failed submission leaves a flag without a session
+	found := false
+	if score != nil {
+		if gt := getGameTypeScore(score, flag.GameType); gt != nil {
+			for i := range gt.Sessions {
+				if gt.Sessions[i].SessionID == flag.SessionID {
+					gt.Sessions[i].ReviewStatus = entity.ReviewApproved
+					found = true
+				}
+			}
+			// Approved sessions now count towards high_score, avg_score and the composite
+			recomputeAggregates(ctx, score, s.profileService)
+			if err := s.scoreRepo.Upsert(ctx, score); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := s.sessionRepo.SetReviewStatus(ctx, flag.UserID, flag.SessionID, entity.ReviewApproved); err != nil {
+		return nil, err
+	}
+	// A daily challenge attempt joins its leaderboard; rejected attempts stay flagged and still use up the day
+	if err := s.attemptRepo.SetFlaggedBySession(ctx, flag.UserID, flag.SessionID, false); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if err := s.flagRepo.SetStatus(ctx, flag.ID, entity.ReviewApproved, reviewerID, now); err != nil {
+		return nil, err
+	}
+	flag.Status, flag.ReviewedBy, flag.ReviewedAt = entity.ReviewApproved, reviewerID, &now
+
+	if found {
+		_ = s.dashboardService.MaybeUpdateTop10(ctx, flag.GameType, flag.UserID, flag.SessionID, flag.SessionScore, flag.Timestamp)
+	}
+	return flag, nil
+}
+
+// Reject removes the flagged session from the user's scores and recomputes avg_score, high_score and overall_score.
+func (s *ReviewService) Reject(ctx context.Context, flagID, reviewerID string) (*entity.SessionFlag, error) {
+	flag, err := s.pendingFlag(ctx, flagID)
+	if err != nil {
+		return nil, err
+	}
+	// Run in the flagged user's tenant rather than the reviewing admin's, so the score, scoring parameters
+	// and leaderboard updated are that tenant's
+	ctx = s.tenantService.NewContext(ctx, flag.TenantID)
+
+	score, err := s.scoreRepo.FindByUserID(ctx, flag.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if score != nil {
+		if gt := getGameTypeScore(score, flag.GameType); gt != nil {
+			kept := make([]entity.Session, 0, len(gt.Sessions))
+			for _, se := range gt.Sessions {
+				if se.SessionID != flag.SessionID {
+					kept = append(kept, se)
+				}
+			}
+			gt.Sessions = kept
+			recomputeAggregates(ctx, score, s.profileService)
+			if err := s.scoreRepo.Upsert(ctx, score); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := s.sessionRepo.DeleteBySessionID(ctx, flag.UserID, flag.SessionID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if err := s.flagRepo.SetStatus(ctx, flag.ID, entity.ReviewRejected, reviewerID, now); err != nil {
+		return nil, err
+	}
+	flag.Status, flag.ReviewedBy, flag.ReviewedAt = entity.ReviewRejected, reviewerID, &now
+	return flag, nil
+}
+
+func (s *ReviewService) pendingFlag(ctx context.Context, flagID string) (*entity.SessionFlag, error) {
+	flag, err := s.flagRepo.FindByID(ctx, flagID)
+	if err != nil {
+		return nil, err
+	}
+	if flag == nil {
+		return nil, ErrFlagNotFound
+	}
+	if flag.Status != entity.ReviewPending {
+		return nil, ErrFlagReviewed
+	}
+	return flag, nil
+}
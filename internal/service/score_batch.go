@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"brainbash_backend/internal/game"
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/model/request"
+	"brainbash_backend/internal/validation"
+)
+
+const (
+	defaultBatchMaxSessions   = 50
+	defaultBatchMaxAge        = 30 * 24 * time.Hour
+	defaultBatchMaxFutureSkew = 5 * time.Minute
+)
+
+var (
+	// ErrEmptyBatch is returned when a batch has no sessions.
+	ErrEmptyBatch = errors.New("sessions must not be empty")
+	// ErrBatchTooLarge is returned when a batch exceeds batch.max_sessions.
+	ErrBatchTooLarge = errors.New("too many sessions in batch")
+)
+
+// BatchItemResult is the outcome of one session of a batch. Exactly one of Result and Err is set.
+type BatchItemResult struct {
+	SessionID string
+	Result    *entity.GameResult
+	Replayed  bool
+	Err       error
+}
+
+// batchItem is a batch session that passed validation and scoring and holds an idempotency key.
+type batchItem struct {
+	index   int
+	keyID   string
+	pending PendingSession
+}
+
+// SubmitBatch stores sessions played offline. Each session is validated, scored and made idempotent
+// by its session_id exactly like SubmitGameResult, but all new sessions are written to the score
+// document in one update so aggregates are recomputed once. Results are in the order of items;
+// per-item failures are reported in BatchItemResult.Err.
+func (s *ScoreService) SubmitBatch(ctx context.Context, userID string, items []request.BatchGameResult) ([]BatchItemResult, error) {
+	if len(items) == 0 {
+		return nil, ErrEmptyBatch
+	}
+	if len(items) > s.batch.MaxSessions {
+		return nil, fmt.Errorf("%w: %d (max %d)", ErrBatchTooLarge, len(items), s.batch.MaxSessions)
+	}
+
+	now := time.Now().UTC()
+	results := make([]BatchItemResult, len(items))
+	seen := make(map[string]bool, len(items))
+	var accepted []batchItem
+	for i, item := range items {
+		results[i].SessionID = item.SessionID
+		if seen[item.SessionID] {
+			results[i].Err = &validation.Error{Field: "session_id", Message: "duplicate session_id in batch"}
+			continue
+		}
+		seen[item.SessionID] = true
+
+		pending, err := s.prepareBatchItem(ctx, item, now)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		// Hash without played_at so a session first tried online and later synced offline is one submission
+		hash, err := requestHash(request.GameResultRequest{
+			GameType:          item.GameType,
+			QuestionResponses: item.QuestionResponses,
+			SessionID:         item.SessionID,
+		})
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		keyID, replay, err := s.reserveKey(ctx, userID, "session_id:"+item.SessionID, hash)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		if replay != nil {
+			results[i].Result = replay
+			results[i].Replayed = true
+			continue
+		}
+		accepted = append(accepted, batchItem{index: i, keyID: keyID, pending: *pending})
+	}
+	if len(accepted) == 0 {
+		return results, nil
+	}
+
+	pending := make([]PendingSession, len(accepted))
+	for i, a := range accepted {
+		pending[i] = a.pending
+	}
+	sessions, err := s.appendSessions(ctx, userID, pending)
+	if err != nil {
+		for _, a := range accepted {
+			s.releaseKey(ctx, a.keyID)
+		}
+		return nil, err
+	}
+
+	ageBand := s.normsService.AgeBandFor(ctx, userID)
+	for i, a := range accepted {
+		session := sessions[i]
+		gameType := a.pending.GameType
+		if session.LeaderboardEligible() {
+			_ = s.dashboardService.MaybeUpdateTop10(ctx, gameType, userID, session.SessionID, session.SessionScore, session.Timestamp)
+		}
+		result := &entity.GameResult{
+			SessionID:    session.SessionID,
+			GameType:     gameType,
+			SessionScore: session.SessionScore,
+			Percentile:   s.normsService.Percentile(ctx, gameType, ageBand, session.SessionScore.Score),
+			Flagged:      !session.LeaderboardEligible(),
+			XPGained:     session.XP.Gained,
+			Level:        session.XP.Level,
+			LeveledUp:    session.XP.LeveledUp,
+		}
+		s.completeKey(ctx, a.keyID, result)
+		results[a.index].Result = result
+	}
+	return results, nil
+}
+
+// prepareBatchItem checks the session_id and played_at of one batch session, then validates and scores it.
+// played_at must lie within [now-max_age, now+max_future_skew]; timestamps ahead of now are clamped to now.
+func (s *ScoreService) prepareBatchItem(ctx context.Context, item request.BatchGameResult, now time.Time) (*PendingSession, error) {
+	if !clientSessionIDPattern.MatchString(item.SessionID) {
+		return nil, &validation.Error{Field: "session_id", Message: "required: 8-64 characters of letters, digits, '-' or '_'"}
+	}
+	if item.PlayedAt.IsZero() {
+		return nil, &validation.Error{Field: "played_at", Message: "required (RFC 3339 timestamp)"}
+	}
+	playedAt := item.PlayedAt.UTC()
+	if playedAt.Before(now.Add(-s.batch.MaxAge)) {
+		return nil, &validation.Error{Field: "played_at", Message: fmt.Sprintf("older than %s", s.batch.MaxAge)}
+	}
+	if playedAt.After(now.Add(s.batch.MaxFutureSkew)) {
+		return nil, &validation.Error{Field: "played_at", Message: "in the future"}
+	}
+	if playedAt.After(now) {
+		playedAt = now
+	}
+
+	gt := game.GameType(item.GameType)
+	if err := ValidateGameType(ctx, item.GameType); err != nil {
+		return nil, err
+	}
+	strategy := gt.StrategyFor()
+	verdict, err := s.validator.Validate(item.GameType, strategy, item.QuestionResponses)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.scorer.Calculate(strategy, item.QuestionResponses)
+	if err != nil {
+		return nil, err
+	}
+	return &PendingSession{
+		SessionID:         item.SessionID,
+		GameType:          item.GameType,
+		QuestionResponses: item.QuestionResponses,
+		Result:            result,
+		Flags:             verdict.Flags,
+		PlayedAt:          playedAt,
+	}, nil
+}
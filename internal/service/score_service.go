@@ -2,51 +2,246 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"regexp"
+	"sort"
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 
+	"brainbash_backend/config"
+	"brainbash_backend/internal/event"
 	"brainbash_backend/internal/game"
 	"brainbash_backend/internal/model/entity"
 	"brainbash_backend/internal/model/request"
 	"brainbash_backend/internal/repository"
 	"brainbash_backend/internal/scoring"
+	"brainbash_backend/internal/tenant"
+	"brainbash_backend/internal/validation"
 )
 
 // ScoreService appends sessions and maintains per-game-type and overall scores.
 type ScoreService struct {
 	scoreRepo        *repository.ScoreRepository
+	sessionRepo      *repository.SessionRepository
+	flagRepo         *repository.FlagRepository
+	idempotencyRepo  *repository.IdempotencyRepository
 	scorer           *scoring.Scorer
+	validator        *validation.SessionValidator
+	outlierDetector  *validation.OutlierDetector
 	dashboardService *DashboardService
+	normsService     *NormsService
+	profileService   *ProfileService
+	streakService    *StreakService
+	xpService        *XPService
+	lockTimeout      time.Duration
+	batch            config.BatchConfig
 }
 
-// NewScoreService creates a new ScoreService.
-func NewScoreService(scoreRepo *repository.ScoreRepository, scorer *scoring.Scorer, dashboardService *DashboardService) *ScoreService {
-	return &ScoreService{scoreRepo: scoreRepo, scorer: scorer, dashboardService: dashboardService}
+var (
+	// ErrSubmissionInProgress is returned when a submission with the same idempotency key is still being processed.
+	ErrSubmissionInProgress = errors.New("a submission with this idempotency key is in progress")
+	// ErrIdempotencyKeyReused is returned when an idempotency key is replayed with a different request body.
+	ErrIdempotencyKeyReused = errors.New("idempotency key was already used with a different request")
+)
+
+var clientSessionIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{8,64}$`)
+
+// PendingSession is a scored session about to be stored by AppendSession.
+type PendingSession struct {
+	SessionID         string // generated when empty
+	GameType          string
+	QuestionResponses []request.QuestionResponse
+	Result            *scoring.ScoreResult
+	Flags             []string  // validation flags; outlier flags are added by AppendSession
+	PlayedAt          time.Time // when the session was played; zero means now
+	Tags              entity.SessionTags
+}
+
+// NewScoreService creates a new ScoreService. lockTimeout is how long an unfinished submission
+// blocks retries with the same idempotency key.
+func NewScoreService(scoreRepo *repository.ScoreRepository, sessionRepo *repository.SessionRepository, flagRepo *repository.FlagRepository, idempotencyRepo *repository.IdempotencyRepository, scorer *scoring.Scorer, validator *validation.SessionValidator, outlierDetector *validation.OutlierDetector, dashboardService *DashboardService, normsService *NormsService, profileService *ProfileService, streakService *StreakService, xpService *XPService, lockTimeout time.Duration, batch config.BatchConfig) *ScoreService {
+	if batch.MaxSessions <= 0 {
+		batch.MaxSessions = defaultBatchMaxSessions
+	}
+	if batch.MaxAge <= 0 {
+		batch.MaxAge = defaultBatchMaxAge
+	}
+	if batch.MaxFutureSkew <= 0 {
+		batch.MaxFutureSkew = defaultBatchMaxFutureSkew
+	}
+	return &ScoreService{
+		scoreRepo:        scoreRepo,
+		sessionRepo:      sessionRepo,
+		flagRepo:         flagRepo,
+		idempotencyRepo:  idempotencyRepo,
+		scorer:           scorer,
+		validator:        validator,
+		outlierDetector:  outlierDetector,
+		dashboardService: dashboardService,
+		normsService:     normsService,
+		profileService:   profileService,
+		streakService:    streakService,
+		xpService:        xpService,
+		lockTimeout:      lockTimeout,
+		batch:            batch,
+	}
+}
+
+// SubmitGameResult validates gametype and responses, calculates score, persists the session, and returns the result.
+// Invalid responses return a *validation.Error; suspicious sessions are stored flagged and kept off the dashboard.
+//
+// idempotencyKey (the Idempotency-Key header) or else req.SessionID makes retries safe: the session is stored
+// once and a replay returns the original result with replayed=true.
+func (s *ScoreService) SubmitGameResult(ctx context.Context, userID, idempotencyKey string, req request.GameResultRequest) (result *entity.GameResult, replayed bool, err error) {
+	return s.SubmitTaggedResult(ctx, userID, idempotencyKey, req, entity.SessionTags{})
 }
 
-// SubmitGameResult validates gametype, calculates score, persists the session, and returns the score result.
-func (s *ScoreService) SubmitGameResult(ctx context.Context, userID string, req request.GameResultRequest) (*scoring.ScoreResult, error) {
+// SubmitTaggedResult is SubmitGameResult for a session played in a game mode (e.g. the daily challenge);
+// tags are stored with the session.
+func (s *ScoreService) SubmitTaggedResult(ctx context.Context, userID, idempotencyKey string, req request.GameResultRequest, tags entity.SessionTags) (result *entity.GameResult, replayed bool, err error) {
+	if req.SessionID != "" && !clientSessionIDPattern.MatchString(req.SessionID) {
+		return nil, false, &validation.Error{Field: "session_id", Message: "must be 8-64 characters of letters, digits, '-' or '_'"}
+	}
+	key := idempotencyKey
+	if key == "" && req.SessionID != "" {
+		key = "session_id:" + req.SessionID
+	}
+	if key == "" {
+		result, err = s.submit(ctx, userID, req, tags)
+		return result, false, err
+	}
+	return s.submitOnce(ctx, userID, key, req, tags)
+}
+
+// submitOnce runs submit at most once per (user, key), replaying the stored result for retries.
+func (s *ScoreService) submitOnce(ctx context.Context, userID, key string, req request.GameResultRequest, tags entity.SessionTags) (*entity.GameResult, bool, error) {
+	hash, err := requestHash(req)
+	if err != nil {
+		return nil, false, err
+	}
+	id, replay, err := s.reserveKey(ctx, userID, key, hash)
+	if err != nil {
+		return nil, false, err
+	}
+	if replay != nil {
+		return replay, true, nil
+	}
+
+	result, err := s.submit(ctx, userID, req, tags)
+	if err != nil {
+		s.releaseKey(ctx, id)
+		return nil, false, err
+	}
+	s.completeKey(ctx, id, result)
+	return result, false, nil
+}
+
+// reserveKey claims (user, key) for a submission with the given request hash and returns the record ID.
+// When the key already completed for the same request, the stored result is returned instead.
+func (s *ScoreService) reserveKey(ctx context.Context, userID, key, hash string) (string, *entity.GameResult, error) {
+	record := &entity.IdempotencyRecord{
+		ID:          entity.IdempotencyID(userID, key),
+		UserID:      userID,
+		Key:         key,
+		RequestHash: hash,
+		Status:      entity.IdempotencyPending,
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	reserved, existing, err := s.idempotencyRepo.Reserve(ctx, record)
+	if err != nil {
+		return "", nil, err
+	}
+	if !reserved && existing != nil && existing.Status == entity.IdempotencyPending {
+		// A crashed submission must not block retries forever
+		if err := s.idempotencyRepo.ReleaseIfStale(ctx, record.ID, time.Now().Add(-s.lockTimeout)); err != nil {
+			return "", nil, err
+		}
+		reserved, existing, err = s.idempotencyRepo.Reserve(ctx, record)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	if !reserved {
+		switch {
+		case existing == nil:
+			return "", nil, ErrSubmissionInProgress
+		case existing.RequestHash != hash:
+			return "", nil, ErrIdempotencyKeyReused
+		case existing.Status == entity.IdempotencyCompleted && existing.Result != nil:
+			return "", existing.Result, nil
+		default:
+			return "", nil, ErrSubmissionInProgress
+		}
+	}
+	return record.ID, nil, nil
+}
+
+// releaseKey frees a reserved key after a failed submission so the client can retry.
+func (s *ScoreService) releaseKey(ctx context.Context, id string) {
+	if err := s.idempotencyRepo.Release(ctx, id); err != nil {
+		log.Printf("SubmitGameResult: release idempotency key: %v", err)
+	}
+}
+
+// completeKey stores the result of a reserved key for replay.
+func (s *ScoreService) completeKey(ctx context.Context, id string, result *entity.GameResult) {
+	if err := s.idempotencyRepo.Complete(ctx, id, result); err != nil {
+		log.Printf("SubmitGameResult: complete idempotency key: %v", err)
+	}
+}
+
+// submit validates, scores and stores one game result.
+func (s *ScoreService) submit(ctx context.Context, userID string, req request.GameResultRequest, tags entity.SessionTags) (*entity.GameResult, error) {
 	gt := game.GameType(req.GameType)
-	if err := gt.Validate(); err != nil {
+	if err := ValidateGameType(ctx, req.GameType); err != nil {
 		return nil, err
 	}
 
 	strategy := gt.StrategyFor()
+	verdict, err := s.validator.Validate(req.GameType, strategy, req.QuestionResponses)
+	if err != nil {
+		return nil, err
+	}
+
 	result, err := s.scorer.Calculate(strategy, req.QuestionResponses)
 	if err != nil {
 		return nil, err
 	}
 
-	session, err := s.AppendSession(ctx, userID, req.GameType, req.QuestionResponses, result)
+	session, err := s.AppendSession(ctx, userID, PendingSession{
+		SessionID:         req.SessionID,
+		GameType:          req.GameType,
+		QuestionResponses: req.QuestionResponses,
+		Result:            result,
+		Flags:             verdict.Flags,
+		Tags:              tags,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// Update dashboard (top 10) for this game type if this score qualifies
-	_ = s.dashboardService.MaybeUpdateTop10(ctx, req.GameType, userID, session.SessionID, session.SessionScore, session.Timestamp)
+	// Update dashboard (top 10) for this game type if this score qualifies; flagged sessions wait for review
+	if session.LeaderboardEligible() {
+		_ = s.dashboardService.MaybeUpdateTop10(ctx, req.GameType, userID, session.SessionID, session.SessionScore, session.Timestamp)
+	}
 
-	return result, nil
+	return &entity.GameResult{
+		SessionID:    session.SessionID,
+		GameType:     req.GameType,
+		SessionScore: session.SessionScore,
+		Percentile:   s.normsService.Percentile(ctx, req.GameType, s.normsService.AgeBandFor(ctx, userID), result.Score),
+		Flagged:      !session.LeaderboardEligible(),
+		XPGained:     session.XP.Gained,
+		Level:        session.XP.Level,
+		LeveledUp:    session.XP.LeveledUp,
+	}, nil
 }
 
 // GetUserStats returns the user's score document from the scores collection, or nil if not found.
@@ -54,71 +249,229 @@ func (s *ScoreService) GetUserStats(ctx context.Context, userID string) (*entity
 	return s.scoreRepo.FindByUserID(ctx, userID)
 }
 
+// StoredSession is a session stored by AppendSession and the XP it earned.
+type StoredSession struct {
+	entity.Session
+	XP XPAward
+}
+
 // AppendSession adds a session for the user and game type, then recomputes avg_score, high_score, and overall_score.
-// Returns the created session for use by dashboard updates.
-func (s *ScoreService) AppendSession(ctx context.Context, userID, gameType string, questionResponses interface{}, result *scoring.ScoreResult) (*entity.Session, error) {
+// Validation flags and outliers against the user's own history store the session as pending review and
+// add it to the admin review queue. Returns the created session for use by dashboard updates.
+func (s *ScoreService) AppendSession(ctx context.Context, userID string, p PendingSession) (*StoredSession, error) {
+	sessions, err := s.appendSessions(ctx, userID, []PendingSession{p})
+	if err != nil {
+		return nil, err
+	}
+	return &sessions[0], nil
+}
+
+// appendSessions adds several sessions with one read and one write of the score document, recomputing
+// aggregates once. Sessions are kept in timestamp order so offline sessions land where they were played.
+// Leaderboard-eligible sessions earn XP, with a bonus for beating the game type's high_score.
+// The returned sessions are in the order of pending.
+func (s *ScoreService) appendSessions(ctx context.Context, userID string, pending []PendingSession) ([]StoredSession, error) {
 	score, err := s.scoreRepo.FindByUserID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 	if score == nil {
-		score = &entity.Score{UserID: userID, OverallScore: result.Score}
-	}
-
-	session := entity.Session{
-		SessionID:        bson.NewObjectID().Hex(),
-		QuestionResponses: questionResponses,
-		SessionScore: entity.SessionScoreDetail{
-			Score:     result.Score,
-			Questions: result.Questions,
-			Correct:   result.Correct,
-			Accuracy:  result.Accuracy,
-			AvgTime:   result.AvgTime,
-		},
-		Timestamp: time.Now().UTC(),
-	}
-
-	gt := getGameTypeScore(score, gameType)
-	if gt == nil {
-		gt = &entity.GameTypeScore{Sessions: []entity.Session{}}
-		setGameTypeScore(score, gameType, gt)
-	}
-	gt.Sessions = append(gt.Sessions, session)
-
-	// Recompute avg and high for this game type (using session_score.score)
-	var sum float64
-	high := 0.0
-	for _, se := range gt.Sessions {
-		sum += se.SessionScore.Score
-		if se.SessionScore.Score > high {
-			high = se.SessionScore.Score
-		}
-	}
-	gt.AvgScore = sum / float64(len(gt.Sessions))
-	gt.HighScore = high
-
-	// Recompute overall_score (average of all session scores across all game types)
-	var totalSum float64
-	var totalCount int
-	for _, g := range allGameTypeScores(score) {
-		if g == nil {
+		score = &entity.Score{UserID: userID}
+	}
+
+	now := time.Now().UTC()
+	created := make([]entity.Session, len(pending))
+	personalBest := make([]bool, len(pending))
+	best := make(map[string]float64) // high_score per game type as sessions are added
+	for i, p := range pending {
+		sessionID := p.SessionID
+		if sessionID == "" {
+			sessionID = bson.NewObjectID().Hex()
+		}
+		playedAt := p.PlayedAt.UTC()
+		if p.PlayedAt.IsZero() {
+			playedAt = now
+		}
+		session := entity.Session{
+			SessionID:         sessionID,
+			QuestionResponses: p.QuestionResponses,
+			SessionScore: entity.SessionScoreDetail{
+				Score:     p.Result.Score,
+				Questions: p.Result.Questions,
+				Correct:   p.Result.Correct,
+				Accuracy:  p.Result.Accuracy,
+				AvgTime:   p.Result.AvgTime,
+			},
+			Timestamp:   playedAt,
+			SessionTags: p.Tags,
+		}
+
+		gt := getGameTypeScore(score, p.GameType)
+		if gt == nil {
+			gt = &entity.GameTypeScore{Sessions: []entity.Session{}}
+			setGameTypeScore(score, p.GameType, gt)
+		}
+
+		flags := append(append([]string(nil), p.Flags...), s.outlierDetector.Check(sessionsBefore(gt.Sessions, playedAt), session.SessionScore)...)
+		if len(flags) > 0 {
+			session.Flags = flags
+			session.ReviewStatus = entity.ReviewPending
+		}
+		if _, ok := best[p.GameType]; !ok {
+			best[p.GameType] = gt.HighScore
+		}
+		if session.LeaderboardEligible() {
+			// A first session has nothing to beat
+			personalBest[i] = len(gt.Sessions) > 0 && session.SessionScore.Score > best[p.GameType]
+			best[p.GameType] = math.Max(best[p.GameType], session.SessionScore.Score)
+		}
+		gt.Sessions = insertByTimestamp(gt.Sessions, session)
+		created[i] = session
+	}
+	recomputeAggregates(ctx, score, s.profileService)
+
+	// Flags go first: once the score document is stored the sessions count, and a failure after that would
+	// release the idempotency key and let a retry append them again
+	for i, session := range created {
+		if len(session.Flags) == 0 {
 			continue
 		}
-		for _, se := range g.Sessions {
-			totalSum += se.SessionScore.Score
-			totalCount++
+		if err := s.flagRepo.Upsert(ctx, &entity.SessionFlag{
+			SessionID:    session.SessionID,
+			UserID:       userID,
+			GameType:     pending[i].GameType,
+			Flags:        session.Flags,
+			Status:       entity.ReviewPending,
+			SessionScore: session.SessionScore,
+			Timestamp:    session.Timestamp,
+		}); err != nil {
+			return nil, err
 		}
 	}
-	if totalCount > 0 {
-		score.OverallScore = totalSum / float64(totalCount)
+	if err := s.scoreRepo.Upsert(ctx, score); err != nil {
+		return nil, err
+	}
+
+	playedAt := make([]time.Time, len(created))
+	for i, session := range created {
+		playedAt[i] = session.Timestamp
+	}
+	streakDays := 0
+	if streak, err := s.streakService.RecordPlays(ctx, userID, playedAt...); err != nil {
+		log.Printf("AppendSession: update streak: %v", err)
 	} else {
-		score.OverallScore = result.Score
+		streakDays = streak.Current
 	}
 
-	if err := s.scoreRepo.Upsert(ctx, score); err != nil {
-		return nil, err
+	for i, session := range created {
+		gameType := pending[i].GameType
+		// The score document is the source of truth; a missing record can be restored by the backfill
+		if err := s.sessionRepo.Insert(ctx, newSessionRecord(score, gameType, session)); err != nil {
+			log.Printf("AppendSession: store session record: %v", err)
+		}
+	}
+
+	gains := make([]int64, len(created))
+	for i := range created {
+		if created[i].LeaderboardEligible() {
+			gains[i] = s.xpService.SessionXP(created[i].SessionScore, personalBest[i], streakDays)
+		}
+	}
+	stored := make([]StoredSession, len(created))
+	awards, err := s.xpService.Award(ctx, userID, gains)
+	if err != nil {
+		log.Printf("AppendSession: award xp: %v", err)
+	}
+	for i := range created {
+		stored[i].Session = created[i]
+		if i < len(awards) {
+			stored[i].XP = awards[i]
+		}
+	}
+
+	for i := range created {
+		if !created[i].LeaderboardEligible() {
+			continue
+		}
+		event.Publish(ctx, event.Event{
+			Type:         event.SessionStored,
+			UserID:       userID,
+			GameType:     pending[i].GameType,
+			SessionID:    created[i].SessionID,
+			SessionScore: created[i].SessionScore,
+			Timestamp:    created[i].Timestamp,
+		})
+	}
+	return stored, nil
+}
+
+// sessionsBefore returns the prefix of sessions (sorted by timestamp) played before t.
+func sessionsBefore(sessions []entity.Session, t time.Time) []entity.Session {
+	i := sort.Search(len(sessions), func(i int) bool { return sessions[i].Timestamp.After(t) })
+	return sessions[:i]
+}
+
+// insertByTimestamp inserts session into sessions (sorted by timestamp) after any equal timestamps.
+func insertByTimestamp(sessions []entity.Session, session entity.Session) []entity.Session {
+	i := sort.Search(len(sessions), func(i int) bool { return sessions[i].Timestamp.After(session.Timestamp) })
+	sessions = append(sessions, entity.Session{})
+	copy(sessions[i+1:], sessions[i:])
+	sessions[i] = session
+	return sessions
+}
+
+// requestHash fingerprints a submission so a reused idempotency key with a different body is detected.
+func requestHash(req request.GameResultRequest) (string, error) {
+	raw, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ValidateGameType returns a *validation.Error unless gameType is valid and in the catalog of the tenant in ctx.
+func ValidateGameType(ctx context.Context, gameType string) error {
+	if err := game.GameType(gameType).Validate(); err != nil {
+		return &validation.Error{Field: "gametype", Message: err.Error()}
+	}
+	if t := tenant.FromContext(ctx); t != nil && !t.GameTypeEnabled(gameType) {
+		return &validation.Error{Field: "gametype", Message: "game type is not available"}
+	}
+	return nil
+}
+
+// recomputeAggregates recomputes avg_score and high_score for each game type from the leaderboard-eligible
+// sessions in score, and overall_score as the composite of the user's cognitive profile. Sessions pending
+// review count once they are approved.
+func recomputeAggregates(ctx context.Context, score *entity.Score, profileService *ProfileService) {
+	for _, gt := range allGameTypeScores(score) {
+		if gt == nil {
+			continue
+		}
+		var sum float64
+		high := 0.0
+		n := 0
+		for i := range gt.Sessions {
+			se := &gt.Sessions[i]
+			if !se.LeaderboardEligible() {
+				continue
+			}
+			n++
+			sum += se.SessionScore.Score
+			if se.SessionScore.Score > high {
+				high = se.SessionScore.Score
+			}
+		}
+		if n > 0 {
+			gt.AvgScore = sum / float64(n)
+			gt.HighScore = high
+		} else {
+			gt.AvgScore = 0
+			gt.HighScore = 0
+		}
 	}
-	return &session, nil
+	score.OverallScore = profileService.Compute(ctx, score, time.Now().UTC()).Composite
 }
 
 func getGameTypeScore(score *entity.Score, gameType string) *entity.GameTypeScore {
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"brainbash_backend/internal/game"
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/repository"
+)
+
+var (
+	// ErrSessionNotFound is returned when the session does not exist or belongs to another user.
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrInvalidCursor is returned for a malformed pagination cursor.
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// SessionPage is one page of a user's sessions, newest first. NextCursor is "" on the last page.
+type SessionPage struct {
+	Sessions   []*entity.SessionRecord
+	NextCursor string
+}
+
+// SessionService serves a user's past sessions from the sessions collection.
+type SessionService struct {
+	sessionRepo *repository.SessionRepository
+	scoreRepo   *repository.ScoreRepository
+}
+
+// NewSessionService creates a new SessionService.
+func NewSessionService(sessionRepo *repository.SessionRepository, scoreRepo *repository.ScoreRepository) *SessionService {
+	return &SessionService{
+		sessionRepo: sessionRepo,
+		scoreRepo:   scoreRepo,
+	}
+}
+
+// ListSessions returns up to limit of the user's sessions (without question_responses) after cursor.
+// gameType "" lists all game types.
+func (s *SessionService) ListSessions(ctx context.Context, userID, gameType, cursor string, limit int64) (*SessionPage, error) {
+	if gameType != "" {
+		if err := game.GameType(gameType).Validate(); err != nil {
+			return nil, err
+		}
+	}
+	afterTS, afterID, err := decodeSessionCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch one extra record to know whether another page exists
+	records, err := s.sessionRepo.FindPage(ctx, userID, gameType, afterTS, afterID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	page := &SessionPage{Sessions: records}
+	if int64(len(records)) > limit {
+		page.Sessions = records[:limit]
+		last := page.Sessions[len(page.Sessions)-1]
+		page.NextCursor = encodeSessionCursor(last.Timestamp, last.ID)
+	}
+	return page, nil
+}
+
+// GetSession returns one of the user's sessions including its question_responses.
+func (s *SessionService) GetSession(ctx context.Context, userID, sessionID string) (*entity.SessionRecord, error) {
+	record, err := s.sessionRepo.FindBySessionID(ctx, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, ErrSessionNotFound
+	}
+	return record, nil
+}
+
+// Backfill copies sessions embedded in score documents into the sessions collection.
+// Already stored sessions are left untouched. Returns the number of sessions inserted.
+func (s *SessionService) Backfill(ctx context.Context) (int, error) {
+	scores, err := s.scoreRepo.FindAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+	inserted := 0
+	for _, score := range scores {
+		var records []*entity.SessionRecord
+		for _, gt := range game.AllGameTypes {
+			gts := getGameTypeScore(score, string(gt))
+			if gts == nil {
+				continue
+			}
+			for _, se := range gts.Sessions {
+				records = append(records, newSessionRecord(score, string(gt), se))
+			}
+		}
+		n, err := s.sessionRepo.InsertMissing(ctx, records)
+		if err != nil {
+			return inserted, err
+		}
+		inserted += n
+	}
+	log.Printf("Sessions backfill: inserted %d sessions from %d score documents", inserted, len(scores))
+	return inserted, nil
+}
+
+// newSessionRecord builds the sessions-collection document for a session embedded in score.
+func newSessionRecord(score *entity.Score, gameType string, se entity.Session) *entity.SessionRecord {
+	return &entity.SessionRecord{
+		ID:                bson.NewObjectID().Hex(),
+		SessionID:         se.SessionID,
+		UserID:            score.UserID,
+		TenantID:          score.TenantID,
+		GameType:          gameType,
+		QuestionResponses: toResponseRecords(se.QuestionResponses),
+		SessionScore:      se.SessionScore,
+		Timestamp:         se.Timestamp,
+		Flags:             se.Flags,
+		ReviewStatus:      se.ReviewStatus,
+		SessionTags:       se.SessionTags,
+	}
+}
+
+// toResponseRecords converts question_responses (a []request.QuestionResponse on write, or generic BSON
+// when read back from a score document) into typed records by round-tripping through BSON.
+func toResponseRecords(v interface{}) []entity.ResponseRecord {
+	var out struct {
+		R []entity.ResponseRecord `bson:"r"`
+	}
+	raw, err := bson.Marshal(bson.M{"r": v})
+	if err != nil {
+		return nil
+	}
+	if err := bson.Unmarshal(raw, &out); err != nil {
+		return nil
+	}
+	return out.R
+}
+
+// encodeSessionCursor encodes the position of the last returned session as an opaque cursor.
+func encodeSessionCursor(ts time.Time, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(ts.UnixNano(), 10) + ":" + id))
+}
+
+func decodeSessionCursor(cursor string) (time.Time, string, error) {
+	if cursor == "" {
+		return time.Time{}, "", nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return time.Unix(0, nanos).UTC(), parts[1], nil
+}
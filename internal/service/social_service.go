@@ -0,0 +1,658 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"brainbash_backend/config"
+	"brainbash_backend/internal/game"
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/repository"
+)
+
+const (
+	defaultMaxFriends   = 500
+	defaultMaxFollowing = 1000
+	friendCodeLength    = 8
+	friendCodeAlphabet  = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // no 0/O or 1/I, so codes survive being read aloud
+	friendCodeAttempts  = 5
+)
+
+// Leaderboard windows of the friends and group leaderboards.
+const (
+	WindowAll   = "all"
+	WindowDay   = "day"
+	WindowWeek  = "week"
+	WindowMonth = "month"
+)
+
+var windowDurations = map[string]time.Duration{
+	WindowDay:   24 * time.Hour,
+	WindowWeek:  7 * 24 * time.Hour,
+	WindowMonth: 30 * 24 * time.Hour,
+}
+
+var (
+	// ErrUserNotFound is returned when the target user or friend code does not exist.
+	ErrUserNotFound = errors.New("user not found")
+	// ErrSelfRelation is returned when a user targets themselves.
+	ErrSelfRelation = errors.New("cannot target yourself")
+	// ErrBlocked is returned when either user blocked the other.
+	ErrBlocked = errors.New("user is not available")
+	// ErrAlreadyFriends is returned when requesting an existing friend.
+	ErrAlreadyFriends = errors.New("already friends")
+	// ErrNotFriends is returned when an action needs the users to be friends.
+	ErrNotFriends = errors.New("not friends")
+	// ErrFriendRequestNotFound is returned when there is no matching pending request or friendship.
+	ErrFriendRequestNotFound = errors.New("friend request not found")
+	// ErrSocialLimit is returned when social.max_friends or social.max_following is reached.
+	ErrSocialLimit = errors.New("limit reached")
+	// ErrInvalidWindow is returned for an unknown leaderboard window.
+	ErrInvalidWindow = errors.New("window must be all, day, week or month")
+)
+
+// FriendRequestView is a pending friend request with the other user.
+type FriendRequestView struct {
+	User      *entity.User
+	CreatedAt time.Time
+}
+
+// FriendRequests are the user's pending incoming and outgoing friend requests.
+type FriendRequests struct {
+	Incoming []FriendRequestView
+	Outgoing []FriendRequestView
+}
+
+// Connections are the users related to a user.
+type Connections struct {
+	Friends   []*entity.User
+	Following []*entity.User
+	Followers []*entity.User
+}
+
+// LeaderboardEntry is one user on a friends or group leaderboard.
+type LeaderboardEntry struct {
+	User     *entity.User
+	Score    float64
+	Sessions int // sessions in the window; 0 for the all-time window
+	IsMe     bool
+}
+
+// SocialService manages the social graph: follows, friend requests, blocks and friend codes,
+// and ranks a user against the people they are connected to.
+type SocialService struct {
+	socialRepo *repository.SocialRepository
+	userRepo   *repository.UserRepository
+	scoreRepo  *repository.ScoreRepository
+	cfg        config.SocialConfig
+}
+
+// NewSocialService creates a new SocialService.
+func NewSocialService(socialRepo *repository.SocialRepository, userRepo *repository.UserRepository, scoreRepo *repository.ScoreRepository, cfg config.SocialConfig) *SocialService {
+	if cfg.MaxFriends <= 0 {
+		cfg.MaxFriends = defaultMaxFriends
+	}
+	if cfg.MaxFollowing <= 0 {
+		cfg.MaxFollowing = defaultMaxFollowing
+	}
+	return &SocialService{
+		socialRepo: socialRepo,
+		userRepo:   userRepo,
+		scoreRepo:  scoreRepo,
+		cfg:        cfg,
+	}
+}
+
+// FriendCode returns the user's friend code, generating it on first use, and the invite link built from
+// it ("" when social.invite_url is not configured).
+func (s *SocialService) FriendCode(ctx context.Context, userID string) (code, inviteURL string, err error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return "", "", err
+	}
+	code = user.FriendCode
+	for i := 0; code == "" && i < friendCodeAttempts; i++ {
+		candidate, err := newFriendCode()
+		if err != nil {
+			return "", "", err
+		}
+		taken, err := s.userRepo.SetFriendCode(ctx, user.UserID, candidate)
+		if err != nil {
+			return "", "", err
+		}
+		if taken {
+			continue
+		}
+		// Re-read: a concurrent call may have set a different code first
+		if user, err = s.findUser(ctx, userID); err != nil {
+			return "", "", err
+		}
+		code = user.FriendCode
+	}
+	if code == "" {
+		return "", "", fmt.Errorf("could not allocate a friend code after %d attempts", friendCodeAttempts)
+	}
+	if s.cfg.InviteURL != "" {
+		inviteURL = fmt.Sprintf(s.cfg.InviteURL, code)
+	}
+	return code, inviteURL, nil
+}
+
+// LookupFriendCode returns the user behind a friend code, e.g. to preview an invite link.
+func (s *SocialService) LookupFriendCode(ctx context.Context, userID, code string) (*entity.User, error) {
+	user, err := s.userRepo.FindByFriendCode(ctx, normalizeFriendCode(code))
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	blocked, err := s.socialRepo.IsBlocked(ctx, userID, user.UserID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// SendFriendRequest asks the target (by user id or friend code) to be friends. If the target already sent
+// the user a request, it is accepted instead. Returns the resulting request status.
+func (s *SocialService) SendFriendRequest(ctx context.Context, userID, targetID, friendCode string) (string, error) {
+	if friendCode != "" {
+		target, err := s.LookupFriendCode(ctx, userID, friendCode)
+		if err != nil {
+			return "", err
+		}
+		targetID = target.UserID.Hex()
+	}
+	if err := s.checkTarget(ctx, userID, targetID); err != nil {
+		return "", err
+	}
+
+	if reverse, err := s.socialRepo.FindRequest(ctx, targetID, userID); err != nil {
+		return "", err
+	} else if reverse != nil {
+		if reverse.Status == entity.FriendRequestAccepted {
+			return "", ErrAlreadyFriends
+		}
+		if err := s.AcceptFriendRequest(ctx, userID, targetID); err != nil {
+			return "", err
+		}
+		return entity.FriendRequestAccepted, nil
+	}
+	if err := s.checkFriendLimit(ctx, userID); err != nil {
+		return "", err
+	}
+
+	inserted, err := s.socialRepo.InsertRequest(ctx, &entity.FriendRequest{
+		ID:         entity.PairID(userID, targetID),
+		FromUserID: userID,
+		ToUserID:   targetID,
+		Status:     entity.FriendRequestPending,
+		CreatedAt:  time.Now().UTC(),
+	})
+	if err != nil {
+		return "", err
+	}
+	if !inserted {
+		existing, err := s.socialRepo.FindRequest(ctx, userID, targetID)
+		if err != nil {
+			return "", err
+		}
+		if existing != nil && existing.Status == entity.FriendRequestAccepted {
+			return "", ErrAlreadyFriends
+		}
+	}
+	return entity.FriendRequestPending, nil
+}
+
+// AcceptFriendRequest accepts the pending request fromID sent to userID.
+func (s *SocialService) AcceptFriendRequest(ctx context.Context, userID, fromID string) error {
+	if err := s.checkFriendLimit(ctx, userID); err != nil {
+		return err
+	}
+	ok, err := s.socialRepo.AcceptRequest(ctx, fromID, userID, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrFriendRequestNotFound
+	}
+	return nil
+}
+
+// DeclineFriendRequest deletes the pending request fromID sent to userID. The sender may ask again.
+func (s *SocialService) DeclineFriendRequest(ctx context.Context, userID, fromID string) error {
+	return s.deleteRequest(ctx, fromID, userID, entity.FriendRequestPending)
+}
+
+// CancelFriendRequest withdraws the pending request userID sent to toID.
+func (s *SocialService) CancelFriendRequest(ctx context.Context, userID, toID string) error {
+	return s.deleteRequest(ctx, userID, toID, entity.FriendRequestPending)
+}
+
+// Unfriend ends the friendship between userID and friendID.
+func (s *SocialService) Unfriend(ctx context.Context, userID, friendID string) error {
+	a, err := s.socialRepo.DeleteRequest(ctx, userID, friendID, entity.FriendRequestAccepted)
+	if err != nil {
+		return err
+	}
+	b, err := s.socialRepo.DeleteRequest(ctx, friendID, userID, entity.FriendRequestAccepted)
+	if err != nil {
+		return err
+	}
+	if !a && !b {
+		return ErrFriendRequestNotFound
+	}
+	return nil
+}
+
+// ListFriendRequests returns the user's pending incoming and outgoing requests, oldest first.
+func (s *SocialService) ListFriendRequests(ctx context.Context, userID string) (*FriendRequests, error) {
+	incoming, err := s.socialRepo.FindRequests(ctx, userID, entity.FriendRequestPending, false)
+	if err != nil {
+		return nil, err
+	}
+	outgoing, err := s.socialRepo.FindRequests(ctx, userID, entity.FriendRequestPending, true)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(incoming)+len(outgoing))
+	for _, r := range incoming {
+		ids = append(ids, r.FromUserID)
+	}
+	for _, r := range outgoing {
+		ids = append(ids, r.ToUserID)
+	}
+	users, err := s.usersByID(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &FriendRequests{Incoming: []FriendRequestView{}, Outgoing: []FriendRequestView{}}
+	for _, r := range incoming {
+		if u := users[r.FromUserID]; u != nil {
+			out.Incoming = append(out.Incoming, FriendRequestView{User: u, CreatedAt: r.CreatedAt})
+		}
+	}
+	for _, r := range outgoing {
+		if u := users[r.ToUserID]; u != nil {
+			out.Outgoing = append(out.Outgoing, FriendRequestView{User: u, CreatedAt: r.CreatedAt})
+		}
+	}
+	return out, nil
+}
+
+// Follow makes userID follow targetID. Following is one-way and needs no approval.
+func (s *SocialService) Follow(ctx context.Context, userID, targetID string) error {
+	if err := s.checkTarget(ctx, userID, targetID); err != nil {
+		return err
+	}
+	n, err := s.socialRepo.CountFollowing(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if n >= int64(s.cfg.MaxFollowing) {
+		return fmt.Errorf("%w: following at most %d users", ErrSocialLimit, s.cfg.MaxFollowing)
+	}
+	return s.socialRepo.InsertFollow(ctx, &entity.Follow{
+		ID:         entity.PairID(userID, targetID),
+		FollowerID: userID,
+		FolloweeID: targetID,
+		CreatedAt:  time.Now().UTC(),
+	})
+}
+
+// Unfollow stops userID following targetID.
+func (s *SocialService) Unfollow(ctx context.Context, userID, targetID string) error {
+	return s.socialRepo.DeleteFollow(ctx, userID, targetID)
+}
+
+// Block blocks targetID for userID and removes every relation between the two users.
+// Blocked users cannot follow, request or see each other through friend codes.
+func (s *SocialService) Block(ctx context.Context, userID, targetID string) error {
+	if userID == targetID {
+		return ErrSelfRelation
+	}
+	if _, err := s.findUser(ctx, targetID); err != nil {
+		return err
+	}
+	if err := s.socialRepo.InsertBlock(ctx, &entity.Block{
+		ID:        entity.PairID(userID, targetID),
+		BlockerID: userID,
+		BlockedID: targetID,
+		CreatedAt: time.Now().UTC(),
+	}); err != nil {
+		return err
+	}
+	for _, pair := range [][2]string{{userID, targetID}, {targetID, userID}} {
+		if err := s.socialRepo.DeleteFollow(ctx, pair[0], pair[1]); err != nil {
+			return err
+		}
+		if _, err := s.socialRepo.DeleteRequest(ctx, pair[0], pair[1], ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Unblock lifts userID's block of targetID. Removed relations are not restored.
+func (s *SocialService) Unblock(ctx context.Context, userID, targetID string) error {
+	ok, err := s.socialRepo.DeleteBlock(ctx, userID, targetID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// ListBlocked returns the users userID blocked.
+func (s *SocialService) ListBlocked(ctx context.Context, userID string) ([]*entity.User, error) {
+	ids, err := s.socialRepo.FindBlocked(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.userList(ctx, ids)
+}
+
+// GetConnections returns the user's friends, the users they follow and their followers.
+func (s *SocialService) GetConnections(ctx context.Context, userID string) (*Connections, error) {
+	friendIDs, err := s.socialRepo.FindFriendIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	followingIDs, err := s.socialRepo.FindFollowing(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	followerIDs, err := s.socialRepo.FindFollowers(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &Connections{}
+	if out.Friends, err = s.userList(ctx, friendIDs); err != nil {
+		return nil, err
+	}
+	if out.Following, err = s.userList(ctx, followingIDs); err != nil {
+		return nil, err
+	}
+	if out.Followers, err = s.userList(ctx, followerIDs); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FriendIDs returns the ids of the user's friends and the users they follow, without duplicates.
+func (s *SocialService) FriendIDs(ctx context.Context, userID string) ([]string, error) {
+	friendIDs, err := s.socialRepo.FindFriendIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	followingIDs, err := s.socialRepo.FindFollowing(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ids := append(friendIDs, followingIDs...)
+	slices.Sort(ids)
+	return slices.Compact(ids), nil
+}
+
+// CheckFriend returns nil when friendID is userID's friend and neither blocked the other,
+// otherwise ErrNotFriends or the error of checkTarget.
+func (s *SocialService) CheckFriend(ctx context.Context, userID, friendID string) error {
+	if err := s.checkTarget(ctx, userID, friendID); err != nil {
+		return err
+	}
+	for _, pair := range [][2]string{{userID, friendID}, {friendID, userID}} {
+		req, err := s.socialRepo.FindRequest(ctx, pair[0], pair[1])
+		if err != nil {
+			return err
+		}
+		if req != nil && req.Status == entity.FriendRequestAccepted {
+			return nil
+		}
+	}
+	return ErrNotFriends
+}
+
+// FriendsLeaderboard ranks the user among their friends and the users they follow. With a game type, users
+// are ranked by their best session score in the window (high_score for "all"); without one, by overall_score
+// for "all" or the mean session score across game types in shorter windows. Only leaderboard-eligible
+// sessions count; connections without a score are left out, the user is always included.
+func (s *SocialService) FriendsLeaderboard(ctx context.Context, userID, gameType, window string) ([]LeaderboardEntry, error) {
+	since, windowed, err := leaderboardWindow(window)
+	if err != nil {
+		return nil, err
+	}
+	if gameType != "" {
+		if err := game.GameType(gameType).Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	ids, err := s.FriendIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ids = append(ids, userID)
+	scores, err := s.scoreRepo.FindByUserIDs(ctx, ids, windowed)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.usersByID(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return rankLeaderboard(scores, users, userID, gameType, windowed, since), nil
+}
+
+// leaderboardWindow returns when a leaderboard window starts, and whether it is windowed at all
+// (false for WindowAll, which ranks by stored all-time scores). Returns ErrInvalidWindow for unknown windows.
+func leaderboardWindow(window string) (since time.Time, windowed bool, err error) {
+	if window == "" || window == WindowAll {
+		return time.Time{}, false, nil
+	}
+	span, ok := windowDurations[window]
+	if !ok {
+		return time.Time{}, false, ErrInvalidWindow
+	}
+	return time.Now().UTC().Add(-span), true, nil
+}
+
+// rankLeaderboard ranks users by score, best first. With a game type, users are ranked by their high score
+// on it, otherwise by overall score; windowed leaderboards use the sessions since the window start instead.
+// Users without a score are left out, except userID who is always listed.
+func rankLeaderboard(scores []*entity.Score, users map[string]*entity.User, userID, gameType string, windowed bool, since time.Time) []LeaderboardEntry {
+	byUser := make(map[string]LeaderboardEntry, len(scores))
+	for _, score := range scores {
+		user := users[score.UserID]
+		if user == nil {
+			continue
+		}
+		entry := LeaderboardEntry{User: user, IsMe: score.UserID == userID}
+		switch {
+		case !windowed && gameType == "":
+			entry.Score = score.OverallScore
+		case !windowed:
+			if gts := getGameTypeScore(score, gameType); gts != nil {
+				entry.Score = gts.HighScore
+			}
+		default:
+			entry.Score, entry.Sessions = windowScore(score, gameType, since)
+		}
+		if entry.Score > 0 || entry.IsMe {
+			byUser[score.UserID] = entry
+		}
+	}
+	if _, ok := byUser[userID]; !ok {
+		if me := users[userID]; me != nil {
+			byUser[userID] = LeaderboardEntry{User: me, IsMe: true}
+		}
+	}
+
+	out := make([]LeaderboardEntry, 0, len(byUser))
+	for _, e := range byUser {
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return out[i].User.UserID.Hex() < out[j].User.UserID.Hex()
+	})
+	return out
+}
+
+// windowScore returns the best eligible session score of gameType since the given time, or the mean
+// across all game types when gameType is "", and the number of sessions counted.
+func windowScore(score *entity.Score, gameType string, since time.Time) (float64, int) {
+	var best, sum float64
+	count := 0
+	for _, gt := range game.AllGameTypes {
+		if gameType != "" && string(gt) != gameType {
+			continue
+		}
+		gts := getGameTypeScore(score, string(gt))
+		if gts == nil {
+			continue
+		}
+		for i := range gts.Sessions {
+			se := &gts.Sessions[i]
+			if se.Timestamp.Before(since) || !se.LeaderboardEligible() {
+				continue
+			}
+			count++
+			sum += se.SessionScore.Score
+			best = max(best, se.SessionScore.Score)
+		}
+	}
+	if gameType != "" || count == 0 {
+		return best, count
+	}
+	return sum / float64(count), count
+}
+
+// checkTarget verifies targetID is another existing user who has not blocked (and is not blocked by) userID.
+func (s *SocialService) checkTarget(ctx context.Context, userID, targetID string) error {
+	if userID == targetID {
+		return ErrSelfRelation
+	}
+	if _, err := s.findUser(ctx, targetID); err != nil {
+		return err
+	}
+	blocked, err := s.socialRepo.IsBlocked(ctx, userID, targetID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return ErrBlocked
+	}
+	return nil
+}
+
+func (s *SocialService) checkFriendLimit(ctx context.Context, userID string) error {
+	friends, err := s.socialRepo.FindFriendIDs(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if len(friends) >= s.cfg.MaxFriends {
+		return fmt.Errorf("%w: at most %d friends", ErrSocialLimit, s.cfg.MaxFriends)
+	}
+	return nil
+}
+
+func (s *SocialService) deleteRequest(ctx context.Context, fromID, toID, status string) error {
+	ok, err := s.socialRepo.DeleteRequest(ctx, fromID, toID, status)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrFriendRequestNotFound
+	}
+	return nil
+}
+
+// findUser returns the user or ErrUserNotFound (also for malformed ids).
+func (s *SocialService) findUser(ctx context.Context, userID string) (*entity.User, error) {
+	objID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	user, err := s.userRepo.FindByUserID(ctx, objID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// usersByID loads users keyed by hex id. Malformed and unknown ids are skipped.
+func (s *SocialService) usersByID(ctx context.Context, ids []string) (map[string]*entity.User, error) {
+	objIDs := make([]bson.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if objID, err := bson.ObjectIDFromHex(id); err == nil {
+			objIDs = append(objIDs, objID)
+		}
+	}
+	out := make(map[string]*entity.User, len(objIDs))
+	if len(objIDs) == 0 {
+		return out, nil
+	}
+	users, err := s.userRepo.FindByUserIDs(ctx, objIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		out[u.UserID.Hex()] = u
+	}
+	return out, nil
+}
+
+// userList loads users in the order of ids, skipping unknown ones.
+func (s *SocialService) userList(ctx context.Context, ids []string) ([]*entity.User, error) {
+	users, err := s.usersByID(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*entity.User, 0, len(ids))
+	for _, id := range ids {
+		if u := users[id]; u != nil {
+			out = append(out, u)
+		}
+	}
+	return out, nil
+}
+
+// newFriendCode returns a random friend code.
+func newFriendCode() (string, error) {
+	b := make([]byte, friendCodeLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate friend code: %w", err)
+	}
+	for i := range b {
+		b[i] = friendCodeAlphabet[int(b[i])%len(friendCodeAlphabet)]
+	}
+	return string(b), nil
+}
+
+// normalizeFriendCode uppercases a code and drops separators users may type ("abcd-efgh").
+func normalizeFriendCode(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"brainbash_backend/config"
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/repository"
+)
+
+// StreakStatus is a user's streak as of today in their timezone.
+type StreakStatus struct {
+	Current        int // 0 once missed days exceed the freeze tokens
+	Longest        int
+	LastPlayedDay  string
+	PlayedToday    bool
+	FreezeTokens   int
+	FreezesPending int // missed days the next play will cover with tokens
+	FreezesUsed    int
+	NextFreezeIn   int // streak days until the next token; 0 when tokens are at the maximum
+	Timezone       string
+}
+
+// StreakService tracks consecutive days of play per user. Playing on a new day extends the streak;
+// missed days are covered by freeze tokens, earned every freeze_every streak days, or else reset it.
+type StreakService struct {
+	streakRepo      *repository.StreakRepository
+	userService     *UserService
+	freezeEvery     int
+	maxFreezeTokens int
+}
+
+// NewStreakService creates a new StreakService. freeze_every 0 disables freeze tokens.
+func NewStreakService(streakRepo *repository.StreakRepository, userService *UserService, cfg config.StreakConfig) *StreakService {
+	return &StreakService{
+		streakRepo:      streakRepo,
+		userService:     userService,
+		freezeEvery:     cfg.FreezeEvery,
+		maxFreezeTokens: cfg.MaxFreezeTokens,
+	}
+}
+
+// RecordPlays updates the user's streak for sessions played at the given times and returns it. Days already
+// counted and days before the last counted one (late offline syncs) leave the streak unchanged.
+func (s *StreakService) RecordPlays(ctx context.Context, userID string, playedAt ...time.Time) (*entity.Streak, error) {
+	streak, err := s.streakRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if streak == nil {
+		streak = &entity.Streak{UserID: userID}
+	}
+
+	loc := s.userService.Location(ctx, userID)
+	days := make([]string, len(playedAt))
+	for i, t := range playedAt {
+		days[i] = t.In(loc).Format(entity.StreakDayLayout)
+	}
+	sort.Strings(days)
+
+	before := *streak
+	for _, day := range days {
+		s.apply(streak, day)
+	}
+	if streak.LastPlayedDay == before.LastPlayedDay {
+		return streak, nil
+	}
+	streak.UpdatedAt = time.Now().UTC()
+	if err := s.streakRepo.Upsert(ctx, streak); err != nil {
+		return nil, err
+	}
+	return streak, nil
+}
+
+// GetStreak returns the user's streak as seen today. Nothing is written; tokens for missed days are
+// only spent when the user plays again.
+func (s *StreakService) GetStreak(ctx context.Context, userID string) (*StreakStatus, error) {
+	streak, err := s.streakRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if streak == nil {
+		streak = &entity.Streak{UserID: userID}
+	}
+
+	loc := s.userService.Location(ctx, userID)
+	today := time.Now().In(loc).Format(entity.StreakDayLayout)
+	status := &StreakStatus{
+		Current:       streak.Current,
+		Longest:       streak.Longest,
+		LastPlayedDay: streak.LastPlayedDay,
+		PlayedToday:   streak.LastPlayedDay == today,
+		FreezeTokens:  streak.FreezeTokens,
+		FreezesUsed:   streak.FreezesUsed,
+		Timezone:      loc.String(),
+	}
+	if streak.LastPlayedDay != "" && streak.LastPlayedDay < today {
+		// Yesterday's play keeps the streak alive; every day missed since needs a token
+		missed := daysBetween(streak.LastPlayedDay, today) - 1
+		if missed > streak.FreezeTokens {
+			status.Current = 0
+		} else {
+			status.FreezesPending = missed
+		}
+	}
+	if s.freezeEvery > 0 && status.FreezeTokens < s.maxFreezeTokens {
+		status.NextFreezeIn = s.freezeEvery - status.Current%s.freezeEvery
+	}
+	return status, nil
+}
+
+// apply counts a play on day (YYYY-MM-DD) towards the streak.
+func (s *StreakService) apply(streak *entity.Streak, day string) {
+	if streak.LastPlayedDay != "" && day <= streak.LastPlayedDay {
+		return
+	}
+	switch missed := daysBetween(streak.LastPlayedDay, day) - 1; {
+	case streak.LastPlayedDay == "":
+		streak.Current = 1
+	case missed == 0:
+		streak.Current++
+	case missed <= streak.FreezeTokens:
+		streak.FreezeTokens -= missed
+		streak.FreezesUsed += missed
+		last, _ := time.Parse(entity.StreakDayLayout, streak.LastPlayedDay)
+		for i := 1; i <= missed; i++ {
+			streak.FrozenDays = append(streak.FrozenDays, last.AddDate(0, 0, i).Format(entity.StreakDayLayout))
+		}
+		streak.Current++
+	default:
+		streak.Current = 1
+		streak.FrozenDays = nil
+	}
+	streak.LastPlayedDay = day
+	if streak.Current > streak.Longest {
+		streak.Longest = streak.Current
+	}
+	if s.freezeEvery > 0 && streak.Current%s.freezeEvery == 0 && streak.FreezeTokens < s.maxFreezeTokens {
+		streak.FreezeTokens++
+	}
+}
+
+// daysBetween returns the number of calendar days from one YYYY-MM-DD date to another (0 if either is invalid).
+func daysBetween(from, to string) int {
+	f, err := time.Parse(entity.StreakDayLayout, from)
+	if err != nil {
+		return 0
+	}
+	t, err := time.Parse(entity.StreakDayLayout, to)
+	if err != nil {
+		return 0
+	}
+	return int(t.Sub(f).Hours() / 24)
+}
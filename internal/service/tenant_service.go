@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"brainbash_backend/config"
+	"brainbash_backend/internal/game"
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/repository"
+	"brainbash_backend/internal/tenant"
+	"brainbash_backend/internal/validation"
+)
+
+const (
+	defaultTenantSettingsCacheTTL = 5 * time.Minute
+	tenantSyncOverlap             = 5 * time.Second // re-reads changes saved around the last sync on slower clocks
+	maxTenantNameLength           = 60
+	maxScoringHalfLife            = 10 * 365 * 24 * time.Hour
+	minTenantRetention            = 24 * time.Hour
+)
+
+// ErrTenantNotFound is returned when no tenant has the id.
+var ErrTenantNotFound = errors.New("tenant not found")
+
+// cachedTenant is a tenant with its stored settings applied.
+type cachedTenant struct {
+	tenant   *tenant.Tenant
+	loadedAt time.Time
+}
+
+// TenantService resolves tenants with the settings their admins stored in Mongo applied over the
+// configured ones. Resolved tenants are cached: saving settings invalidates this instance's entry, Sync
+// picks up settings saved on other instances, and entries expire after the cache TTL in any case.
+type TenantService struct {
+	registry     *tenant.Registry
+	settingsRepo *repository.TenantSettingsRepository
+	ttl          time.Duration
+
+	mu       sync.RWMutex
+	cache    map[string]cachedTenant
+	lastSync time.Time
+}
+
+// NewTenantService creates a new TenantService.
+func NewTenantService(registry *tenant.Registry, settingsRepo *repository.TenantSettingsRepository, cfg config.TenantsConfig) *TenantService {
+	ttl := cfg.SettingsCacheTTL
+	if ttl <= 0 {
+		ttl = defaultTenantSettingsCacheTTL
+	}
+	return &TenantService{
+		registry:     registry,
+		settingsRepo: settingsRepo,
+		ttl:          ttl,
+		cache:        make(map[string]cachedTenant),
+		lastSync:     time.Now().UTC(),
+	}
+}
+
+// Get returns the tenant with the id and its settings applied, or nil if there is none.
+func (s *TenantService) Get(ctx context.Context, id string) *tenant.Tenant {
+	base := s.registry.Get(id)
+	if base == nil {
+		return nil
+	}
+	return s.resolve(ctx, base)
+}
+
+// ForHost returns the tenant serving host with its settings applied; unknown hosts get the default tenant.
+func (s *TenantService) ForHost(ctx context.Context, host string) *tenant.Tenant {
+	return s.resolve(ctx, s.registry.ForHost(host))
+}
+
+// All returns every tenant with its settings applied, the default one first.
+func (s *TenantService) All(ctx context.Context) []*tenant.Tenant {
+	bases := s.registry.All()
+	out := make([]*tenant.Tenant, 0, len(bases))
+	for _, base := range bases {
+		out = append(out, s.resolve(ctx, base))
+	}
+	return out
+}
+
+// NewContext returns ctx carrying the tenant with the id stored on a document ("" for the default tenant),
+// for work done on a user's behalf outside their request. Tenants no longer configured leave it unscoped.
+func (s *TenantService) NewContext(ctx context.Context, id string) context.Context {
+	if id == "" {
+		id = tenant.DefaultID
+	}
+	return tenant.NewContext(ctx, s.Get(ctx, id))
+}
+
+// Settings returns the tenant's current settings: those stored by its admins, or the configured ones
+// (with a zero UpdatedAt) if they never saved any.
+func (s *TenantService) Settings(ctx context.Context, id string) (*entity.TenantSettings, error) {
+	base := s.registry.Get(id)
+	if base == nil {
+		return nil, ErrTenantNotFound
+	}
+	settings, err := s.settingsRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		settings = settingsOf(base)
+	}
+	return settings, nil
+}
+
+// UpdateSettings validates and stores the tenant's settings, replacing any saved before, and applies them
+// right away on this instance.
+func (s *TenantService) UpdateSettings(ctx context.Context, id, userID string, in *entity.TenantSettings) (*entity.TenantSettings, error) {
+	if s.registry.Get(id) == nil {
+		return nil, ErrTenantNotFound
+	}
+	settings, err := normalizeTenantSettings(in)
+	if err != nil {
+		return nil, err
+	}
+	settings.TenantID = id
+	settings.UpdatedAt = time.Now().UTC()
+	settings.UpdatedBy = userID
+	if err := s.settingsRepo.Upsert(ctx, settings); err != nil {
+		return nil, err
+	}
+	s.invalidate(id)
+	return settings, nil
+}
+
+// Sync drops cached tenants whose settings changed since the last sync, so settings saved on another
+// instance take effect here. Run periodically.
+func (s *TenantService) Sync(ctx context.Context) error {
+	now := time.Now().UTC()
+	s.mu.RLock()
+	since := s.lastSync.Add(-tenantSyncOverlap)
+	s.mu.RUnlock()
+	ids, err := s.settingsRepo.FindUpdatedSince(ctx, since)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		delete(s.cache, id)
+	}
+	s.lastSync = now
+	return nil
+}
+
+// resolve returns base with its stored settings applied, from the cache when fresh. When the settings
+// cannot be loaded the stale entry, or else base, is used rather than failing the request.
+func (s *TenantService) resolve(ctx context.Context, base *tenant.Tenant) *tenant.Tenant {
+	s.mu.RLock()
+	entry, ok := s.cache[base.ID]
+	s.mu.RUnlock()
+	if ok && time.Since(entry.loadedAt) < s.ttl {
+		return entry.tenant
+	}
+
+	settings, err := s.settingsRepo.FindByID(ctx, base.ID)
+	if err != nil {
+		log.Printf("Tenants: load settings of %s: %v", base.ID, err)
+		if ok {
+			return entry.tenant
+		}
+		return base
+	}
+	t := applySettings(base, settings)
+	s.mu.Lock()
+	s.cache[base.ID] = cachedTenant{tenant: t, loadedAt: time.Now()}
+	s.mu.Unlock()
+	return t
+}
+
+func (s *TenantService) invalidate(id string) {
+	s.mu.Lock()
+	delete(s.cache, id)
+	s.mu.Unlock()
+}
+
+// applySettings returns a copy of base with settings applied; nil settings return base itself.
+func applySettings(base *tenant.Tenant, settings *entity.TenantSettings) *tenant.Tenant {
+	if settings == nil {
+		return base
+	}
+	t := *base
+	t.Name = settings.Name
+	t.GameTypes = settings.GameTypes
+	t.GameLabels = settings.GameLabels
+	t.Scoring = tenant.Scoring{Weights: settings.ScoringWeights, HalfLife: settings.ScoringHalfLife}
+	t.Leaderboards = settings.Leaderboards
+	t.Retention = settings.Retention
+	t.CORSOrigins = settings.CORSOrigins
+	return &t
+}
+
+// settingsOf returns the settings t was configured with.
+func settingsOf(t *tenant.Tenant) *entity.TenantSettings {
+	return &entity.TenantSettings{
+		TenantID:        t.ID,
+		Name:            t.Name,
+		GameTypes:       nonNil(t.GameTypes),
+		GameLabels:      t.GameLabels,
+		ScoringWeights:  t.Scoring.Weights,
+		ScoringHalfLife: t.Scoring.HalfLife,
+		Leaderboards:    t.Leaderboards,
+		Retention:       t.Retention,
+		CORSOrigins:     nonNil(t.CORSOrigins),
+	}
+}
+
+// normalizeTenantSettings validates in and returns a copy with lists sorted and deduplicated.
+func normalizeTenantSettings(in *entity.TenantSettings) (*entity.TenantSettings, error) {
+	name := strings.TrimSpace(in.Name)
+	if name == "" || len([]rune(name)) > maxTenantNameLength {
+		return nil, &validation.Error{Field: "name", Message: fmt.Sprintf("must be 1 to %d characters", maxTenantNameLength)}
+	}
+	for _, gt := range in.GameTypes {
+		if err := game.GameType(gt).Validate(); err != nil {
+			return nil, &validation.Error{Field: "game_types", Message: err.Error()}
+		}
+	}
+	for gt, label := range in.GameLabels {
+		if err := game.GameType(gt).Validate(); err != nil {
+			return nil, &validation.Error{Field: "game_labels", Message: err.Error()}
+		}
+		if strings.TrimSpace(label) == "" {
+			return nil, &validation.Error{Field: "game_labels", Message: fmt.Sprintf("label of %s must not be empty", gt)}
+		}
+	}
+	for gt, w := range in.ScoringWeights {
+		if err := game.GameType(gt).Validate(); err != nil {
+			return nil, &validation.Error{Field: "scoring_weights", Message: err.Error()}
+		}
+		if w < 0 {
+			return nil, &validation.Error{Field: "scoring_weights", Message: fmt.Sprintf("weight of %s must not be negative", gt)}
+		}
+	}
+	if in.ScoringHalfLife < 0 || in.ScoringHalfLife > maxScoringHalfLife {
+		return nil, &validation.Error{Field: "scoring_half_life_days", Message: "must be between 0 and 3650 days"}
+	}
+	if tenant.LeaderboardRank(in.Leaderboards) == 0 && in.Leaderboards != tenant.LeaderboardsOff {
+		return nil, &validation.Error{Field: "leaderboards", Message: "must be public, group or off"}
+	}
+	if in.Retention != 0 && in.Retention < minTenantRetention {
+		return nil, &validation.Error{Field: "retention_days", Message: "must be 0 (keep forever) or at least 1 day"}
+	}
+	for _, origin := range in.CORSOrigins {
+		if !validOrigin(origin) {
+			return nil, &validation.Error{Field: "cors_origins", Message: fmt.Sprintf("%q is not an origin like https://example.com", origin)}
+		}
+	}
+
+	return &entity.TenantSettings{
+		Name:            name,
+		GameTypes:       nonNil(slices.Compact(slices.Sorted(slices.Values(in.GameTypes)))),
+		GameLabels:      maps.Clone(in.GameLabels),
+		ScoringWeights:  maps.Clone(in.ScoringWeights),
+		ScoringHalfLife: in.ScoringHalfLife,
+		Leaderboards:    in.Leaderboards,
+		Retention:       in.Retention,
+		CORSOrigins:     nonNil(slices.Compact(slices.Sorted(slices.Values(in.CORSOrigins)))),
+	}, nil
+}
+
+// validOrigin reports whether origin is a bare http(s) scheme and host, as browsers send in Origin.
+func validOrigin(origin string) bool {
+	u, err := url.Parse(origin)
+	return err == nil && (u.Scheme == "https" || u.Scheme == "http") && u.Host != "" &&
+		u.Path == "" && u.RawQuery == "" && u.Fragment == "" && u.User == nil
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,804 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/bits"
+	"math/rand/v2"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"brainbash_backend/config"
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/model/request"
+	"brainbash_backend/internal/repository"
+	"brainbash_backend/internal/validation"
+)
+
+const (
+	defaultTournamentMaxRounds = 10
+	defaultTournamentMinRound  = 10 * time.Minute
+	tournamentSubmitGrace      = 5 * time.Minute
+	tournamentAdvanceBatch     = 20
+	minTournamentPlayers       = 2
+	tournamentWinPoints        = 1.0
+	tournamentDrawPoints       = 0.5
+	maxSingleEliminationRounds = 16 // 65536 players
+	tournamentResultKeyPrefix  = "tournament:"
+	tournamentNameMaxLength    = 100
+)
+
+var (
+	// ErrTournamentNotFound is returned when the tournament does not exist.
+	ErrTournamentNotFound = errors.New("tournament not found")
+	// ErrTournamentClosed is returned when joining or leaving outside the entry window.
+	ErrTournamentClosed = errors.New("tournament entries are closed")
+	// ErrTournamentFull is returned when a single-elimination bracket has no free place.
+	ErrTournamentFull = errors.New("tournament is full")
+	// ErrTournamentJoined is returned when the user already joined.
+	ErrTournamentJoined = errors.New("already joined the tournament")
+	// ErrTournamentNotJoined is returned when the user is not a player of the tournament.
+	ErrTournamentNotJoined = errors.New("not a player of the tournament")
+	// ErrTournamentNoGame is returned when the user has nothing to play in the current round.
+	ErrTournamentNoGame = errors.New("no game to play this round")
+	// ErrTournamentPlayed is returned when the user already submitted a different session this round.
+	ErrTournamentPlayed = errors.New("round already played")
+	// ErrTournamentFinished is returned when cancelling a tournament that is over.
+	ErrTournamentFinished = errors.New("tournament is over")
+)
+
+// TournamentStanding is one row of a tournament's standings.
+type TournamentStanding struct {
+	Place  int
+	Player *entity.TournamentPlayer
+}
+
+// TournamentService runs admin-created tournaments: players join during the entry window, the server
+// pairs them round by round (round robin or single elimination, seeded by rating), settles each round
+// from the submitted sessions when it closes, and keeps the standings.
+type TournamentService struct {
+	tournamentRepo   *repository.TournamentRepository
+	scoreService     *ScoreService
+	ratingService    *RatingService
+	userService      *UserService
+	tenants          *TenantService
+	maxRounds        int
+	minRoundDuration time.Duration
+}
+
+// NewTournamentService creates a new TournamentService.
+func NewTournamentService(tournamentRepo *repository.TournamentRepository, scoreService *ScoreService, ratingService *RatingService, userService *UserService, tenants *TenantService, cfg config.TournamentsConfig) *TournamentService {
+	if cfg.MaxRounds <= 0 {
+		cfg.MaxRounds = defaultTournamentMaxRounds
+	}
+	if cfg.MinRoundDuration <= 0 {
+		cfg.MinRoundDuration = defaultTournamentMinRound
+	}
+	return &TournamentService{
+		tournamentRepo:   tournamentRepo,
+		scoreService:     scoreService,
+		ratingService:    ratingService,
+		userService:      userService,
+		tenants:          tenants,
+		maxRounds:        min(cfg.MaxRounds, maxSingleEliminationRounds),
+		minRoundDuration: cfg.MinRoundDuration,
+	}
+}
+
+// Create validates and stores a new tournament on behalf of an admin.
+func (s *TournamentService) Create(ctx context.Context, adminID string, req request.CreateTournamentRequest) (*entity.Tournament, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > tournamentNameMaxLength {
+		return nil, &validation.Error{Field: "name", Message: fmt.Sprintf("must be 1 to %d characters", tournamentNameMaxLength)}
+	}
+	if err := ValidateGameType(ctx, req.GameType); err != nil {
+		return nil, err
+	}
+	if req.Format != entity.TournamentRoundRobin && req.Format != entity.TournamentSingleElimination {
+		return nil, &validation.Error{Field: "format", Message: "must be round_robin or single_elimination"}
+	}
+	if req.Rounds < 1 || req.Rounds > s.maxRounds {
+		return nil, &validation.Error{Field: "rounds", Message: fmt.Sprintf("must be between 1 and %d", s.maxRounds)}
+	}
+	roundDuration, err := time.ParseDuration(req.RoundDuration)
+	if err != nil || roundDuration < s.minRoundDuration {
+		return nil, &validation.Error{Field: "round_duration", Message: fmt.Sprintf("must be a duration of at least %v", s.minRoundDuration)}
+	}
+
+	now := time.Now().UTC()
+	opensAt := now
+	if req.EntryOpensAt != nil {
+		opensAt = req.EntryOpensAt.UTC()
+	}
+	closesAt := req.EntryClosesAt.UTC()
+	if !closesAt.After(now) || !closesAt.After(opensAt) {
+		return nil, &validation.Error{Field: "entry_closes_at", Message: "must be in the future and after entry_opens_at"}
+	}
+
+	t := &entity.Tournament{
+		ID:            bson.NewObjectID().Hex(),
+		Name:          name,
+		GameType:      req.GameType,
+		Format:        req.Format,
+		Status:        entity.TournamentRegistration,
+		EntryOpensAt:  opensAt,
+		EntryClosesAt: closesAt,
+		Rounds:        req.Rounds,
+		RoundDuration: roundDuration,
+		CreatedBy:     adminID,
+		CreatedAt:     now,
+	}
+	if t.Format == entity.TournamentSingleElimination {
+		t.MaxPlayers = 1 << t.Rounds
+	}
+	if err := s.tournamentRepo.Insert(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Cancel stops a tournament that has not finished. Sessions already played stay in the players' history.
+func (s *TournamentService) Cancel(ctx context.Context, tournamentID string) (*entity.Tournament, error) {
+	t, err := s.Get(ctx, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	if t.Status != entity.TournamentRegistration && t.Status != entity.TournamentRunning {
+		return nil, ErrTournamentFinished
+	}
+	now := time.Now().UTC()
+	ok, err := s.tournamentRepo.Transition(ctx, t.ID, t.Status, t.CurrentRound, bson.M{"status": entity.TournamentCancelled, "completed_at": now})
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		// Advanced in the meantime; let the admin retry against the new state
+		return nil, ErrTournamentFinished
+	}
+	return s.Get(ctx, tournamentID)
+}
+
+// Get returns the tournament.
+func (s *TournamentService) Get(ctx context.Context, tournamentID string) (*entity.Tournament, error) {
+	t, err := s.tournamentRepo.FindByID(ctx, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, ErrTournamentNotFound
+	}
+	return t, nil
+}
+
+// List returns up to limit tournaments, latest first, optionally restricted to one status.
+func (s *TournamentService) List(ctx context.Context, status string, limit int64) ([]*entity.Tournament, error) {
+	return s.tournamentRepo.Find(ctx, status, limit)
+}
+
+// Join enters the user into a tournament during its entry window.
+func (s *TournamentService) Join(ctx context.Context, userID, tournamentID string) (*entity.TournamentPlayer, error) {
+	t, err := s.openForEntries(ctx, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	if t.MaxPlayers > 0 {
+		// Best effort: concurrent joins may overfill by a few, and start drops the latest of those entries
+		n, err := s.tournamentRepo.CountPlayers(ctx, t.ID)
+		if err != nil {
+			return nil, err
+		}
+		if n >= int64(t.MaxPlayers) {
+			return nil, ErrTournamentFull
+		}
+	}
+	p := &entity.TournamentPlayer{
+		ID:           entity.TournamentPlayerID(t.ID, userID),
+		TournamentID: t.ID,
+		UserID:       userID,
+		JoinedAt:     time.Now().UTC(),
+	}
+	inserted, err := s.tournamentRepo.InsertPlayer(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	if !inserted {
+		return nil, ErrTournamentJoined
+	}
+	return p, nil
+}
+
+// Leave withdraws the user from a tournament before its entries close.
+func (s *TournamentService) Leave(ctx context.Context, userID, tournamentID string) error {
+	t, err := s.openForEntries(ctx, tournamentID)
+	if err != nil {
+		return err
+	}
+	deleted, err := s.tournamentRepo.DeletePlayer(ctx, t.ID, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrTournamentNotJoined
+	}
+	return nil
+}
+
+// Standings returns the tournament's players in standing order. Round robin ranks by points, then wins,
+// then total score; single elimination by how far each player got. Final places are used once completed.
+func (s *TournamentService) Standings(ctx context.Context, tournamentID string) (*entity.Tournament, []TournamentStanding, error) {
+	t, err := s.Get(ctx, tournamentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	players, err := s.tournamentRepo.FindPlayers(ctx, t.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return t, rankTournamentPlayers(t, players), nil
+}
+
+// Pairings returns the pairings of a round, or of every round when round is 0.
+func (s *TournamentService) Pairings(ctx context.Context, tournamentID string, round int) (*entity.Tournament, []*entity.TournamentPairing, error) {
+	t, err := s.Get(ctx, tournamentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if round < 0 || round > t.CurrentRound {
+		return nil, nil, &validation.Error{Field: "round", Message: fmt.Sprintf("must be between 0 and %d", t.CurrentRound)}
+	}
+	pairings, err := s.tournamentRepo.FindPairings(ctx, t.ID, round)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The next round is paired just before it starts
+	for len(pairings) > 0 && pairings[len(pairings)-1].Round > t.CurrentRound {
+		pairings = pairings[:len(pairings)-1]
+	}
+	return t, pairings, nil
+}
+
+// CurrentPairing returns the user's pairing in the running round, with the seed to play it with.
+func (s *TournamentService) CurrentPairing(ctx context.Context, userID, tournamentID string) (*entity.Tournament, *entity.TournamentPairing, error) {
+	t, err := s.Get(ctx, tournamentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if t.Status != entity.TournamentRunning {
+		return nil, nil, ErrTournamentNoGame
+	}
+	pairing, err := s.tournamentRepo.FindPairing(ctx, t.ID, t.CurrentRound, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pairing == nil {
+		return nil, nil, ErrTournamentNoGame
+	}
+	return t, pairing, nil
+}
+
+// Players loads the users in the pairings and standings, keyed by hex id. Users that no longer exist are missing.
+func (s *TournamentService) Players(ctx context.Context, pairings []*entity.TournamentPairing, standings []TournamentStanding) (map[string]*entity.User, error) {
+	var ids []string
+	for _, p := range pairings {
+		ids = append(ids, p.PlayerA)
+		if !p.Bye() {
+			ids = append(ids, p.PlayerB)
+		}
+	}
+	for _, st := range standings {
+		ids = append(ids, st.Player.UserID)
+	}
+	return s.userService.FindByUserIDs(ctx, ids)
+}
+
+// Submit stores the user's session for their game in the current round, scored through the regular
+// pipeline and tagged with the tournament and round. The pairing is settled once both players played.
+func (s *TournamentService) Submit(ctx context.Context, userID, tournamentID string, req request.TournamentResultRequest) (*entity.TournamentPairing, *entity.GameResult, bool, error) {
+	t, pairing, err := s.CurrentPairing(ctx, userID, tournamentID)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	side := pairing.Side(userID)
+	if pairing.Bye() {
+		return nil, nil, false, ErrTournamentNoGame
+	}
+	if result := pairing.Result(side); result != nil {
+		if req.SessionID == "" || result.SessionID != req.SessionID {
+			return nil, nil, false, ErrTournamentPlayed
+		}
+	} else if now := time.Now(); pairing.Status != entity.PairingPending || now.Before(pairing.StartsAt) || now.After(pairing.EndsAt.Add(tournamentSubmitGrace)) {
+		return nil, nil, false, ErrTournamentNoGame
+	}
+
+	// One idempotency key per user and round serialises concurrent submissions and makes retries replay
+	key := fmt.Sprintf("%s%s:%d", tournamentResultKeyPrefix, t.ID, pairing.Round)
+	result, replayed, err := s.scoreService.SubmitTaggedResult(ctx, userID, key, request.GameResultRequest{
+		GameType:          t.GameType,
+		QuestionResponses: req.QuestionResponses,
+		SessionID:         req.SessionID,
+	}, entity.SessionTags{TournamentID: t.ID, Round: pairing.Round})
+	if err != nil {
+		if errors.Is(err, ErrIdempotencyKeyReused) {
+			return nil, nil, false, ErrTournamentPlayed
+		}
+		return nil, nil, false, err
+	}
+
+	updated, err := s.tournamentRepo.SetResult(ctx, pairing.ID, side, &entity.TournamentResult{
+		SessionID:    result.SessionID,
+		SessionScore: result.SessionScore,
+		Flagged:      result.Flagged,
+		SubmittedAt:  time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if updated == nil {
+		// Stored by the original request this one replays, or the round closed in the meantime
+		if updated, err = s.tournamentRepo.FindPairing(ctx, t.ID, pairing.Round, userID); err != nil {
+			return nil, nil, false, err
+		}
+		stored := updated.Result(side)
+		if stored == nil || stored.SessionID != result.SessionID {
+			return nil, nil, false, ErrTournamentNoGame
+		}
+		replayed = true
+	}
+	if updated.Status == entity.PairingPending && updated.A != nil && updated.B != nil {
+		if err := s.settle(ctx, t, updated); err != nil {
+			return nil, nil, false, err
+		}
+		if updated, err = s.tournamentRepo.FindPairing(ctx, t.ID, pairing.Round, userID); err != nil {
+			return nil, nil, false, err
+		}
+	}
+	return updated, result, replayed, nil
+}
+
+// Advance starts tournaments whose entries closed and settles rounds that ended, pairing the next round
+// or completing the tournament. Run periodically.
+func (s *TournamentService) Advance(ctx context.Context) error {
+	due, err := s.tournamentRepo.FindDue(ctx, time.Now().Add(-tournamentSubmitGrace), tournamentAdvanceBatch)
+	if err != nil {
+		return err
+	}
+	for _, t := range due {
+		tctx := s.tenants.NewContext(ctx, t.TenantID)
+		var err error
+		switch t.Status {
+		case entity.TournamentRegistration:
+			err = s.start(tctx, t)
+		case entity.TournamentRunning:
+			err = s.finishRound(tctx, t)
+		}
+		if err != nil {
+			log.Printf("Tournaments: advance %s: %v", t.ID, err)
+		}
+	}
+	return nil
+}
+
+// start seeds the players by rating and pairs the first round, or cancels the tournament when too few joined.
+func (s *TournamentService) start(ctx context.Context, t *entity.Tournament) error {
+	players, err := s.tournamentRepo.FindPlayers(ctx, t.ID)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	if len(players) < minTournamentPlayers {
+		_, err := s.tournamentRepo.Transition(ctx, t.ID, entity.TournamentRegistration, 0, bson.M{"status": entity.TournamentCancelled, "completed_at": now})
+		return err
+	}
+
+	if t.MaxPlayers > 0 && len(players) > t.MaxPlayers {
+		// Joins that overfilled the tournament: the latest entries are dropped, keeping the bracket to Rounds
+		for _, p := range players[t.MaxPlayers:] {
+			if _, err := s.tournamentRepo.DeletePlayer(ctx, t.ID, p.UserID); err != nil {
+				return err
+			}
+		}
+		players = players[:t.MaxPlayers]
+	}
+
+	t.TotalRounds = tournamentRounds(t, len(players))
+	if err := s.seed(ctx, t, players); err != nil {
+		return err
+	}
+	// Pair before starting, so a failed insert is retried on the next run rather than leaving round 1 empty
+	pairings, err := s.pairRound(ctx, t, 1, players, nil)
+	if err != nil {
+		return err
+	}
+
+	roundEndsAt := t.RoundEndsAtFor(1)
+	ok, err := s.tournamentRepo.Transition(ctx, t.ID, entity.TournamentRegistration, 0, bson.M{
+		"status":        entity.TournamentRunning,
+		"total_rounds":  t.TotalRounds,
+		"current_round": 1,
+		"round_ends_at": roundEndsAt,
+		"started_at":    now,
+	})
+	if err != nil || !ok {
+		return err
+	}
+	t.Status, t.CurrentRound, t.RoundEndsAt, t.StartedAt = entity.TournamentRunning, 1, &roundEndsAt, &now
+	return s.settleByes(ctx, t, pairings)
+}
+
+// seed orders players by rating on the tournament's game type, best first, and stores their seeds.
+func (s *TournamentService) seed(ctx context.Context, t *entity.Tournament, players []*entity.TournamentPlayer) error {
+	ids := make([]string, 0, len(players))
+	for _, p := range players {
+		ids = append(ids, p.UserID)
+	}
+	ratings, err := s.ratingService.Ratings(ctx, t.GameType, ids)
+	if err != nil {
+		return err
+	}
+	for _, p := range players {
+		p.Rating = ratings[p.UserID].Rating
+	}
+	// Players joined in order, so earlier entries win ties
+	sort.SliceStable(players, func(i, j int) bool { return players[i].Rating > players[j].Rating })
+	for i, p := range players {
+		p.Seed = i + 1
+		if err := s.tournamentRepo.UpdatePlayer(ctx, t.ID, p.UserID, nil, bson.M{"seed": p.Seed, "rating": p.Rating}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// finishRound settles the current round's open pairings, then pairs the next round or completes the tournament.
+func (s *TournamentService) finishRound(ctx context.Context, t *entity.Tournament) error {
+	round := t.CurrentRound
+	pairings, err := s.tournamentRepo.FindPairings(ctx, t.ID, round)
+	if err != nil {
+		return err
+	}
+	for _, p := range pairings {
+		if p.Status == entity.PairingPending {
+			if err := s.settle(ctx, t, p); err != nil {
+				return err
+			}
+		}
+	}
+
+	now := time.Now().UTC()
+	if round >= t.TotalRounds {
+		ok, err := s.tournamentRepo.Transition(ctx, t.ID, entity.TournamentRunning, round, bson.M{"status": entity.TournamentCompleted, "completed_at": now})
+		if err != nil || !ok {
+			return err
+		}
+		t.Status = entity.TournamentCompleted
+		return s.storePlaces(ctx, t)
+	}
+
+	// Pair the next round before moving to it, so a failed insert is retried on the next run rather than
+	// leaving the round without pairings
+	next := round + 1
+	players, err := s.tournamentRepo.FindPlayers(ctx, t.ID)
+	if err != nil {
+		return err
+	}
+	if pairings, err = s.tournamentRepo.FindPairings(ctx, t.ID, round); err != nil {
+		return err
+	}
+	if pairings, err = s.pairRound(ctx, t, next, players, pairings); err != nil {
+		return err
+	}
+
+	roundEndsAt := t.RoundEndsAtFor(next)
+	ok, err := s.tournamentRepo.Transition(ctx, t.ID, entity.TournamentRunning, round, bson.M{"current_round": next, "round_ends_at": roundEndsAt})
+	if err != nil || !ok {
+		return err
+	}
+	t.CurrentRound, t.RoundEndsAt = next, &roundEndsAt
+	return s.settleByes(ctx, t, pairings)
+}
+
+// pairRound stores and returns the pairings of round. Pairings already stored by an earlier attempt are kept.
+// Round robin pairs players by the circle method over their seeds; single elimination seeds a bracket in
+// round 1 and then pairs the winners of neighbouring tables.
+func (s *TournamentService) pairRound(ctx context.Context, t *entity.Tournament, round int, players []*entity.TournamentPlayer, previous []*entity.TournamentPairing) ([]*entity.TournamentPairing, error) {
+	var pairs [][2]string
+	switch {
+	case t.Format == entity.TournamentRoundRobin:
+		pairs = roundRobinPairs(bySeed(players), round)
+	case round == 1:
+		pairs = bracketPairs(bySeed(players), t.TotalRounds)
+	default:
+		pairs = advancePairs(previous)
+	}
+
+	startsAt, endsAt := t.RoundStartsAt(round), t.RoundEndsAtFor(round)
+	pairings := make([]*entity.TournamentPairing, 0, len(pairs))
+	for table, pair := range pairs {
+		a, b := pair[0], pair[1]
+		if a == "" && b == "" {
+			continue
+		}
+		if a == "" {
+			a, b = b, a
+		}
+		pairings = append(pairings, &entity.TournamentPairing{
+			ID:           entity.TournamentPairingID(t.ID, round, table+1),
+			TournamentID: t.ID,
+			Round:        round,
+			Table:        table + 1,
+			PlayerA:      a,
+			PlayerB:      b,
+			Seed:         rand.Int64(),
+			Status:       entity.PairingPending,
+			StartsAt:     startsAt,
+			EndsAt:       endsAt,
+		})
+	}
+	if err := s.tournamentRepo.InsertPairings(ctx, pairings); err != nil {
+		return nil, err
+	}
+	return pairings, nil
+}
+
+// settleByes settles the byes among pairings straight away. Byes left pending are settled when the round ends.
+func (s *TournamentService) settleByes(ctx context.Context, t *entity.Tournament, pairings []*entity.TournamentPairing) error {
+	for _, p := range pairings {
+		if p.Bye() {
+			if err := s.settle(ctx, t, p); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// settle decides a pairing and updates both players' standings. A bye is a win; a player who did not play
+// loses to one who did, and when neither played both lose. Single-elimination draws go to the earlier
+// submission. Each pairing is settled once.
+func (s *TournamentService) settle(ctx context.Context, t *entity.Tournament, p *entity.TournamentPairing) error {
+	var winnerID string
+	noShow := false
+	switch {
+	case p.Bye():
+		winnerID = p.PlayerA
+	case p.A != nil && p.B != nil:
+		switch compareSessions(p.A.SessionScore, p.A.Flagged, p.B.SessionScore, p.B.Flagged) {
+		case 1:
+			winnerID = p.PlayerA
+		case -1:
+			winnerID = p.PlayerB
+		default:
+			if t.Format == entity.TournamentSingleElimination {
+				winnerID = p.PlayerA
+				if p.B.SubmittedAt.Before(p.A.SubmittedAt) {
+					winnerID = p.PlayerB
+				}
+			}
+		}
+	case p.A != nil:
+		winnerID, noShow = p.PlayerA, true
+	case p.B != nil:
+		winnerID, noShow = p.PlayerB, true
+	default:
+		noShow = true
+	}
+
+	now := time.Now().UTC()
+	set := bson.M{"completed_at": now, "no_show": noShow}
+	if winnerID != "" {
+		set["winner_id"] = winnerID
+	}
+	ok, err := s.tournamentRepo.CompletePairing(ctx, p.ID, set)
+	if err != nil || !ok {
+		return err
+	}
+
+	sides := []string{entity.PairingSideA}
+	if !p.Bye() {
+		sides = append(sides, entity.PairingSideB)
+	}
+	for _, side := range sides {
+		userID := p.PlayerA
+		if side == entity.PairingSideB {
+			userID = p.PlayerB
+		}
+		inc := bson.M{}
+		set := bson.M{}
+		result := p.Result(side)
+		if result != nil {
+			inc["score_total"] = result.SessionScore.Score
+		} else if !p.Bye() {
+			inc["no_shows"] = 1
+		}
+		switch {
+		case winnerID == userID:
+			inc["wins"], inc["points"] = 1, tournamentWinPoints
+		case winnerID == "" && !noShow:
+			inc["draws"], inc["points"] = 1, tournamentDrawPoints
+		default:
+			inc["losses"] = 1
+			if t.Format == entity.TournamentSingleElimination {
+				set["eliminated_round"] = p.Round
+			}
+		}
+		if err := s.tournamentRepo.UpdatePlayer(ctx, t.ID, userID, inc, set); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// storePlaces writes every player's final place.
+func (s *TournamentService) storePlaces(ctx context.Context, t *entity.Tournament) error {
+	players, err := s.tournamentRepo.FindPlayers(ctx, t.ID)
+	if err != nil {
+		return err
+	}
+	for _, st := range rankTournamentPlayers(t, players) {
+		if err := s.tournamentRepo.UpdatePlayer(ctx, t.ID, st.Player.UserID, nil, bson.M{"place": st.Place}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// openForEntries returns the tournament if it is taking entries now.
+func (s *TournamentService) openForEntries(ctx context.Context, tournamentID string) (*entity.Tournament, error) {
+	t, err := s.Get(ctx, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if t.Status != entity.TournamentRegistration || now.Before(t.EntryOpensAt) || !now.Before(t.EntryClosesAt) {
+		return nil, ErrTournamentClosed
+	}
+	return t, nil
+}
+
+// tournamentRounds is how many rounds n players play: up to Rounds for round robin (each pair meets at most
+// once), and enough to leave one winner for single elimination.
+func tournamentRounds(t *entity.Tournament, n int) int {
+	if t.Format == entity.TournamentSingleElimination {
+		return bits.Len(uint(n - 1))
+	}
+	maxRounds := n - 1
+	if n%2 == 1 {
+		maxRounds = n
+	}
+	return min(t.Rounds, maxRounds)
+}
+
+// rankTournamentPlayers orders players by final place once set, otherwise by standing. Players with equal
+// standings share a place.
+func rankTournamentPlayers(t *entity.Tournament, players []*entity.TournamentPlayer) []TournamentStanding {
+	compare := func(a, b *entity.TournamentPlayer) int {
+		if t.Format == entity.TournamentSingleElimination {
+			// Still in (0) ranks above any elimination round; later eliminations rank higher
+			ea, eb := a.EliminatedRound, b.EliminatedRound
+			if ea == 0 {
+				ea = t.TotalRounds + 1
+			}
+			if eb == 0 {
+				eb = t.TotalRounds + 1
+			}
+			if ea != eb {
+				return eb - ea
+			}
+			return cmp.Compare(b.ScoreTotal, a.ScoreTotal)
+		}
+		if c := cmp.Compare(b.Points, a.Points); c != 0 {
+			return c
+		}
+		if a.Wins != b.Wins {
+			return b.Wins - a.Wins
+		}
+		return cmp.Compare(b.ScoreTotal, a.ScoreTotal)
+	}
+	sorted := append([]*entity.TournamentPlayer(nil), players...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Place > 0 && b.Place > 0 && a.Place != b.Place {
+			return a.Place < b.Place
+		}
+		if c := compare(a, b); c != 0 {
+			return c < 0
+		}
+		return seedOrder(a) < seedOrder(b)
+	})
+	out := make([]TournamentStanding, 0, len(sorted))
+	for i, p := range sorted {
+		place := i + 1
+		switch {
+		case p.Place > 0:
+			place = p.Place
+		case i > 0 && compare(sorted[i-1], p) == 0:
+			place = out[i-1].Place
+		}
+		out = append(out, TournamentStanding{Place: place, Player: p})
+	}
+	return out
+}
+
+// bySeed returns the players' ids from top seed down.
+func bySeed(players []*entity.TournamentPlayer) []string {
+	sorted := append([]*entity.TournamentPlayer(nil), players...)
+	sort.SliceStable(sorted, func(i, j int) bool { return seedOrder(sorted[i]) < seedOrder(sorted[j]) })
+	ids := make([]string, 0, len(sorted))
+	for _, p := range sorted {
+		ids = append(ids, p.UserID)
+	}
+	return ids
+}
+
+// seedOrder sorts unseeded players after seeded ones.
+func seedOrder(p *entity.TournamentPlayer) int {
+	if p.Seed == 0 {
+		return int(^uint(0) >> 1)
+	}
+	return p.Seed
+}
+
+// roundRobinPairs pairs ids for round (1-based) by the circle method: the first player stays put and the
+// others rotate one place per round. With an odd count one player per round gets a bye ("").
+func roundRobinPairs(ids []string, round int) [][2]string {
+	if len(ids)%2 == 1 {
+		ids = append(ids, "")
+	}
+	n := len(ids)
+	rest := ids[1:]
+	shift := (round - 1) % len(rest)
+	rotated := append(append([]string{ids[0]}, rest[len(rest)-shift:]...), rest[:len(rest)-shift]...)
+	pairs := make([][2]string, 0, n/2)
+	for i := 0; i < n/2; i++ {
+		pairs = append(pairs, [2]string{rotated[i], rotated[n-1-i]})
+	}
+	return pairs
+}
+
+// bracketPairs places seeded ids into a bracket of 2^rounds slots so the top seeds meet as late as
+// possible; missing seeds are byes ("").
+func bracketPairs(ids []string, rounds int) [][2]string {
+	order := []int{1}
+	for len(order) < 1<<rounds {
+		size := len(order) * 2
+		next := make([]int, 0, size)
+		for _, seed := range order {
+			next = append(next, seed, size+1-seed)
+		}
+		order = next
+	}
+	pairs := make([][2]string, 0, len(order)/2)
+	for i := 0; i < len(order); i += 2 {
+		pairs = append(pairs, [2]string{seedAt(ids, order[i]), seedAt(ids, order[i+1])})
+	}
+	return pairs
+}
+
+func seedAt(ids []string, seed int) string {
+	if seed > len(ids) {
+		return ""
+	}
+	return ids[seed-1]
+}
+
+// advancePairs pairs the winners of neighbouring tables of the previous single-elimination round. A table
+// without a winner gives its neighbour a bye.
+func advancePairs(previous []*entity.TournamentPairing) [][2]string {
+	winners := make(map[int]string, len(previous))
+	tables := 0
+	for _, p := range previous {
+		winners[p.Table] = p.WinnerID
+		tables = max(tables, p.Table)
+	}
+	pairs := make([][2]string, 0, (tables+1)/2)
+	for table := 1; table <= tables; table += 2 {
+		pairs = append(pairs, [2]string{winners[table], winners[table+1]})
+	}
+	return pairs
+}
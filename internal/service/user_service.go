@@ -2,13 +2,46 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 
 	"brainbash_backend/internal/model/entity"
 	"brainbash_backend/internal/repository"
+	"brainbash_backend/internal/validation"
 )
 
+const (
+	minDisplayNameLength = 3
+	maxDisplayNameLength = 30
+	maxAvatarURLLength   = 512
+	maxBioLength         = 280
+	minBirthYear         = 1900
+)
+
+// ErrDisplayNameTaken is returned when another user of the tenant already uses the display name.
+var ErrDisplayNameTaken = errors.New("display name is already taken")
+
+// localePattern matches BCP 47 language tags such as "en", "en-GB" or "zh-Hant-TW".
+var localePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{1,8})*$`)
+
+// ProfileUpdate holds the profile fields to change. Nil fields are left as they are; empty values (0 for
+// BirthYear) clear them.
+type ProfileUpdate struct {
+	DisplayName *string
+	AvatarURL   *string
+	BirthYear   *int
+	Timezone    *string
+	Locale      *string
+	Bio         *string
+}
+
 // UserService handles business logic for user operations.
 type UserService struct {
 	userRepo *repository.UserRepository
@@ -46,3 +79,159 @@ func (s *UserService) FindByUserID(ctx context.Context, userID string) (*entity.
 	}
 	return s.userRepo.FindByUserID(ctx, objID)
 }
+
+// FindByUserIDs loads users keyed by hex id. Malformed and unknown ids are skipped.
+func (s *UserService) FindByUserIDs(ctx context.Context, userIDs []string) (map[string]*entity.User, error) {
+	objIDs := make([]bson.ObjectID, 0, len(userIDs))
+	for _, id := range userIDs {
+		if objID, err := bson.ObjectIDFromHex(id); err == nil {
+			objIDs = append(objIDs, objID)
+		}
+	}
+	out := make(map[string]*entity.User, len(objIDs))
+	if len(objIDs) == 0 {
+		return out, nil
+	}
+	users, err := s.userRepo.FindByUserIDs(ctx, objIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		out[u.UserID.Hex()] = u
+	}
+	return out, nil
+}
+
+// FindBirthYears returns birth_year keyed by user_id for all users who set one.
+func (s *UserService) FindBirthYears(ctx context.Context) (map[string]int, error) {
+	return s.userRepo.FindBirthYears(ctx)
+}
+
+// SetTimezone validates and stores the user's IANA timezone (e.g. "Asia/Kolkata").
+func (s *UserService) SetTimezone(ctx context.Context, userID, timezone string) error {
+	if _, err := time.LoadLocation(timezone); err != nil || timezone == "" {
+		return fmt.Errorf("invalid timezone: %q", timezone)
+	}
+	objID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+	return s.userRepo.SetTimezone(ctx, objID, timezone)
+}
+
+// UpdateProfile validates and applies the changes to the user's profile and returns the updated user.
+// Display names are unique per tenant, ignoring case.
+func (s *UserService) UpdateProfile(ctx context.Context, userID string, in ProfileUpdate) (*entity.User, error) {
+	objID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	set := bson.M{}
+	var unset []string
+	apply := func(field, value string) {
+		if value == "" {
+			unset = append(unset, field)
+		} else {
+			set[field] = value
+		}
+	}
+
+	if in.DisplayName != nil {
+		name, err := normalizeDisplayName(*in.DisplayName)
+		if err != nil {
+			return nil, err
+		}
+		apply("display_name", name)
+		apply("display_name_key", strings.ToLower(name))
+	}
+	if in.AvatarURL != nil {
+		avatar := strings.TrimSpace(*in.AvatarURL)
+		if avatar != "" && !validAvatarURL(avatar) {
+			return nil, &validation.Error{Field: "avatar_url", Message: fmt.Sprintf("must be an https URL of at most %d characters", maxAvatarURLLength)}
+		}
+		apply("avatar_url", avatar)
+	}
+	if in.BirthYear != nil {
+		year := *in.BirthYear
+		if year != 0 && (year < minBirthYear || year > time.Now().UTC().Year()) {
+			return nil, &validation.Error{Field: "birth_year", Message: fmt.Sprintf("must be between %d and this year, or 0 to clear", minBirthYear)}
+		}
+		if year == 0 {
+			unset = append(unset, "birth_year")
+		} else {
+			set["birth_year"] = year
+		}
+	}
+	if in.Timezone != nil {
+		tz := strings.TrimSpace(*in.Timezone)
+		if _, err := time.LoadLocation(tz); tz != "" && err != nil {
+			return nil, &validation.Error{Field: "timezone", Message: fmt.Sprintf("unknown timezone %q", tz)}
+		}
+		apply("timezone", tz)
+	}
+	if in.Locale != nil {
+		locale := strings.TrimSpace(*in.Locale)
+		if locale != "" && !localePattern.MatchString(locale) {
+			return nil, &validation.Error{Field: "locale", Message: "must be a language tag like en or en-GB"}
+		}
+		apply("locale", locale)
+	}
+	if in.Bio != nil {
+		bio := strings.TrimSpace(*in.Bio)
+		if len([]rune(bio)) > maxBioLength {
+			return nil, &validation.Error{Field: "bio", Message: fmt.Sprintf("must be at most %d characters", maxBioLength)}
+		}
+		apply("bio", bio)
+	}
+
+	user, taken, err := s.userRepo.UpdateProfile(ctx, objID, set, unset)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, ErrDisplayNameTaken
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// normalizeDisplayName trims name and collapses runs of spaces, then checks its length and characters:
+// letters, digits, spaces and . _ - only. An empty name clears the display name.
+func normalizeDisplayName(name string) (string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		return "", nil
+	}
+	if n := len([]rune(name)); n < minDisplayNameLength || n > maxDisplayNameLength {
+		return "", &validation.Error{Field: "display_name", Message: fmt.Sprintf("must be %d to %d characters", minDisplayNameLength, maxDisplayNameLength)}
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune(" ._-", r) {
+			return "", &validation.Error{Field: "display_name", Message: "may only contain letters, digits, spaces and . _ -"}
+		}
+	}
+	return name, nil
+}
+
+func validAvatarURL(raw string) bool {
+	if len(raw) > maxAvatarURLLength {
+		return false
+	}
+	u, err := url.Parse(raw)
+	return err == nil && u.Scheme == "https" && u.Host != "" && u.User == nil
+}
+
+// Location returns the user's configured timezone, or UTC when unset or unknown.
+func (s *UserService) Location(ctx context.Context, userID string) *time.Location {
+	user, err := s.FindByUserID(ctx, userID)
+	if err != nil || user == nil || user.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"brainbash_backend/internal/validation"
+)
+
+func TestNormalizeDisplayName(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{name: "empty clears the name", in: "", want: ""},
+		{name: "only spaces clears the name", in: "   \t ", want: ""},
+		{name: "plain", in: "Ada", want: "Ada"},
+		{name: "trimmed", in: "  Ada Lovelace  ", want: "Ada Lovelace"},
+		{name: "runs of spaces collapsed", in: "Ada \t  Lovelace", want: "Ada Lovelace"},
+		{name: "allowed punctuation", in: "a.b_c-d", want: "a.b_c-d"},
+		{name: "non-ASCII letters", in: "Zoë Ñúñez", want: "Zoë Ñúñez"},
+		{name: "digits", in: "player 42", want: "player 42"},
+		{name: "shortest", in: strings.Repeat("a", minDisplayNameLength), want: strings.Repeat("a", minDisplayNameLength)},
+		{name: "longest", in: strings.Repeat("é", maxDisplayNameLength), want: strings.Repeat("é", maxDisplayNameLength)},
+		{name: "too short", in: "ab", wantErr: true},
+		{name: "too short after trimming", in: "  ab  ", wantErr: true},
+		{name: "too long", in: strings.Repeat("a", maxDisplayNameLength+1), wantErr: true},
+		{name: "symbols", in: "ada!", wantErr: true},
+		{name: "markup", in: "<b>ada</b>", wantErr: true},
+		{name: "emoji", in: "ada 🙂", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeDisplayName(tt.in)
+			if tt.wantErr {
+				var vErr *validation.Error
+				if !errors.As(err, &vErr) || vErr.Field != "display_name" {
+					t.Fatalf("normalizeDisplayName(%q) error = %v, want a display_name validation error", tt.in, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("normalizeDisplayName(%q): %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("normalizeDisplayName(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"math"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"brainbash_backend/config"
+	"brainbash_backend/internal/model/entity"
+	"brainbash_backend/internal/repository"
+)
+
+const (
+	defaultLevelBase   = 100
+	defaultLevelGrowth = 1.25
+)
+
+// XPAward is the XP one session added and the user's level afterwards.
+type XPAward struct {
+	Gained    int64
+	Total     int64
+	Level     int
+	LeveledUp bool
+}
+
+// LevelProgress describes where a total XP lies on the level curve.
+type LevelProgress struct {
+	Level        int
+	XP           int64
+	IntoLevel    int64 // XP earned since reaching Level
+	ForNextLevel int64 // XP Level takes in total
+}
+
+// XPService awards XP for sessions and maps accumulated XP to levels.
+type XPService struct {
+	userRepo *repository.UserRepository
+	cfg      config.XPConfig
+}
+
+// NewXPService creates a new XPService.
+func NewXPService(userRepo *repository.UserRepository, cfg config.XPConfig) *XPService {
+	if cfg.LevelBase <= 0 {
+		cfg.LevelBase = defaultLevelBase
+	}
+	if cfg.LevelGrowth < 1 {
+		cfg.LevelGrowth = defaultLevelGrowth
+	}
+	return &XPService{
+		userRepo: userRepo,
+		cfg:      cfg,
+	}
+}
+
+// SessionXP returns the XP a session earns: a flat amount plus effort (questions answered) and score,
+// with bonuses for a new personal best and for the current streak.
+func (s *XPService) SessionXP(score entity.SessionScoreDetail, personalBest bool, streakDays int) int64 {
+	xp := s.cfg.PerSession +
+		int64(math.Round(s.cfg.PerQuestion*float64(score.Questions))) +
+		int64(math.Round(s.cfg.ScoreFactor*score.Score))
+	if personalBest {
+		xp += s.cfg.PersonalBestBonus
+	}
+	streakBonus := s.cfg.StreakBonusPerDay * int64(streakDays)
+	if s.cfg.MaxStreakBonus > 0 && streakBonus > s.cfg.MaxStreakBonus {
+		streakBonus = s.cfg.MaxStreakBonus
+	}
+	return xp + streakBonus
+}
+
+// Award adds the sum of gains to the user's XP in one update and returns one award per gain, as if they
+// had been added in order, so the gain that crossed a level boundary reports LeveledUp.
+func (s *XPService) Award(ctx context.Context, userID string, gains []int64) ([]XPAward, error) {
+	var sum int64
+	for _, g := range gains {
+		sum += g
+	}
+	if sum == 0 {
+		return make([]XPAward, len(gains)), nil
+	}
+	objID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.AddXP(ctx, objID, sum)
+	if err != nil || user == nil {
+		return nil, err
+	}
+
+	awards := make([]XPAward, len(gains))
+	total := user.XP - sum
+	level := s.Progress(total).Level
+	for i, g := range gains {
+		total += g
+		next := s.Progress(total).Level
+		awards[i] = XPAward{Gained: g, Total: total, Level: next, LeveledUp: next > level}
+		level = next
+	}
+	if level != user.Level {
+		if err := s.userRepo.SetLevel(ctx, objID, level); err != nil {
+			return nil, err
+		}
+	}
+	return awards, nil
+}
+
+// Progress returns the level reached with xp and the progress towards the next one.
+func (s *XPService) Progress(xp int64) LevelProgress {
+	level, floor := 1, int64(0)
+	need := s.levelCost(level)
+	for xp >= floor+need {
+		floor += need
+		level++
+		need = s.levelCost(level)
+	}
+	return LevelProgress{Level: level, XP: xp, IntoLevel: xp - floor, ForNextLevel: need}
+}
+
+// levelCost returns the XP needed to go from level to level+1.
+func (s *XPService) levelCost(level int) int64 {
+	return int64(math.Round(float64(s.cfg.LevelBase) * math.Pow(s.cfg.LevelGrowth, float64(level-1))))
+}
@@ -0,0 +1,123 @@
+package tenant
+
+import (
+	"log"
+	"net"
+	"strings"
+
+	"brainbash_backend/config"
+	"brainbash_backend/internal/game"
+)
+
+// Registry resolves tenants by id and by request host.
+type Registry struct {
+	def    *Tenant
+	byID   map[string]*Tenant
+	byHost map[string]*Tenant
+}
+
+// NewRegistry builds the registry from cfg. The default tenant accepts defaultClientIDs and is administered
+// by defaultAdmins; tenants without an id, with a duplicate id, or with unknown game types or leaderboard
+// visibility are skipped.
+func NewRegistry(cfg config.TenantsConfig, defaultClientIDs, defaultAdmins []string) *Registry {
+	r := &Registry{
+		def: &Tenant{
+			ID:              DefaultID,
+			Name:            "BrainBash",
+			GoogleClientIDs: defaultClientIDs,
+			AdminUserIDs:    defaultAdmins,
+			Leaderboards:    LeaderboardsPublic,
+		},
+		byID:   make(map[string]*Tenant),
+		byHost: make(map[string]*Tenant),
+	}
+	r.byID[DefaultID] = r.def
+	for _, tc := range cfg.List {
+		if tc.ID == "" || r.byID[tc.ID] != nil {
+			log.Printf("Tenants: skipping tenant with missing or duplicate id %q", tc.ID)
+			continue
+		}
+		if !validGameTypes(tc.GameTypes) {
+			log.Printf("Tenants: skipping tenant %s: unknown game type in %v", tc.ID, tc.GameTypes)
+			continue
+		}
+		if tc.Leaderboards == "" {
+			tc.Leaderboards = LeaderboardsPublic
+		}
+		if LeaderboardRank(tc.Leaderboards) == 0 && tc.Leaderboards != LeaderboardsOff {
+			log.Printf("Tenants: skipping tenant %s: unknown leaderboard visibility %q", tc.ID, tc.Leaderboards)
+			continue
+		}
+		t := &Tenant{
+			ID:              tc.ID,
+			Name:            tc.Name,
+			Hosts:           tc.Hosts,
+			GoogleClientIDs: splitTrim(tc.GoogleClientIDs),
+			CORSOrigins:     tc.CORSOrigins,
+			GameTypes:       tc.GameTypes,
+			GameLabels:      tc.GameLabels,
+			AdminUserIDs:    splitTrim(tc.AdminUserIDs),
+			Leaderboards:    tc.Leaderboards,
+			Retention:       tc.Retention,
+		}
+		if len(t.GoogleClientIDs) == 0 {
+			t.GoogleClientIDs = defaultClientIDs
+		}
+		r.byID[t.ID] = t
+		for _, host := range t.Hosts {
+			r.byHost[strings.ToLower(host)] = t
+		}
+	}
+	return r
+}
+
+// Default returns the default tenant.
+func (r *Registry) Default() *Tenant {
+	return r.def
+}
+
+// All returns every tenant, the default one first.
+func (r *Registry) All() []*Tenant {
+	out := []*Tenant{r.def}
+	for _, t := range r.byID {
+		if !t.IsDefault() {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// Get returns the tenant with the id, or nil if there is none.
+func (r *Registry) Get(id string) *Tenant {
+	return r.byID[id]
+}
+
+// ForHost returns the tenant serving host (with or without a port), or the default tenant.
+func (r *Registry) ForHost(host string) *Tenant {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if t := r.byHost[strings.ToLower(host)]; t != nil {
+		return t
+	}
+	return r.def
+}
+
+func validGameTypes(gameTypes []string) bool {
+	for _, gt := range gameTypes {
+		if !game.GameType(gt).IsValid() {
+			return false
+		}
+	}
+	return true
+}
+
+func splitTrim(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
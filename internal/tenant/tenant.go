@@ -0,0 +1,105 @@
+package tenant
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"brainbash_backend/internal/game"
+)
+
+// DefaultID is the tenant of requests that resolve to no partner, and of data stored before tenants existed.
+const DefaultID = "default"
+
+// Leaderboard visibilities, from most to least open: public shows every leaderboard, group only the friends
+// and group leaderboards, off none.
+const (
+	LeaderboardsPublic = "public"
+	LeaderboardsGroup  = "group"
+	LeaderboardsOff    = "off"
+)
+
+// LeaderboardRank orders visibilities: a higher rank shows everything a lower one does. Unknown values rank 0.
+func LeaderboardRank(visibility string) int {
+	switch visibility {
+	case LeaderboardsPublic:
+		return 2
+	case LeaderboardsGroup:
+		return 1
+	}
+	return 0
+}
+
+// Tenant is an organisation whose users, scores and leaderboards are isolated from other tenants'.
+type Tenant struct {
+	ID              string
+	Name            string
+	Hosts           []string
+	GoogleClientIDs []string
+	CORSOrigins     []string          // empty for the default tenant, which uses the built-in origins
+	GameTypes       []string          // enabled game types; empty enables all
+	GameLabels      map[string]string // display names overriding game.GameType.Label
+	AdminUserIDs    []string          // users who may change the tenant's settings
+	Leaderboards    string            // leaderboard visibility
+	Retention       time.Duration     // sessions older than this are deleted; 0 keeps them
+	Scoring         Scoring
+}
+
+// Scoring holds the tenant's composite score parameters. Zero values use the profile config.
+type Scoring struct {
+	Weights  map[string]float64 // per game type weight in the composite; missing game types use the default
+	HalfLife time.Duration
+}
+
+// IsDefault reports whether t is the default tenant. Its documents carry no tenant_id.
+func (t *Tenant) IsDefault() bool {
+	return t.ID == DefaultID
+}
+
+// GameTypeEnabled reports whether the tenant's catalog offers gameType.
+func (t *Tenant) GameTypeEnabled(gameType string) bool {
+	return len(t.GameTypes) == 0 || slices.Contains(t.GameTypes, gameType)
+}
+
+// IsAdmin reports whether userID may change the tenant's settings.
+func (t *Tenant) IsAdmin(userID string) bool {
+	return userID != "" && slices.Contains(t.AdminUserIDs, userID)
+}
+
+// ShowsLeaderboards reports whether leaderboards needing the given visibility are shown.
+func (t *Tenant) ShowsLeaderboards(visibility string) bool {
+	return LeaderboardRank(t.Leaderboards) >= LeaderboardRank(visibility)
+}
+
+// Catalog returns the tenant's enabled game types in game.AllGameTypes order.
+func (t *Tenant) Catalog() []game.GameType {
+	out := make([]game.GameType, 0, len(game.AllGameTypes))
+	for _, gt := range game.AllGameTypes {
+		if t.GameTypeEnabled(string(gt)) {
+			out = append(out, gt)
+		}
+	}
+	return out
+}
+
+// Label returns the tenant's display name for gameType.
+func (t *Tenant) Label(gameType game.GameType) string {
+	if l := t.GameLabels[string(gameType)]; l != "" {
+		return l
+	}
+	return gameType.Label()
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying t. Repositories scope their queries to it.
+func NewContext(ctx context.Context, t *Tenant) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// FromContext returns the tenant carried by ctx, or nil for contexts outside a request (background jobs),
+// which are not scoped to a tenant.
+func FromContext(ctx context.Context) *Tenant {
+	t, _ := ctx.Value(contextKey{}).(*Tenant)
+	return t
+}
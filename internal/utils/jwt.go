@@ -11,6 +11,8 @@ import (
 const (
 	// ContextKeyClaims is the key used to store JWT claims in the Gin context.
 	ContextKeyClaims = "claims"
+	// ClaimTenant is the JWT claim holding the id of the tenant the token was issued for.
+	ClaimTenant = "tenant"
 
 	authorizationHeader = "Authorization"
 	bearerPrefix        = "Bearer "
@@ -74,3 +76,12 @@ func GetUserIDFromContext(c *gin.Context) string {
 	}
 	return ""
 }
+
+// GetTenantFromClaims returns the tenant the token was issued for, or "" for tokens issued before
+// tenants existed (which belong to the default tenant).
+func GetTenantFromClaims(claims jwt.MapClaims) string {
+	if id, ok := claims[ClaimTenant].(string); ok {
+		return id
+	}
+	return ""
+}
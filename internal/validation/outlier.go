@@ -0,0 +1,88 @@
+package validation
+
+import (
+	"math"
+
+	"brainbash_backend/config"
+	"brainbash_backend/internal/model/entity"
+)
+
+// Flags attached to sessions that jump implausibly against the user's own history.
+const (
+	FlagScoreOutlier   = "score_outlier"    // score far above the user's usual scores
+	FlagAvgTimeOutlier = "avg_time_outlier" // avgTime far below the user's usual times
+)
+
+var defaultOutlier = config.OutlierConfig{
+	MinHistory:      5,
+	HistoryWindow:   30,
+	ZThreshold:      3.5,
+	MinScoreStdDev:  5,
+	MinAvgTimeRatio: 0.1,
+}
+
+// OutlierDetector compares a new session with the user's previous sessions of the same game type.
+type OutlierDetector struct {
+	cfg config.OutlierConfig
+}
+
+// NewOutlierDetector builds a detector from config. Zero fields fall back to built-in values.
+func NewOutlierDetector(cfg config.OutlierConfig) *OutlierDetector {
+	if cfg.MinHistory == 0 {
+		cfg.MinHistory = defaultOutlier.MinHistory
+	}
+	if cfg.HistoryWindow == 0 {
+		cfg.HistoryWindow = defaultOutlier.HistoryWindow
+	}
+	if cfg.ZThreshold == 0 {
+		cfg.ZThreshold = defaultOutlier.ZThreshold
+	}
+	if cfg.MinScoreStdDev == 0 {
+		cfg.MinScoreStdDev = defaultOutlier.MinScoreStdDev
+	}
+	if cfg.MinAvgTimeRatio == 0 {
+		cfg.MinAvgTimeRatio = defaultOutlier.MinAvgTimeRatio
+	}
+	return &OutlierDetector{cfg: cfg}
+}
+
+// Check returns outlier flags for candidate given the user's history (oldest first).
+// Only leaderboard-eligible sessions form the baseline, so an unreviewed jump cannot raise it.
+// Only improvements are flagged: a much higher score or a much lower avgTime.
+func (d *OutlierDetector) Check(history []entity.Session, candidate entity.SessionScoreDetail) []string {
+	var scores, times []float64
+	for i := len(history) - 1; i >= 0 && len(scores) < d.cfg.HistoryWindow; i-- {
+		if !history[i].LeaderboardEligible() {
+			continue
+		}
+		scores = append(scores, history[i].SessionScore.Score)
+		times = append(times, history[i].SessionScore.AvgTime)
+	}
+	if len(scores) < d.cfg.MinHistory {
+		return nil
+	}
+
+	var flags []string
+	scoreMean, scoreStd := meanStdDev(scores)
+	if z := (candidate.Score - scoreMean) / math.Max(scoreStd, d.cfg.MinScoreStdDev); z > d.cfg.ZThreshold {
+		flags = append(flags, FlagScoreOutlier)
+	}
+	timeMean, timeStd := meanStdDev(times)
+	if floor := timeMean * d.cfg.MinAvgTimeRatio; floor > 0 {
+		if z := (candidate.AvgTime - timeMean) / math.Max(timeStd, floor); z < -d.cfg.ZThreshold {
+			flags = append(flags, FlagAvgTimeOutlier)
+		}
+	}
+	return flags
+}
+
+func meanStdDev(values []float64) (mean, std float64) {
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	for _, v := range values {
+		std += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(std / float64(len(values)))
+}
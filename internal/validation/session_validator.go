@@ -0,0 +1,164 @@
+package validation
+
+import (
+	"fmt"
+	"math"
+
+	"brainbash_backend/config"
+	"brainbash_backend/internal/model/request"
+	"brainbash_backend/internal/scoring"
+)
+
+// Flags attached to sessions that are valid but look implausible.
+const (
+	FlagFastResponses = "fast_responses" // too many responses below the human reaction floor
+	FlagUniformTiming = "uniform_timing" // response times are almost identical (bot-like)
+)
+
+// minResponsesForTimingCheck is the smallest session for which timing uniformity is meaningful.
+const minResponsesForTimingCheck = 5
+
+// defaultLimits is used when validation is not configured at all.
+var defaultLimits = config.GameLimits{
+	MinQuestions:        1,
+	MaxQuestions:        200,
+	MaxTimeTaken:        300,
+	SuspiciousTimeTaken: 0.25,
+	SuspiciousFastRatio: 0.5,
+	MinTimingCV:         0.02,
+}
+
+// Error is returned for question_responses that can never be accepted. Maps to HTTP 400.
+type Error struct {
+	Field   string
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return e.Field + ": " + e.Message
+}
+
+// Verdict is the result of validating an acceptable session.
+// Flags is non-empty when the session is valid but suspicious and must be reviewed.
+type Verdict struct {
+	Flags []string
+}
+
+// Suspicious returns true if the session was flagged.
+func (v *Verdict) Suspicious() bool {
+	return len(v.Flags) > 0
+}
+
+// SessionValidator checks question_responses against per-game limits before scoring.
+type SessionValidator struct {
+	defaults config.GameLimits
+	games    map[string]config.GameLimits
+}
+
+// NewSessionValidator builds a validator from config. Missing default fields fall back to built-in limits.
+func NewSessionValidator(cfg config.ValidationConfig) *SessionValidator {
+	return &SessionValidator{
+		defaults: mergeLimits(defaultLimits, cfg.Default),
+		games:    cfg.Games,
+	}
+}
+
+// LimitsFor returns the effective limits for a game type ("" for the defaults).
+func (v *SessionValidator) LimitsFor(gameType string) config.GameLimits {
+	if override, ok := v.games[gameType]; ok {
+		return mergeLimits(v.defaults, override)
+	}
+	return v.defaults
+}
+
+// Validate rejects impossible input with *Error and flags suspicious-but-valid sessions in the Verdict.
+// strategy decides which outcomes are allowed; gameType selects the limits ("" for the defaults).
+func (v *SessionValidator) Validate(gameType, strategy string, responses []request.QuestionResponse) (*Verdict, error) {
+	limits := v.LimitsFor(gameType)
+
+	n := len(responses)
+	if n < limits.MinQuestions {
+		return nil, &Error{Field: "question_responses", Message: fmt.Sprintf("at least %d responses required, got %d", limits.MinQuestions, n)}
+	}
+	if n > limits.MaxQuestions {
+		return nil, &Error{Field: "question_responses", Message: fmt.Sprintf("at most %d responses allowed, got %d", limits.MaxQuestions, n)}
+	}
+
+	var fast int
+	var sum, sumSq float64
+	for i, r := range responses {
+		field := fmt.Sprintf("question_responses[%d]", i)
+		if math.IsNaN(r.TimeTaken) || math.IsInf(r.TimeTaken, 0) || r.TimeTaken <= 0 {
+			return nil, &Error{Field: field + ".time_taken", Message: "must be greater than 0"}
+		}
+		if r.TimeTaken > limits.MaxTimeTaken {
+			return nil, &Error{Field: field + ".time_taken", Message: fmt.Sprintf("must be at most %g seconds", limits.MaxTimeTaken)}
+		}
+		if err := validateOutcome(strategy, r.Outcome); err != nil {
+			err.Field = field + ".outcome"
+			return nil, err
+		}
+		if r.TimeTaken < limits.SuspiciousTimeTaken {
+			fast++
+		}
+		sum += r.TimeTaken
+		sumSq += r.TimeTaken * r.TimeTaken
+	}
+
+	verdict := &Verdict{}
+	if n > 0 && float64(fast)/float64(n) >= limits.SuspiciousFastRatio {
+		verdict.Flags = append(verdict.Flags, FlagFastResponses)
+	}
+	if n >= minResponsesForTimingCheck {
+		mean := sum / float64(n)
+		variance := math.Max(sumSq/float64(n)-mean*mean, 0)
+		if math.Sqrt(variance)/mean < limits.MinTimingCV {
+			verdict.Flags = append(verdict.Flags, FlagUniformTiming)
+		}
+	}
+	return verdict, nil
+}
+
+// validateOutcome checks the outcome against the strategy: timed_outcome needs one of
+// correct/incorrect/unsolved, sequential_time accepts only an empty outcome or "correct".
+func validateOutcome(strategy, outcome string) *Error {
+	switch strategy {
+	case scoring.StrategySequentialTime:
+		if outcome == "" || outcome == scoring.OutcomeCorrect {
+			return nil
+		}
+		return &Error{Message: fmt.Sprintf("unexpected outcome %q for sequential_time (omit it or use %q)", outcome, scoring.OutcomeCorrect)}
+	default:
+		switch outcome {
+		case scoring.OutcomeCorrect, scoring.OutcomeIncorrect, scoring.OutcomeUnsolved:
+			return nil
+		}
+		return &Error{Message: fmt.Sprintf("unknown outcome %q (allowed: correct, incorrect, unsolved)", outcome)}
+	}
+}
+
+// mergeLimits returns base with every non-zero field of override applied.
+func mergeLimits(base, override config.GameLimits) config.GameLimits {
+	if override.MinQuestions != 0 {
+		base.MinQuestions = override.MinQuestions
+	}
+	if override.MaxQuestions != 0 {
+		base.MaxQuestions = override.MaxQuestions
+	}
+	if override.MaxTimeTaken != 0 {
+		base.MaxTimeTaken = override.MaxTimeTaken
+	}
+	if override.SuspiciousTimeTaken != 0 {
+		base.SuspiciousTimeTaken = override.SuspiciousTimeTaken
+	}
+	if override.SuspiciousFastRatio != 0 {
+		base.SuspiciousFastRatio = override.SuspiciousFastRatio
+	}
+	if override.MinTimingCV != 0 {
+		base.MinTimingCV = override.MinTimingCV
+	}
+	return base
+}